@@ -0,0 +1,257 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Body describes the structure of a configuration body.
+type Body struct {
+	// Attributes of the body, sorted by name.
+	Attributes []*Attribute
+	// Nested blocks of the body, sorted by type name.
+	Blocks []*Block
+}
+
+// Attribute describes an attribute of a configuration body.
+type Attribute struct {
+	Name     string
+	Type     cty.Type
+	Required bool
+	// Whether the value of the attribute is subject to additional
+	// validations besides type checking.
+	Validated bool
+}
+
+// Block describes a type of block nested inside a configuration body.
+type Block struct {
+	TypeName string
+	Labels   []string
+	Nesting  NestingMode
+	Required bool
+
+	// Bounds to the number of occurrences of the block. Only relevant to
+	// blocks that can be repeated. A MaxItems value of 0 means unbounded.
+	MinItems int
+	MaxItems int
+
+	Body *Body
+}
+
+// NestingMode represents the ways a block can be nested inside a body.
+type NestingMode uint8
+
+// Supported nesting modes.
+const (
+	// The block appears at most once.
+	NestingSingle NestingMode = iota
+	// The block can be repeated, and the order of occurrences matters.
+	NestingList
+	// The block can be repeated, and the order of occurrences doesn't matter.
+	NestingSet
+	// The block can be repeated, and each occurrence is identified by its
+	// labels.
+	NestingMap
+)
+
+// String implements fmt.Stringer.
+func (m NestingMode) String() string {
+	switch m {
+	case NestingList:
+		return "list"
+	case NestingSet:
+		return "set"
+	case NestingMap:
+		return "map"
+	default:
+		return "single"
+	}
+}
+
+// Repeatable returns whether blocks nested with this mode can appear more
+// than once.
+func (m NestingMode) Repeatable() bool {
+	return m != NestingSingle
+}
+
+// Attribute returns the attribute with the given name, if it exists.
+func (b *Body) Attribute(name string) *Attribute {
+	for _, a := range b.Attributes {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// Block returns the nested block with the given type name, if it exists.
+func (b *Body) Block(typeName string) *Block {
+	for _, blk := range b.Blocks {
+		if blk.TypeName == typeName {
+			return blk
+		}
+	}
+	return nil
+}
+
+// FromSpec returns a description of the configuration body that can be
+// decoded using the given hcldec.Spec.
+func FromSpec(spec hcldec.Spec) *Body {
+	return fromSpec(spec, nil)
+}
+
+// fromSpec returns a description of the configuration body that can be
+// decoded using the given hcldec.Spec, and collects the labels declared by
+// that spec into the given slice.
+func fromSpec(spec hcldec.Spec, labels *[]labelSpec) *Body {
+	b := &Body{}
+	walkSpec(spec, b, labels)
+
+	sort.Slice(b.Attributes, func(i, j int) bool {
+		return b.Attributes[i].Name < b.Attributes[j].Name
+	})
+	sort.Slice(b.Blocks, func(i, j int) bool {
+		return b.Blocks[i].TypeName < b.Blocks[j].TypeName
+	})
+
+	return b
+}
+
+// walkSpec populates the given Body with the attributes and blocks found while
+// walking the given hcldec.Spec.
+// Labels declared by BlockLabelSpecs belong to the block that encloses the
+// walked spec, and are collected into the given slice.
+func walkSpec(spec hcldec.Spec, b *Body, labels *[]labelSpec) {
+	switch s := spec.(type) {
+	case hcldec.ObjectSpec:
+		for _, child := range s {
+			walkSpec(child, b, labels)
+		}
+	case *hcldec.ObjectSpec:
+		walkSpec(*s, b, labels)
+
+	case hcldec.TupleSpec:
+		for _, child := range s {
+			walkSpec(child, b, labels)
+		}
+	case *hcldec.TupleSpec:
+		walkSpec(*s, b, labels)
+
+	case *hcldec.AttrSpec:
+		b.Attributes = append(b.Attributes, &Attribute{
+			Name:     s.Name,
+			Type:     s.Type,
+			Required: s.Required,
+		})
+
+	case *hcldec.ValidateSpec:
+		nAttrs := len(b.Attributes)
+		walkSpec(s.Wrapped, b, labels)
+		for _, a := range b.Attributes[nAttrs:] {
+			a.Validated = true
+		}
+
+	case *hcldec.DefaultSpec:
+		nAttrs, nBlocks := len(b.Attributes), len(b.Blocks)
+		walkSpec(s.Primary, b, labels)
+		for _, a := range b.Attributes[nAttrs:] {
+			a.Required = false
+		}
+		for _, blk := range b.Blocks[nBlocks:] {
+			blk.Required = false
+		}
+
+	case *hcldec.TransformExprSpec:
+		walkSpec(s.Wrapped, b, labels)
+	case *hcldec.TransformFuncSpec:
+		walkSpec(s.Wrapped, b, labels)
+
+	case *hcldec.BlockSpec:
+		blk := newBlock(s.TypeName, NestingSingle, s.Nested, nil)
+		blk.Required = s.Required
+		b.Blocks = append(b.Blocks, blk)
+
+	case *hcldec.BlockListSpec:
+		blk := newBlock(s.TypeName, NestingList, s.Nested, nil)
+		blk.MinItems, blk.MaxItems = s.MinItems, s.MaxItems
+		blk.Required = s.MinItems > 0
+		b.Blocks = append(b.Blocks, blk)
+
+	case *hcldec.BlockTupleSpec:
+		blk := newBlock(s.TypeName, NestingList, s.Nested, nil)
+		blk.MinItems, blk.MaxItems = s.MinItems, s.MaxItems
+		blk.Required = s.MinItems > 0
+		b.Blocks = append(b.Blocks, blk)
+
+	case *hcldec.BlockSetSpec:
+		blk := newBlock(s.TypeName, NestingSet, s.Nested, nil)
+		blk.MinItems, blk.MaxItems = s.MinItems, s.MaxItems
+		blk.Required = s.MinItems > 0
+		b.Blocks = append(b.Blocks, blk)
+
+	case *hcldec.BlockMapSpec:
+		b.Blocks = append(b.Blocks, newBlock(s.TypeName, NestingMap, s.Nested, s.LabelNames))
+
+	case *hcldec.BlockObjectSpec:
+		b.Blocks = append(b.Blocks, newBlock(s.TypeName, NestingMap, s.Nested, s.LabelNames))
+
+	case *hcldec.BlockAttrsSpec:
+		b.Blocks = append(b.Blocks, &Block{
+			TypeName: s.TypeName,
+			Nesting:  NestingSingle,
+			Required: s.Required,
+			Body:     &Body{},
+		})
+
+	case *hcldec.BlockLabelSpec:
+		if labels != nil {
+			*labels = append(*labels, labelSpec{index: s.Index, name: s.Name})
+		}
+	}
+}
+
+// labelSpec is the description of a block label extracted from a
+// hcldec.BlockLabelSpec.
+type labelSpec struct {
+	index int
+	name  string
+}
+
+// newBlock returns a Block initialized from the given properties.
+func newBlock(typeName string, nesting NestingMode, nested hcldec.Spec, labelNames []string) *Block {
+	blk := &Block{
+		TypeName: typeName,
+		Nesting:  nesting,
+		Labels:   labelNames,
+	}
+
+	var labels []labelSpec
+	blk.Body = fromSpec(nested, &labels)
+
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].index < labels[j].index
+	})
+	for _, l := range labels {
+		blk.Labels = append(blk.Labels, l.name)
+	}
+
+	return blk
+}
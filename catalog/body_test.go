@@ -0,0 +1,192 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+
+	. "til/catalog"
+	"til/lang/k8s"
+)
+
+func TestFromSpec(t *testing.T) {
+	testCases := map[string]struct {
+		spec   hcldec.Spec
+		expect *Body
+	}{
+		"object with attributes and blocks": {
+			spec: &hcldec.ObjectSpec{
+				"url": &hcldec.AttrSpec{
+					Name:     "url",
+					Type:     cty.String,
+					Required: true,
+				},
+				"credentials": &hcldec.AttrSpec{
+					Name: "credentials",
+					Type: k8s.ObjectReferenceCty,
+				},
+				"retries": &hcldec.ValidateSpec{
+					Wrapped: &hcldec.AttrSpec{
+						Name: "retries",
+						Type: cty.Number,
+					},
+				},
+				"route": &hcldec.BlockListSpec{
+					TypeName: "route",
+					Nested: &hcldec.ObjectSpec{
+						"name": &hcldec.BlockLabelSpec{
+							Index: 0,
+							Name:  "name",
+						},
+						"to": &hcldec.AttrSpec{
+							Name:     "to",
+							Type:     k8s.DestinationCty,
+							Required: true,
+						},
+					},
+					MinItems: 1,
+				},
+				"options": &hcldec.BlockSpec{
+					TypeName: "options",
+					Nested: &hcldec.DefaultSpec{
+						Primary: &hcldec.AttrSpec{
+							Name:     "debug",
+							Type:     cty.Bool,
+							Required: true,
+						},
+						Default: &hcldec.LiteralSpec{
+							Value: cty.False,
+						},
+					},
+				},
+			},
+			expect: &Body{
+				Attributes: []*Attribute{{
+					Name: "credentials",
+					Type: k8s.ObjectReferenceCty,
+				}, {
+					Name:      "retries",
+					Type:      cty.Number,
+					Validated: true,
+				}, {
+					Name:     "url",
+					Type:     cty.String,
+					Required: true,
+				}},
+				Blocks: []*Block{{
+					TypeName: "options",
+					Nesting:  NestingSingle,
+					Body: &Body{
+						Attributes: []*Attribute{{
+							Name: "debug",
+							Type: cty.Bool,
+						}},
+					},
+				}, {
+					TypeName: "route",
+					Labels:   []string{"name"},
+					Nesting:  NestingList,
+					Required: true,
+					MinItems: 1,
+					Body: &Body{
+						Attributes: []*Attribute{{
+							Name:     "to",
+							Type:     k8s.DestinationCty,
+							Required: true,
+						}},
+					},
+				}},
+			},
+		},
+		"single set of blocks": {
+			spec: &hcldec.BlockSetSpec{
+				TypeName: "subscriber",
+				Nested: &hcldec.AttrSpec{
+					Name:     "ref",
+					Type:     k8s.DestinationCty,
+					Required: true,
+				},
+			},
+			expect: &Body{
+				Blocks: []*Block{{
+					TypeName: "subscriber",
+					Nesting:  NestingSet,
+					Body: &Body{
+						Attributes: []*Attribute{{
+							Name:     "ref",
+							Type:     k8s.DestinationCty,
+							Required: true,
+						}},
+					},
+				}},
+			},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			b := FromSpec(tc.spec)
+
+			if diff := cmp.Diff(tc.expect, b, cmp.Comparer(ctyTypeEqual)); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+		})
+	}
+}
+
+func TestTypeString(t *testing.T) {
+	testCases := map[string]struct {
+		typ    cty.Type
+		expect string
+	}{
+		"primitive": {
+			typ:    cty.String,
+			expect: "string",
+		},
+		"well-known": {
+			typ:    k8s.SecretKeySelectorCty,
+			expect: TypeSecretKeySelector,
+		},
+		"collection of well-known": {
+			typ:    cty.Set(k8s.DestinationCty),
+			expect: "set(" + TypeDestination + ")",
+		},
+		"object": {
+			typ: cty.Object(map[string]cty.Type{
+				"b": cty.List(cty.Number),
+				"a": cty.DynamicPseudoType,
+			}),
+			expect: "object({a=any, b=list(number)})",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if s := TypeString(tc.typ); s != tc.expect {
+				t.Errorf("Expected %q, got %q", tc.expect, s)
+			}
+		})
+	}
+}
+
+func ctyTypeEqual(a, b cty.Type) bool {
+	return a.Equals(b)
+}
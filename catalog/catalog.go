@@ -0,0 +1,191 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"til/config"
+	"til/lang/k8s"
+	"til/translation"
+
+	"til/internal/components/channels"
+	"til/internal/components/routers"
+	"til/internal/components/sources"
+	"til/internal/components/targets"
+	"til/internal/components/transformers"
+)
+
+// Categories returns all categories of Bridge components, in the order in
+// which they are typically described.
+func Categories() []config.ComponentCategory {
+	return []config.ComponentCategory{
+		config.CategorySources,
+		config.CategoryChannels,
+		config.CategoryRouters,
+		config.CategoryTransformers,
+		config.CategoryTargets,
+	}
+}
+
+// Types returns the sorted names of all component types supported for the
+// given component category.
+func Types(cat config.ComponentCategory) []string {
+	impls := implementations(cat)
+
+	types := make([]string, 0, len(impls))
+	for t := range impls {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	return types
+}
+
+// CategoryAttributes returns the attributes that are common to all component
+// types of the given category, and are interpreted by the language itself
+// rather than by component implementations.
+func CategoryAttributes(cat config.ComponentCategory) []*Attribute {
+	var s *hcl.BodySchema
+
+	switch cat {
+	case config.CategoryChannels:
+		s = config.ChannelBlockSchema
+	case config.CategoryRouters:
+		s = config.RouterBlockSchema
+	case config.CategoryTransformers:
+		s = config.TransformerBlockSchema
+	case config.CategorySources:
+		s = config.SourceBlockSchema
+	case config.CategoryTargets:
+		s = config.TargetBlockSchema
+	default:
+		return nil
+	}
+
+	attrs := make([]*Attribute, 0, len(s.Attributes))
+	for _, a := range s.Attributes {
+		attrs = append(attrs, &Attribute{
+			Name:     a.Name,
+			Type:     categoryAttributeType(a.Name),
+			Required: a.Required,
+		})
+	}
+
+	return attrs
+}
+
+// categoryAttributeType returns the type of the given category attribute.
+func categoryAttributeType(name string) cty.Type {
+	switch name {
+	case config.AttrTo, config.AttrReplyTo:
+		return k8s.DestinationCty
+	case config.AttrDescription, config.AttrOwner:
+		return cty.String
+	case config.AttrEnabled:
+		return cty.Bool
+	default:
+		return cty.DynamicPseudoType
+	}
+}
+
+// ComponentType describes a type of Bridge component.
+type ComponentType struct {
+	Category config.ComponentCategory
+	Name     string
+
+	// Implementation of the component type.
+	Impl interface{}
+
+	// Structure of the configuration body expected by the component type.
+	// Nil if the component type doesn't accept any configuration.
+	Body *Body
+
+	// Whether the component type can receive events from other
+	// components.
+	Addressable bool
+
+	// Kinds of the Kubernetes objects the component type translates to.
+	// Nil if the component type doesn't describe them.
+	Kinds []schema.GroupVersionKind
+}
+
+// Lookup returns a description of the given component type, if this type
+// exists in the given category.
+func Lookup(cat config.ComponentCategory, typ string) (*ComponentType, bool) {
+	impl, ok := implementations(cat)[typ]
+	if !ok {
+		return nil, false
+	}
+
+	cmpType := &ComponentType{
+		Category: cat,
+		Name:     typ,
+		Impl:     impl,
+	}
+
+	if dec, ok := impl.(translation.Decodable); ok {
+		cmpType.Body = FromSpec(dec.Spec())
+	}
+
+	_, cmpType.Addressable = impl.(translation.Addressable)
+
+	if intr, ok := impl.(translation.Introspectable); ok {
+		cmpType.Kinds = intr.Kinds()
+	}
+
+	return cmpType, true
+}
+
+// BlockBody returns the structure of the whole body of a block that declares a
+// component of this type, including the attributes that are common to all
+// component types of its category.
+func (t *ComponentType) BlockBody() *Body {
+	body := &Body{
+		Attributes: CategoryAttributes(t.Category),
+	}
+
+	if t.Body != nil {
+		body.Attributes = append(body.Attributes, t.Body.Attributes...)
+		body.Blocks = t.Body.Blocks
+	}
+
+	return body
+}
+
+// implementations returns the implementations of all component types
+// supported for the given component category.
+func implementations(cat config.ComponentCategory) map[string]interface{} {
+	switch cat {
+	case config.CategoryChannels:
+		return channels.All
+	case config.CategoryRouters:
+		return routers.All
+	case config.CategoryTransformers:
+		return transformers.All
+	case config.CategorySources:
+		return sources.All
+	case config.CategoryTargets:
+		return targets.All
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	. "til/catalog"
+	"til/config/globals"
+	"til/lang/k8s"
+	"til/translation"
+)
+
+func TestLookup(t *testing.T) {
+	for _, cat := range Categories() {
+		types := Types(cat)
+		if len(types) == 0 {
+			t.Errorf("No component type found for category %q", cat)
+		}
+
+		for _, typ := range types {
+			cmpType, ok := Lookup(cat, typ)
+			if !ok {
+				t.Errorf("Lookup failed for listed %s type %q", cat, typ)
+				continue
+			}
+
+			_, isTranslatable := cmpType.Impl.(translation.Translatable)
+			_, isTranslatableV2 := cmpType.Impl.(translation.TranslatableV2)
+			if (isTranslatable || isTranslatableV2) && len(cmpType.Kinds) == 0 {
+				t.Errorf("The %s type %q is translatable but doesn't describe its generated kinds", cat, typ)
+			}
+		}
+
+		if _, ok := Lookup(cat, "does_not_exist"); ok {
+			t.Errorf("Lookup succeeded for non-existing %s type", cat)
+		}
+	}
+}
+
+// Ensures that the kinds described by component types match the kinds of the
+// objects they actually translate to.
+//
+// Each component type is translated with a configuration where all attributes
+// and blocks are set to sample values, with and without global delivery
+// settings. Component types whose translation depends on the value of some
+// attributes are additionally translated with the configuration variants
+// listed in kindsTestConfigVariants.
+func TestKinds(t *testing.T) {
+	for _, cat := range Categories() {
+		for _, typ := range Types(cat) {
+			cmpType, _ := Lookup(cat, typ)
+
+			_, isTranslatable := cmpType.Impl.(translation.Translatable)
+			_, isTranslatableV2 := cmpType.Impl.(translation.TranslatableV2)
+			if !isTranslatable && !isTranslatableV2 {
+				continue
+			}
+
+			variants := kindsTestConfigVariants[cat.String()+"."+typ]
+			if len(variants) == 0 {
+				variants = []map[string]cty.Value{nil}
+			}
+
+			generated := make(map[schema.GroupVersionKind]struct{})
+
+			for _, overrides := range variants {
+				cfg := cty.NullVal(cty.DynamicPseudoType)
+				if dec, ok := cmpType.Impl.(translation.Decodable); ok {
+					cfg = withAttributes(sampleValue(hcldec.ImpliedType(dec.Spec())), overrides)
+				}
+
+				for _, glb := range []globals.Accessor{kindsTestGlobals{}, kindsTestGlobals{withDelivery: true}} {
+					for _, gvk := range translatedKinds(t, cmpType.Impl, cfg, glb) {
+						generated[gvk] = struct{}{}
+
+						if !hasKind(cmpType.Kinds, gvk) {
+							t.Errorf("The %s type %q generates an object of undescribed kind %s", cat, typ, gvk)
+						}
+					}
+				}
+			}
+
+			for _, gvk := range cmpType.Kinds {
+				if _, ok := generated[gvk]; !ok {
+					t.Errorf("The %s type %q describes the kind %s, which it never generates", cat, typ, gvk)
+				}
+			}
+		}
+	}
+}
+
+// kindsTestConfigVariants are the values of attributes of the component types,
+// indexed by category and type, which either are required to translate the
+// component type, or lead to the generation of objects of different kinds.
+var kindsTestConfigVariants = map[string][]map[string]cty.Value{
+	"source.kafka": {{
+		"tls": cty.True,
+	}},
+	"transformer.function": {{
+		"runtime": cty.StringVal("js-otto"),
+	}, {
+		"runtime": cty.StringVal("python"),
+	}},
+	"target.container": {{
+		"env_var": cty.MapVal(map[string]cty.Value{
+			"SOME_VAR": cty.StringVal("some value"),
+		}),
+	}},
+}
+
+// translatedKinds translates the given component implementation, and returns
+// the kinds of the generated objects.
+func translatedKinds(t *testing.T, impl interface{}, cfg cty.Value, glb globals.Accessor) []schema.GroupVersionKind {
+	t.Helper()
+
+	const id = "sample"
+	dst := k8s.NewDestination("serving.knative.dev/v1", "Service", "some-destination")
+
+	var manifests []interface{}
+
+	switch transl := impl.(type) {
+	case translation.TranslatableV2:
+		var diags hcl.Diagnostics
+		manifests, diags = transl.ManifestsV2(&translation.Context{
+			Identifier:       id,
+			Config:           cfg,
+			EventDestination: dst,
+			Globals:          glb,
+		})
+		if diags.HasErrors() {
+			t.Fatal("Failed to translate component:", diags)
+		}
+	case translation.Translatable:
+		manifests = transl.Manifests(id, cfg, dst, glb)
+	}
+
+	kinds := make([]schema.GroupVersionKind, 0, len(manifests))
+	for _, m := range manifests {
+		kinds = append(kinds, m.(*unstructured.Unstructured).GroupVersionKind())
+	}
+
+	return kinds
+}
+
+// hasKind returns whether the given kind is contained in kinds.
+func hasKind(kinds []schema.GroupVersionKind, kind schema.GroupVersionKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// sampleValue returns a non-null value of the given type.
+func sampleValue(ty cty.Type) cty.Value {
+	switch {
+	case ty == cty.String, ty == cty.DynamicPseudoType:
+		return cty.StringVal("sample")
+	case ty == cty.Number:
+		return cty.NumberIntVal(1)
+	case ty == cty.Bool:
+		return cty.True
+	case ty.IsListType():
+		return cty.ListVal([]cty.Value{sampleValue(ty.ElementType())})
+	case ty.IsSetType():
+		return cty.SetVal([]cty.Value{sampleValue(ty.ElementType())})
+	case ty.IsMapType():
+		return cty.MapVal(map[string]cty.Value{"sample": sampleValue(ty.ElementType())})
+	case ty.IsObjectType():
+		attrs := make(map[string]cty.Value, len(ty.AttributeTypes()))
+		for n, attrTy := range ty.AttributeTypes() {
+			attrs[n] = sampleValue(attrTy)
+		}
+		return cty.ObjectVal(attrs)
+	case ty.IsTupleType():
+		elems := make([]cty.Value, 0, len(ty.TupleElementTypes()))
+		for _, elemTy := range ty.TupleElementTypes() {
+			elems = append(elems, sampleValue(elemTy))
+		}
+		return cty.TupleVal(elems)
+	}
+
+	return cty.NullVal(ty)
+}
+
+// withAttributes returns a copy of the given object with the given attributes
+// set.
+func withAttributes(obj cty.Value, attrs map[string]cty.Value) cty.Value {
+	if len(attrs) == 0 {
+		return obj
+	}
+
+	vals := obj.AsValueMap()
+	for n, v := range attrs {
+		vals[n] = v
+	}
+
+	return cty.ObjectVal(vals)
+}
+
+// kindsTestGlobals is a globals.Accessor which optionally exposes global
+// delivery settings.
+type kindsTestGlobals struct {
+	withDelivery bool
+}
+
+var _ globals.Accessor = (*kindsTestGlobals)(nil)
+
+// Delivery implements globals.Accessor.
+func (g kindsTestGlobals) Delivery() *globals.Delivery {
+	if !g.withDelivery {
+		return nil
+	}
+
+	retries := int64(2)
+	return &globals.Delivery{
+		Retries:        &retries,
+		DeadLetterSink: k8s.NewDestination("serving.knative.dev/v1", "Service", "some-dls"),
+	}
+}
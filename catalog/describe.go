@@ -0,0 +1,120 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"til/config"
+)
+
+// Describe writes a description of the given component type to w, in the form
+// of an annotated block skeleton.
+func Describe(w io.Writer, cmpType *ComponentType) {
+	fmt.Fprintf(w, "%s %q %q {\n", cmpType.Category, cmpType.Name, "<"+config.LblID+">")
+
+	writeBody(w, cmpType.BlockBody(), 1)
+
+	fmt.Fprintln(w, "}")
+
+	addressable := "no"
+	if cmpType.Addressable {
+		addressable = "yes"
+	}
+	fmt.Fprintf(w, "\nAddressable: %s\n", addressable)
+
+	if len(cmpType.Kinds) > 0 {
+		fmt.Fprintln(w, "\nGenerated Kubernetes kinds:")
+		for _, gvk := range cmpType.Kinds {
+			fmt.Fprintf(w, "    %s (%s)\n", gvk.Kind, gvk.GroupVersion())
+		}
+	}
+}
+
+// writeBody writes the attributes and nested blocks of the given Body to w,
+// using the given level of indentation.
+func writeBody(w io.Writer, b *Body, indentLvl int) {
+	indent := strings.Repeat("  ", indentLvl)
+
+	var nameWidth, typeWidth int
+	for _, a := range b.Attributes {
+		if l := len(a.Name); l > nameWidth {
+			nameWidth = l
+		}
+		if l := len(TypeString(a.Type)); l > typeWidth {
+			typeWidth = l
+		}
+	}
+
+	for _, a := range b.Attributes {
+		fmt.Fprintf(w, "%s%-*s = %-*s  # %s\n", indent,
+			nameWidth, a.Name, typeWidth, TypeString(a.Type), AttributeAnnotation(a))
+	}
+
+	for _, blk := range b.Blocks {
+		var hdr strings.Builder
+		hdr.WriteString(blk.TypeName)
+		for _, l := range blk.Labels {
+			hdr.WriteString(" " + strconv.Quote("<"+l+">"))
+		}
+
+		fmt.Fprintf(w, "%s%s {  # %s\n", indent, hdr.String(), BlockAnnotation(blk))
+		writeBody(w, blk.Body, indentLvl+1)
+		fmt.Fprintf(w, "%s}\n", indent)
+	}
+}
+
+// AttributeAnnotation returns a short description of the constraints that
+// apply to the given attribute.
+func AttributeAnnotation(a *Attribute) string {
+	annot := "optional"
+	if a.Required {
+		annot = "required"
+	}
+
+	if a.Validated {
+		annot += ", validated"
+	}
+
+	return annot
+}
+
+// BlockAnnotation returns a short description of the constraints that apply
+// to the given block.
+func BlockAnnotation(blk *Block) string {
+	annot := "optional"
+	if blk.Required {
+		annot = "required"
+	}
+
+	if !blk.Nesting.Repeatable() {
+		return annot
+	}
+
+	annot += ", repeatable"
+	if blk.MinItems > 1 {
+		annot += ", min " + strconv.Itoa(blk.MinItems)
+	}
+	if blk.MaxItems > 0 {
+		annot += ", max " + strconv.Itoa(blk.MaxItems)
+	}
+
+	return annot
+}
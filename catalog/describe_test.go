@@ -0,0 +1,53 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog_test
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	. "til/catalog"
+)
+
+func TestAttributeAnnotation(t *testing.T) {
+	testCases := map[string]struct {
+		attr        *Attribute
+		expectAnnot string
+	}{
+		"optional": {
+			attr:        &Attribute{Name: "a", Type: cty.String},
+			expectAnnot: "optional",
+		},
+		"required": {
+			attr:        &Attribute{Name: "a", Type: cty.String, Required: true},
+			expectAnnot: "required",
+		},
+		"required and validated": {
+			attr:        &Attribute{Name: "a", Type: cty.String, Required: true, Validated: true},
+			expectAnnot: "required, validated",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if annot := AttributeAnnotation(tc.attr); annot != tc.expectAnnot {
+				t.Errorf("Expected annotation %q, got %q", tc.expectAnnot, annot)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema
+
+import (
+	"github.com/zclconf/go-cty/cty"
+
+	"til/catalog"
+	"til/lang/k8s"
+)
+
+// Draft is the version of the JSON Schema specification that generated
+// documents conform to.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document, or a subschema of such document.
+//
+// Only the keywords that are relevant to the description of configuration
+// bodies are represented.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type string `json:"type,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+
+	Items       *Schema   `json:"items,omitempty"`
+	PrefixItems []*Schema `json:"prefixItems,omitempty"`
+	MinItems    int       `json:"minItems,omitempty"`
+	MaxItems    int       `json:"maxItems,omitempty"`
+	UniqueItems bool      `json:"uniqueItems,omitempty"`
+
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+
+	// Extension keywords.
+	Category      string  `json:"x-til-category,omitempty"`
+	ComponentType string  `json:"x-til-type,omitempty"`
+	Addressable   *bool   `json:"x-til-addressable,omitempty"`
+	Kinds         []*Kind `json:"x-til-kinds,omitempty"`
+}
+
+// Kind identifies a kind of Kubernetes object.
+type Kind struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+// ForComponentType returns a JSON Schema document that describes the
+// configuration body of the given component type.
+func ForComponentType(cmpType *catalog.ComponentType) *Schema {
+	s := componentTypeSchema(cmpType)
+	s.Schema = Draft
+	s.Defs = namedDefinitions()
+
+	return s
+}
+
+// ForComponentTypes returns a JSON Schema document that describes the
+// configuration bodies of all the given component types. Each component type
+// is represented by a definition named "<category>.<type>".
+func ForComponentTypes(cmpTypes []*catalog.ComponentType) *Schema {
+	s := &Schema{
+		Schema: Draft,
+		Title:  "TriggerMesh Integration Language components",
+		Defs:   namedDefinitions(),
+	}
+
+	for _, ct := range cmpTypes {
+		s.Defs[DefinitionName(ct)] = componentTypeSchema(ct)
+	}
+
+	return s
+}
+
+// DefinitionName returns the name of the definition that represents the
+// given component type in documents returned by ForComponentTypes.
+func DefinitionName(cmpType *catalog.ComponentType) string {
+	return cmpType.Category.String() + "." + cmpType.Name
+}
+
+// componentTypeSchema returns a schema that describes the configuration body
+// of the given component type.
+func componentTypeSchema(cmpType *catalog.ComponentType) *Schema {
+	s := bodySchema(cmpType.BlockBody())
+	s.Title = cmpType.Category.String() + " " + cmpType.Name
+	s.Category = cmpType.Category.String()
+	s.ComponentType = cmpType.Name
+
+	addressable := cmpType.Addressable
+	s.Addressable = &addressable
+
+	for _, gvk := range cmpType.Kinds {
+		apiVersion, kind := gvk.ToAPIVersionAndKind()
+		s.Kinds = append(s.Kinds, &Kind{
+			APIVersion: apiVersion,
+			Kind:       kind,
+		})
+	}
+
+	return s
+}
+
+// bodySchema returns a schema that describes the given configuration body.
+func bodySchema(b *catalog.Body) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema, len(b.Attributes)+len(b.Blocks)),
+		AdditionalProperties: false,
+	}
+
+	for _, a := range b.Attributes {
+		s.Properties[a.Name] = typeSchema(a.Type)
+		if a.Required {
+			s.Required = append(s.Required, a.Name)
+		}
+	}
+
+	for _, blk := range b.Blocks {
+		s.Properties[blk.TypeName] = blockSchema(blk)
+		if blk.Required {
+			s.Required = append(s.Required, blk.TypeName)
+		}
+	}
+
+	return s
+}
+
+// blockSchema returns a schema that describes the given nested block, as it
+// would be represented in HCL's JSON syntax.
+func blockSchema(blk *catalog.Block) *Schema {
+	s := bodySchema(blk.Body)
+
+	if blk.Nesting == catalog.NestingList || blk.Nesting == catalog.NestingSet {
+		s = &Schema{
+			Type:        "array",
+			Items:       s,
+			MinItems:    blk.MinItems,
+			MaxItems:    blk.MaxItems,
+			UniqueItems: blk.Nesting == catalog.NestingSet,
+		}
+	}
+
+	// labeled blocks are represented as nested objects, one level per label
+	for i := len(blk.Labels) - 1; i >= 0; i-- {
+		s = &Schema{
+			Type:                 "object",
+			AdditionalProperties: s,
+		}
+	}
+
+	return s
+}
+
+// typeSchema returns a schema that describes values of the given cty.Type.
+func typeSchema(t cty.Type) *Schema {
+	if name, ok := catalog.NamedType(t); ok {
+		return &Schema{Ref: "#/$defs/" + name}
+	}
+	return structuralTypeSchema(t)
+}
+
+// structuralTypeSchema returns a schema that describes values of the given
+// cty.Type, without substituting well-known types with references to their
+// definitions at the top level.
+func structuralTypeSchema(t cty.Type) *Schema {
+	switch {
+	case t == cty.String:
+		return &Schema{Type: "string"}
+	case t == cty.Number:
+		return &Schema{Type: "number"}
+	case t == cty.Bool:
+		return &Schema{Type: "boolean"}
+
+	case t.IsListType():
+		return &Schema{
+			Type:  "array",
+			Items: typeSchema(t.ElementType()),
+		}
+	case t.IsSetType():
+		return &Schema{
+			Type:        "array",
+			Items:       typeSchema(t.ElementType()),
+			UniqueItems: true,
+		}
+	case t.IsTupleType():
+		s := &Schema{Type: "array"}
+		for _, et := range t.TupleElementTypes() {
+			s.PrefixItems = append(s.PrefixItems, typeSchema(et))
+		}
+		s.MinItems = len(s.PrefixItems)
+		s.MaxItems = len(s.PrefixItems)
+		return s
+
+	case t.IsMapType():
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: typeSchema(t.ElementType()),
+		}
+	case t.IsObjectType():
+		attrTypes := t.AttributeTypes()
+		s := &Schema{
+			Type:                 "object",
+			Properties:           make(map[string]*Schema, len(attrTypes)),
+			AdditionalProperties: false,
+		}
+		for n, at := range attrTypes {
+			s.Properties[n] = typeSchema(at)
+		}
+		return s
+
+	default:
+		// cty.DynamicPseudoType, or any type that doesn't have a JSON
+		// equivalent, accepts any value
+		return &Schema{}
+	}
+}
+
+// namedDefinitions returns definitions for all well-known types.
+func namedDefinitions() map[string]*Schema {
+	return map[string]*Schema{
+		catalog.TypeDestination:       namedDefinition(k8s.DestinationCty, "A Knative \"duck\" Destination."),
+		catalog.TypeObjectReference:   namedDefinition(k8s.ObjectReferenceCty, "A reference to a Kubernetes object by name."),
+		catalog.TypeSecretKeySelector: namedDefinition(k8s.SecretKeySelectorCty, "A reference to a key of a Kubernetes Secret."),
+	}
+}
+
+// namedDefinition returns the definition of a well-known type.
+func namedDefinition(t cty.Type, desc string) *Schema {
+	s := structuralTypeSchema(t)
+	s.Description = desc
+	return s
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"til/catalog"
+	. "til/catalog/jsonschema"
+	"til/config"
+	"til/lang/k8s"
+)
+
+func TestForComponentType(t *testing.T) {
+	cmpType := &catalog.ComponentType{
+		Category: config.CategoryTargets,
+		Name:     "test",
+		Body: &catalog.Body{
+			Attributes: []*catalog.Attribute{{
+				Name:     "credentials",
+				Type:     k8s.ObjectReferenceCty,
+				Required: true,
+			}, {
+				Name: "tags",
+				Type: cty.Map(cty.String),
+			}},
+			Blocks: []*catalog.Block{{
+				TypeName: "header",
+				Labels:   []string{"name"},
+				Nesting:  catalog.NestingMap,
+				Body: &catalog.Body{
+					Attributes: []*catalog.Attribute{{
+						Name:     "value",
+						Type:     cty.List(cty.Number),
+						Required: true,
+					}},
+				},
+			}},
+		},
+		Addressable: true,
+		Kinds: []schema.GroupVersionKind{
+			{Group: "test.io", Version: "v0", Kind: "Test"},
+		},
+	}
+
+	s := ForComponentType(cmpType)
+	s.Defs = nil // well-known definitions are tested separately
+
+	out, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		t.Fatal("Error serializing schema:", err)
+	}
+
+	const expect = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "target test",
+  "type": "object",
+  "properties": {
+    "credentials": {
+      "$ref": "#/$defs/object_reference"
+    },
+    "description": {
+      "type": "string"
+    },
+    "enabled": {
+      "type": "boolean"
+    },
+    "header": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "object",
+        "properties": {
+          "value": {
+            "type": "array",
+            "items": {
+              "type": "number"
+            }
+          }
+        },
+        "additionalProperties": false,
+        "required": [
+          "value"
+        ]
+      }
+    },
+    "owner": {
+      "type": "string"
+    },
+    "reply_to": {
+      "$ref": "#/$defs/destination"
+    },
+    "tags": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "string"
+      }
+    }
+  },
+  "additionalProperties": false,
+  "required": [
+    "credentials"
+  ],
+  "x-til-category": "target",
+  "x-til-type": "test",
+  "x-til-addressable": true,
+  "x-til-kinds": [
+    {
+      "apiVersion": "test.io/v0",
+      "kind": "Test"
+    }
+  ]
+}`
+
+	if diff := cmp.Diff(expect, string(out)); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+func TestWellKnownDefinitions(t *testing.T) {
+	s := ForComponentTypes(nil)
+
+	for _, name := range []string{
+		catalog.TypeDestination,
+		catalog.TypeObjectReference,
+		catalog.TypeSecretKeySelector,
+	} {
+		def, ok := s.Defs[name]
+		if !ok {
+			t.Errorf("Missing definition for well-known type %q", name)
+			continue
+		}
+		if def.Type != "object" {
+			t.Errorf("Expected definition of %q to be of type object, got %q", name, def.Type)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package catalog
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"til/lang/k8s"
+)
+
+// Names of well-known non-primitive types. Values of those types are usually
+// not written literally, but obtained by referencing other components or by
+// calling functions of the language (e.g. "secret_name").
+const (
+	TypeDestination       = "destination"
+	TypeObjectReference   = "object_reference"
+	TypeSecretKeySelector = "secret_key_selector"
+)
+
+// NamedType returns the name of the given type if it is a well-known type.
+func NamedType(t cty.Type) (string, bool) {
+	switch {
+	case t.Equals(k8s.DestinationCty):
+		return TypeDestination, true
+	case t.Equals(k8s.ObjectReferenceCty):
+		return TypeObjectReference, true
+	case t.Equals(k8s.SecretKeySelectorCty):
+		return TypeSecretKeySelector, true
+	default:
+		return "", false
+	}
+}
+
+// TypeString returns a representation of the given type that resembles HCL's
+// type constraints syntax. Well-known types are represented by their name.
+func TypeString(t cty.Type) string {
+	if name, ok := NamedType(t); ok {
+		return name
+	}
+
+	switch {
+	case t == cty.DynamicPseudoType:
+		return "any"
+	case t.IsPrimitiveType():
+		return t.FriendlyNameForConstraint()
+	case t.IsListType():
+		return "list(" + TypeString(t.ElementType()) + ")"
+	case t.IsSetType():
+		return "set(" + TypeString(t.ElementType()) + ")"
+	case t.IsMapType():
+		return "map(" + TypeString(t.ElementType()) + ")"
+	case t.IsTupleType():
+		elems := t.TupleElementTypes()
+		strs := make([]string, len(elems))
+		for i, et := range elems {
+			strs[i] = TypeString(et)
+		}
+		return "tuple([" + strings.Join(strs, ", ") + "])"
+	case t.IsObjectType():
+		attrTypes := t.AttributeTypes()
+		names := make([]string, 0, len(attrTypes))
+		for n := range attrTypes {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+
+		strs := make([]string, len(names))
+		for i, n := range names {
+			strs[i] = n + "=" + TypeString(attrTypes[n])
+		}
+		return "object({" + strings.Join(strs, ", ") + "})"
+	default:
+		return t.FriendlyNameForConstraint()
+	}
+}
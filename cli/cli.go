@@ -40,7 +40,7 @@ type CLI struct {
 	commands map[string]Command
 
 	// UI for reporting messages/errors to the terminal.
-	// Defaulted to stdout/stderr during initialization.
+	// Defaulted to stdin/stdout/stderr during initialization.
 	ui UI
 }
 
@@ -59,6 +59,7 @@ func New(osArgs []string, usage usageFn, opts ...Option) *CLI {
 		usage:    usage(osArgs[0]),
 		commands: make(map[string]Command),
 		ui: UI{
+			StdReader: os.Stdin,
 			StdWriter: os.Stdout,
 			ErrWriter: os.Stderr,
 		},
@@ -118,6 +119,13 @@ func Subcommand(name string, cmd Command) Option {
 	}
 }
 
+// StdReader sets the reader from which input is read.
+func StdReader(r io.Reader) Option {
+	return func(c *CLI) {
+		c.ui.StdReader = r
+	}
+}
+
 // StdWriter sets the writer to which regular messages are written.
 func StdWriter(w io.Writer) Option {
 	return func(c *CLI) {
@@ -133,8 +141,10 @@ func ErrWriter(w io.Writer) Option {
 }
 
 // UI wraps io.Writer interfaces used by commands to report messages/errors to
-// the terminal.
+// the terminal, and the io.Reader interface used by commands which consume
+// input from the terminal.
 type UI struct {
+	StdReader io.Reader
 	StdWriter io.Writer
 	ErrWriter io.Writer
 }
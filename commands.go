@@ -21,15 +21,27 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"io"
+	"os"
+	"sort"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"til/cli"
+	"til/config"
 	"til/config/file"
 	"til/core"
+	"til/diagnostics"
 	"til/encoding"
+	"til/graph"
 	"til/graph/dot"
+	"til/graph/jsongraph"
+	"til/graph/mermaid"
+	"til/graph/plantuml"
+	"til/graph/text"
+	"til/policy"
+	"til/tiltest"
 )
 
 // CLI subcommands
@@ -37,6 +49,17 @@ const (
 	cmdGenerate = "generate"
 	cmdValidate = "validate"
 	cmdGraph    = "graph"
+	cmdExplain  = "explain"
+	cmdSchema   = "schema"
+	cmdLSP      = "lsp"
+	cmdImport   = "import"
+	cmdSimulate = "simulate"
+	cmdTest     = "test"
+	cmdDocs     = "docs"
+	cmdSecrets  = "secrets"
+	cmdVersion  = "version"
+	cmdUpgrade  = "upgrade"
+	cmdRename   = "rename"
 )
 
 // usage is a usageFn for the top level command.
@@ -49,7 +72,18 @@ func usage(cliName string) string {
 		"COMMANDS:\n" +
 		"    " + cmdGenerate + "     Generate Kubernetes manifests for deploying a Bridge.\n" +
 		"    " + cmdValidate + "     Validate a Bridge description.\n" +
-		"    " + cmdGraph + "        Represent a Bridge as a directed graph in DOT format.\n"
+		"    " + cmdGraph + "        Represent a Bridge as a directed graph.\n" +
+		"    " + cmdExplain + "      Describe the supported component types and their configuration.\n" +
+		"    " + cmdSchema + "       Export the configuration schemas of component types as JSON Schema.\n" +
+		"    " + cmdLSP + "          Run a language server for Bridge descriptions.\n" +
+		"    " + cmdImport + "       Reconstruct a Bridge description from Kubernetes manifests.\n" +
+		"    " + cmdSimulate + "     Simulate the flow of an event through a Bridge.\n" +
+		"    " + cmdTest + "         Run routing tests against a Bridge.\n" +
+		"    " + cmdDocs + "         Generate Markdown documentation of a Bridge.\n" +
+		"    " + cmdSecrets + "      List the Kubernetes Secrets required by a Bridge.\n" +
+		"    " + cmdUpgrade + "      Rewrite deprecated constructs of a Bridge description.\n" +
+		"    " + cmdRename + "       Rename a component and update all references to it.\n" +
+		"    " + cmdVersion + "      Print the version of the interpreter.\n"
 }
 
 // usageGenerate is a usageFn for the "generate" subcommand.
@@ -61,27 +95,273 @@ func usageGenerate(cmd string) string {
 		"    " + cmd + " FILE [OPTION]...\n" +
 		"\n" +
 		"OPTIONS:\n" +
-		"    --bridge     Output a Bridge object instead of a List-manifest.\n" +
-		"    --yaml       Output generated manifests in YAML format.\n"
+		"    --bridge               Output a Bridge object instead of a List-manifest.\n" +
+		"    --format               Output format. One of [json, yaml, helm]. Defaults to json, or\n" +
+		"                           to yaml with --output-dir, which doesn't support json.\n" +
+		"                           The helm format packages generated manifests as a Helm chart,\n" +
+		"                           and requires --output-dir. Input variables are resolved at\n" +
+		"                           generation time and aren't exposed as values of the chart.\n" +
+		"    --yaml                 Output generated manifests in YAML format. Same as --format yaml.\n" +
+		"    --output-dir           Write each generated manifest to its own YAML file inside the\n" +
+		"                           given directory instead of standard output, grouped by Bridge\n" +
+		"                           component and listed in a kustomization.yaml file. Files from\n" +
+		"                           a previous generation which became stale are removed. A\n" +
+		"                           kustomization.yaml file which wasn't generated by this\n" +
+		"                           command is never overwritten.\n" +
+		"    --namespace            Kubernetes namespace the Bridge is deployed to, which is set\n" +
+		"                           on all generated objects. By default, generated objects\n" +
+		"                           don't specify a namespace.\n" +
+		"    --network-policies     Generate NetworkPolicies which only allow traffic to each\n" +
+		"                           component from the components which send it events, and\n" +
+		"                           from the namespaces of the Knative data plane.\n" +
+		"    --knative-namespaces   Comma-separated list of the namespaces of the Knative data\n" +
+		"                           plane, used with --network-policies. Defaults to the\n" +
+		"                           namespaces of a standard installation of Knative with the\n" +
+		"                           Kourier ingress:\n" +
+		"                           " + strings.Join(core.DefaultKnativeNamespaces, ",") + "\n" +
+		usageVariableOptions +
+		usagePolicyOptions +
+		usageDiagnosticsOptions
 }
 
 // usageValidate is a usageFn for the "validate" subcommand.
 func usageValidate(cmd string) string {
-	return "Verifies that a Bridge is syntactically valid and can be generated. " +
+	return "Verifies that a Bridge is syntactically valid and can be generated, " +
+		"and optionally that it complies with a set of policies. " +
 		"Returns with an exit code of 0 in case of success, with an exit code of 1 " +
 		"otherwise.\n" +
 		"\n" +
 		"USAGE:\n" +
-		"    " + cmd + " FILE\n"
+		"    " + cmd + " FILE [OPTION]...\n" +
+		"\n" +
+		"OPTIONS:\n" +
+		usageVariableOptions +
+		usagePolicyOptions +
+		usageDiagnosticsOptions
 }
 
 // usageGraph is a usageFn for the "usage" subcommand.
 func usageGraph(cmd string) string {
-	return "Generates a DOT representation of a Bridge and writes it to standard " +
+	return "Generates a graph representation of a Bridge and writes it to standard " +
 		"output.\n" +
 		"\n" +
 		"USAGE:\n" +
-		"    " + cmd + " FILE\n"
+		"    " + cmd + " FILE [OPTION]...\n" +
+		"\n" +
+		"OPTIONS:\n" +
+		"    --format       Output format. One of [dot, mermaid, plantuml, json, text].\n" +
+		"                   Defaults to dot. The json format follows a stable schema of\n" +
+		"                   vertices and edges. The text format is a diagram made of\n" +
+		"                   box-drawing characters, suitable for terminals.\n" +
+		"    --focus        Render only the components connected to the given component\n" +
+		"                   (e.g. target.my_target).\n" +
+		"    --depth        Maximum distance from the focused component, in number of\n" +
+		"                   connections. Defaults to unlimited. Requires --focus.\n" +
+		"    --upstream     Render only the paths leading to the focused component.\n" +
+		"                   Requires --focus.\n" +
+		"    --downstream   Render only the paths starting at the focused component.\n" +
+		"                   Requires --focus.\n" +
+		"    --hide-dls     Omit connections to the dead-letter sink of the Bridge.\n" +
+		"    --cluster-by   Group components in clusters. One of [category]. Only applicable\n" +
+		"                   to the dot format.\n" +
+		"    --rankdir      Direction of the graph layout. One of [LR, RL, TB, BT]. Defaults\n" +
+		"                   to LR. Only applicable to the dot format.\n" +
+		usageVariableOptions +
+		usageDiagnosticsOptions
+}
+
+// usageExplain is a usageFn for the "explain" subcommand.
+func usageExplain(cmd string) string {
+	return "Describes the component types supported by TriggerMesh. Without argument, " +
+		"lists all component types by category. Given a category and a type, describes " +
+		"the configuration accepted by that component type. Attributes annotated as " +
+		"\"validated\" accept only some of the values of their type.\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + " [CATEGORY [TYPE]]\n"
+}
+
+// usageSchema is a usageFn for the "schema" subcommand.
+func usageSchema(cmd string) string {
+	return "Exports the configuration schemas of the component types supported by " +
+		"TriggerMesh as a JSON Schema document, and writes it to standard output. " +
+		"Without argument, all component types are included as definitions named " +
+		"\"<category>.<type>\".\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + " [CATEGORY [TYPE]]\n"
+}
+
+// usageLSP is a usageFn for the "lsp" subcommand.
+func usageLSP(cmd string) string {
+	return "Runs a server implementing the Language Server Protocol, which provides " +
+		"code editors with completion, navigation, documentation and diagnostics for " +
+		"Bridge descriptions. The server communicates over standard input/output.\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + "\n"
+}
+
+// usagePolicyOptions is the usage text of the options shared by all
+// subcommands which enforce policies.
+const usagePolicyOptions = "" +
+	"    --policy-dir           Evaluate the policies contained in the policy files (*" + policy.FileExt + ")\n" +
+	"                           of the given directory against the Bridge. Violations of\n" +
+	"                           policies with an error severity fail the command.\n"
+
+// usageVariableOptions is the usage text of the options shared by all
+// subcommands which load a Bridge for generating or analyzing it.
+const usageVariableOptions = "" +
+	"    --var                  Set the value of an input variable of the Bridge, in the\n" +
+	"                           format NAME=VALUE. Can be repeated.\n"
+
+// usageDiagnosticsOptions is the usage text of the options shared by all
+// subcommands which report diagnostics.
+const usageDiagnosticsOptions = "" +
+	"    --diagnostics-format   Format of reported diagnostics. One of [text, json, sarif].\n" +
+	"                           Defaults to text. Diagnostics are always written to\n" +
+	"                           standard error.\n" +
+	"    --no-color             Disable colors in text diagnostics. Implied when the\n" +
+	"                           NO_COLOR environment variable is set.\n"
+
+// usageImport is a usageFn for the "import" subcommand.
+func usageImport(cmd string) string {
+	return "Reconstructs a Bridge description from Kubernetes manifests, and writes it " +
+		"to standard output. The manifests are read in YAML or JSON format from the " +
+		"given file, or from standard input if FILE is omitted or equal to \"-\". " +
+		"Lists of objects and Bridge objects are supported.\n" +
+		"\n" +
+		"Objects and settings which can not be mapped to Bridge components are reported " +
+		"as diagnostics on standard error, regardless of their format. The import fails if " +
+		"a required setting of a component can not be inferred from the manifests.\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + " [FILE] [OPTION]...\n" +
+		"\n" +
+		"OPTIONS:\n" +
+		usageDiagnosticsOptions
+}
+
+// usageSimulate is a usageFn for the "simulate" subcommand.
+func usageSimulate(cmd string) string {
+	return "Simulates the flow of a CloudEvent through a Bridge without deploying it, " +
+		"and writes the tree of components reached by the event to standard output, " +
+		"together with the payload of the event at each step.\n" +
+		"\n" +
+		"Filters and routing rules of routers, as well as bumblebee transformations, " +
+		"are evaluated. Components which can not be evaluated offline, such as " +
+		"functions, are reported as opaque stops.\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + " FILE --event EVENT_FILE --from COMPONENT [OPTION]...\n" +
+		"\n" +
+		"OPTIONS:\n" +
+		"    --event   File containing the CloudEvent to simulate, in the JSON event\n" +
+		"              format. Read from standard input if equal to \"-\".\n" +
+		"    --from    Address of the component which emits the event, e.g.\n" +
+		"              \"source.my_source\".\n" +
+		usageVariableOptions +
+		usageDiagnosticsOptions
+}
+
+// usageTest is a usageFn for the "test" subcommand.
+func usageTest(cmd string) string {
+	return "Runs the routing tests contained in the given test files against a Bridge, " +
+		"and reports which tests passed or failed. Without test file, all files with " +
+		"the extension \"" + tiltest.FileExt + "\" located next to the Bridge " +
+		"description are used. Returns with an exit code of 1 if any test fails.\n" +
+		"\n" +
+		"Tests run offline, by simulating the flow of events through the Bridge. " +
+		"Failed assertions are reported as diagnostics on standard error.\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + " FILE [TEST_FILE]... [OPTION]...\n" +
+		"\n" +
+		"OPTIONS:\n" +
+		usageVariableOptions +
+		usageDiagnosticsOptions
+}
+
+// usageDocs is a usageFn for the "docs" subcommand.
+func usageDocs(cmd string) string {
+	return "Generates Markdown documentation of a Bridge and writes it to standard output.\n" +
+		"\n" +
+		"The documentation contains the Bridge's description, a Mermaid diagram of its " +
+		"event flows, a table of its components with their key settings, the routing " +
+		"rules of its content-based routers, and the Kubernetes Secrets it requires. " +
+		"Bridges and components can be documented using the \"description\" and " +
+		"\"owner\" attributes.\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + " FILE [OPTION]...\n" +
+		"\n" +
+		"OPTIONS:\n" +
+		usageVariableOptions +
+		usageDiagnosticsOptions
+}
+
+// usageSecrets is a usageFn for the "secrets" subcommand.
+func usageSecrets(cmd string) string {
+	return "Lists the Kubernetes Secrets referenced by the components of a Bridge, " +
+		"together with the keys each Secret must contain and the components which " +
+		"use it.\n" +
+		"\n" +
+		"In template mode, placeholder Secret manifests with empty values are written " +
+		"instead, ready to be filled in and applied before the Bridge is deployed.\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + " FILE [OPTION]...\n" +
+		"\n" +
+		"OPTIONS:\n" +
+		"    --template                Output placeholder manifests for the listed Secrets.\n" +
+		"    --external-secret-store   Output ExternalSecret objects which read the Secrets from\n" +
+		"                              the SecretStore with the given name, instead of Secret\n" +
+		"                              objects. Implies --template.\n" +
+		"    --yaml                    Output manifests in YAML format instead of JSON.\n" +
+		usageVariableOptions +
+		usageDiagnosticsOptions
+}
+
+// usageVersion is a usageFn for the "version" subcommand.
+func usageVersion(cmd string) string {
+	return "Prints the version of the interpreter. Bridges can require a range of " +
+		"interpreter versions using the \"" + config.AttrRequiredVersion + "\" attribute " +
+		"of the \"bridge\" block.\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + "\n"
+}
+
+// usageUpgrade is a usageFn for the "upgrade" subcommand.
+func usageUpgrade(cmd string) string {
+	return "Rewrites deprecated constructs of a Bridge description in place, such as " +
+		"plaintext credentials which must be replaced with references to Kubernetes " +
+		"Secrets. Formatting and comments are " +
+		"preserved. Actions which must be taken manually to complete the migration are " +
+		"reported on standard error.\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + " FILE [OPTION]...\n" +
+		"\n" +
+		"OPTIONS:\n" +
+		"    --dry-run              Write the changes to standard output as a unified diff\n" +
+		"                           instead of modifying the file.\n" +
+		usageDiagnosticsOptions
+}
+
+// usageRename is a usageFn for the "rename" subcommand.
+func usageRename(cmd string) string {
+	return "Changes the identifier of a component of a Bridge, and updates every reference " +
+		"to that component, such as \"to\", \"reply_to\" and \"dead_letter_sink\" attributes. " +
+		"Components are designated by their address, e.g. \"target.my_target\". References " +
+		"contained in the test files (*.tiltest.hcl) located next to FILE are updated as well. " +
+		"The command fails if the new address is already used by another component.\n" +
+		"\n" +
+		"USAGE:\n" +
+		"    " + cmd + " FILE OLD_ADDRESS NEW_ADDRESS [OPTION]...\n" +
+		"\n" +
+		"OPTIONS:\n" +
+		usageDiagnosticsOptions
 }
 
 // usageFn returns the usage text for a program or subcommand.
@@ -99,12 +379,38 @@ var (
 	_ cli.Command = (*GenerateCommand)(nil)
 	_ cli.Command = (*ValidateCommand)(nil)
 	_ cli.Command = (*GraphCommand)(nil)
+	_ cli.Command = (*ExplainCommand)(nil)
+	_ cli.Command = (*SchemaCommand)(nil)
+	_ cli.Command = (*LSPCommand)(nil)
+	_ cli.Command = (*ImportCommand)(nil)
+	_ cli.Command = (*SimulateCommand)(nil)
+	_ cli.Command = (*TestCommand)(nil)
+	_ cli.Command = (*DocsCommand)(nil)
+	_ cli.Command = (*SecretsCommand)(nil)
+	_ cli.Command = (*VersionCommand)(nil)
+	_ cli.Command = (*UpgradeCommand)(nil)
+	_ cli.Command = (*RenameCommand)(nil)
+)
+
+// Output formats supported by the "generate" subcommand.
+const (
+	genFormatJSON = "json"
+	genFormatYAML = "yaml"
+	genFormatHelm = "helm"
 )
 
 type GenerateCommand struct {
 	// flags
-	bridge bool
-	yaml   bool
+	bridge            bool
+	format            string
+	yaml              bool
+	outputDir         string
+	namespace         string
+	networkPolicies   bool
+	knativeNamespaces string
+	variableOptions
+	diagnosticsOptions
+	policyOptions
 }
 
 // Run implements cli.Command.
@@ -113,7 +419,15 @@ func (c *GenerateCommand) Run(ctx context.Context, args []string) error {
 	setUsageFn(flagSet, usageGenerate)
 
 	flagSet.BoolVar(&c.bridge, "bridge", false, "")
+	flagSet.StringVar(&c.format, "format", genFormatJSON, "")
 	flagSet.BoolVar(&c.yaml, "yaml", false, "")
+	flagSet.StringVar(&c.outputDir, "output-dir", "", "")
+	flagSet.StringVar(&c.namespace, "namespace", "", "")
+	flagSet.BoolVar(&c.networkPolicies, "network-policies", false, "")
+	flagSet.StringVar(&c.knativeNamespaces, "knative-namespaces", "", "")
+	c.variableOptions.addFlags(flagSet)
+	c.diagnosticsOptions.addFlags(flagSet)
+	c.policyOptions.addFlags(flagSet)
 
 	pos, flags := splitArgs(1, args)
 	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
@@ -123,31 +437,64 @@ func (c *GenerateCommand) Run(ctx context.Context, args []string) error {
 	}
 	filePath := pos[0]
 
-	// value to use as the Bridge identifier in case none is defined in the
-	// parsed Bridge description
-	const defaultBridgeIdentifier = "til_generated"
+	if c.yaml {
+		c.format = genFormatYAML
+	}
+
+	switch c.format {
+	case genFormatJSON, genFormatYAML, genFormatHelm:
+	default:
+		return fmt.Errorf("unsupported output format %q.\n\n%s", c.format, usageGenerate(flagSet.Name()))
+	}
+
+	if c.outputDir != "" && c.bridge {
+		return fmt.Errorf("the --output-dir and --bridge options are mutually exclusive.\n\n%s",
+			usageGenerate(flagSet.Name()))
+	}
+	// Manifests written to an output directory are always YAML files, so an
+	// explicit request for JSON can't be honoured.
+	if c.outputDir != "" && c.format == genFormatJSON && isFlagSet(flagSet, "format") {
+		return fmt.Errorf("the --output-dir option doesn't support the json output format.\n\n%s",
+			usageGenerate(flagSet.Name()))
+	}
+	if errs := validation.IsDNS1123Label(c.namespace); c.namespace != "" && len(errs) > 0 {
+		return fmt.Errorf("invalid namespace %q: %s.\n\n%s", c.namespace, strings.Join(errs, "; "),
+			usageGenerate(flagSet.Name()))
+	}
+	if c.knativeNamespaces != "" && !c.networkPolicies {
+		return fmt.Errorf("the --knative-namespaces option requires the --network-policies option.\n\n%s",
+			usageGenerate(flagSet.Name()))
+	}
+	if c.format == genFormatHelm && c.outputDir == "" {
+		return fmt.Errorf("the helm output format requires the --output-dir option.\n\n%s",
+			usageGenerate(flagSet.Name()))
+	}
 
 	ui := cli.UIFromContext(ctx)
 
-	p := file.NewParser()
+	// All diagnostics, including warnings, are accumulated and written
+	// once, so that machine-readable formats produce a single document.
+	var reported hcl.Diagnostics
+
+	p := c.variableOptions.newParser()
 	brg, diags := p.LoadBridge(filePath)
-	dw := newDiagnosticTextWriter(ui.ErrWriter, p.Files())
+	dw := c.diagnosticWriter(ui, p.Files())
+	reported = reported.Extend(diags)
 	if diags.HasErrors() {
-		_ = dw.WriteDiagnostics(diags)
+		_ = dw.WriteDiagnostics(reported)
 		return errLoadBridge
 	}
 
 	cctx, diags := core.NewContext(brg)
+	reported = reported.Extend(diags)
 	if diags.HasErrors() {
-		_ = dw.WriteDiagnostics(diags)
+		_ = dw.WriteDiagnostics(reported)
 		return errInitContext
 	}
 
-	manifests, diags := cctx.Generate()
-	if diags.HasErrors() {
-		_ = dw.WriteDiagnostics(diags)
-		return errGenerate
-	}
+	cctx.Namespace = c.namespace
+	cctx.NetworkPolicies = c.networkPolicies
+	cctx.KnativeNamespaces = splitList(c.knativeNamespaces)
 
 	brgID := brg.Identifier
 	if brgID == "" {
@@ -155,14 +502,50 @@ func (c *GenerateCommand) Run(ctx context.Context, args []string) error {
 	}
 	s := encoding.NewSerializer(brgID)
 
+	var cmpsManifests []*core.ComponentManifests
+	var manifests []interface{}
+
+	if c.outputDir != "" {
+		cmpsManifests, diags = cctx.GenerateComponents()
+	} else {
+		manifests, diags = cctx.Generate()
+	}
+	reported = reported.Extend(diags)
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(reported)
+		return errGenerate
+	}
+
+	// Policy violations are reported after the Bridge was successfully
+	// translated, and prevent manifests from being written.
+	diags = c.checkPolicies(p, cctx)
+	reported = reported.Extend(diags)
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(reported)
+		return errPolicies
+	}
+
+	// Warnings, such as workloads which can't be protected by
+	// NetworkPolicies, don't prevent manifests from being written.
+	if len(reported) > 0 {
+		_ = dw.WriteDiagnostics(reported)
+	}
+
+	if c.outputDir != "" {
+		if c.format == genFormatHelm {
+			return s.WriteHelmChart(c.outputDir, cmpsManifests)
+		}
+		return s.WriteManifestsDir(c.outputDir, cmpsManifests)
+	}
+
 	var w encoding.ManifestsWriterFunc
 
 	switch {
-	case c.bridge && c.yaml:
+	case c.bridge && c.format == genFormatYAML:
 		w = s.WriteBridgeYAML
 	case c.bridge:
 		w = s.WriteBridgeJSON
-	case c.yaml:
+	case c.format == genFormatYAML:
 		w = s.WriteManifestsYAML
 	default:
 		w = s.WriteManifestsJSON
@@ -171,13 +554,22 @@ func (c *GenerateCommand) Run(ctx context.Context, args []string) error {
 	return w(ui.StdWriter, manifests)
 }
 
-type ValidateCommand struct{}
+type ValidateCommand struct {
+	// flags
+	variableOptions
+	diagnosticsOptions
+	policyOptions
+}
 
 // Run implements Command.
 func (c *ValidateCommand) Run(ctx context.Context, args []string) error {
 	flagSet := cli.FlagSetFromContext(ctx)
 	setUsageFn(flagSet, usageValidate)
 
+	c.variableOptions.addFlags(flagSet)
+	c.diagnosticsOptions.addFlags(flagSet)
+	c.policyOptions.addFlags(flagSet)
+
 	pos, flags := splitArgs(1, args)
 	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
 
@@ -188,35 +580,88 @@ func (c *ValidateCommand) Run(ctx context.Context, args []string) error {
 
 	ui := cli.UIFromContext(ctx)
 
-	p := file.NewParser()
+	p := c.variableOptions.newParser()
 	brg, diags := p.LoadBridge(filePath)
-	dw := newDiagnosticTextWriter(ui.ErrWriter, p.Files())
+	dw := c.diagnosticWriter(ui, p.Files())
 	if diags.HasErrors() {
 		_ = dw.WriteDiagnostics(diags)
 		return errLoadBridge
 	}
 
-	cctx, diags := core.NewContext(brg)
-	if diags.HasErrors() {
-		_ = dw.WriteDiagnostics(diags)
+	cctx, ctxDiags := core.NewContext(brg)
+	if ctxDiags.HasErrors() {
+		_ = dw.WriteDiagnostics(ctxDiags)
 		return errInitContext
 	}
 
-	if _, diags := cctx.Generate(); diags.HasErrors() {
-		_ = dw.WriteDiagnostics(diags)
+	_, genDiags := cctx.Generate()
+	if genDiags.HasErrors() {
+		_ = dw.WriteDiagnostics(genDiags)
 		return errGenerate
 	}
 
+	polDiags := c.checkPolicies(p, cctx)
+	if polDiags.HasErrors() {
+		_ = dw.WriteDiagnostics(polDiags)
+		return errPolicies
+	}
+
+	// The same set of warnings is reported in every format.
+	// Machine-readable formats always produce a document, so that tools
+	// such as code scanning services can distinguish a valid Bridge from a
+	// failed invocation.
+	warnDiags := diags.Extend(ctxDiags).Extend(genDiags).Extend(polDiags)
+	if c.diagsFormat != diagnostics.FormatText || len(warnDiags) > 0 {
+		_ = dw.WriteDiagnostics(warnDiags)
+	}
+
 	return nil
 }
 
-type GraphCommand struct{}
+// Output formats supported by the "graph" subcommand.
+const (
+	graphFormatDOT      = "dot"
+	graphFormatMermaid  = "mermaid"
+	graphFormatPlantUML = "plantuml"
+	graphFormatJSON     = "json"
+	graphFormatText     = "text"
+)
+
+// Clustering criteria supported by the "graph" subcommand.
+const (
+	graphClusterByCategory = "category"
+)
+
+type GraphCommand struct {
+	// flags
+	format     string
+	focus      string
+	depth      int
+	upstream   bool
+	downstream bool
+	hideDLS    bool
+	clusterBy  string
+	rankDir    string
+	variableOptions
+	diagnosticsOptions
+}
 
 // Run implements Command.
 func (c *GraphCommand) Run(ctx context.Context, args []string) error {
 	flagSet := cli.FlagSetFromContext(ctx)
 	setUsageFn(flagSet, usageGraph)
 
+	flagSet.StringVar(&c.format, "format", graphFormatDOT, "")
+	flagSet.StringVar(&c.focus, "focus", "", "")
+	flagSet.IntVar(&c.depth, "depth", -1, "")
+	flagSet.BoolVar(&c.upstream, "upstream", false, "")
+	flagSet.BoolVar(&c.downstream, "downstream", false, "")
+	flagSet.BoolVar(&c.hideDLS, "hide-dls", false, "")
+	flagSet.StringVar(&c.clusterBy, "cluster-by", "", "")
+	flagSet.StringVar(&c.rankDir, "rankdir", "", "")
+	c.variableOptions.addFlags(flagSet)
+	c.diagnosticsOptions.addFlags(flagSet)
+
 	pos, flags := splitArgs(1, args)
 	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
 
@@ -225,11 +670,58 @@ func (c *GraphCommand) Run(ctx context.Context, args []string) error {
 	}
 	filePath := pos[0]
 
+	if c.focus == "" && (c.depth >= 0 || c.upstream || c.downstream) {
+		return fmt.Errorf("the --depth, --upstream and --downstream options require the --focus option.\n\n%s",
+			usageGraph(flagSet.Name()))
+	}
+
+	var dotOpts []dot.MarshalOption
+
+	switch c.clusterBy {
+	case "":
+	case graphClusterByCategory:
+		dotOpts = append(dotOpts, dot.ClusterBy(vertexCategory))
+	default:
+		return fmt.Errorf("unsupported clustering criterion %q.\n\n%s", c.clusterBy, usageGraph(flagSet.Name()))
+	}
+
+	switch rd := strings.ToUpper(c.rankDir); rd {
+	case "":
+	case "LR", "RL", "TB", "BT":
+		dotOpts = append(dotOpts, dot.RankDir(rd))
+	default:
+		return fmt.Errorf("unsupported layout direction %q.\n\n%s", c.rankDir, usageGraph(flagSet.Name()))
+	}
+
+	if len(dotOpts) > 0 && c.format != graphFormatDOT {
+		return fmt.Errorf("the --cluster-by and --rankdir options are only applicable to the dot format.\n\n%s",
+			usageGraph(flagSet.Name()))
+	}
+
+	var marshal func(*graph.DirectedGraph) ([]byte, error)
+
+	switch c.format {
+	case graphFormatDOT:
+		marshal = func(g *graph.DirectedGraph) ([]byte, error) {
+			return dot.Marshal(g, dotOpts...)
+		}
+	case graphFormatMermaid:
+		marshal = mermaid.Marshal
+	case graphFormatPlantUML:
+		marshal = plantuml.Marshal
+	case graphFormatJSON:
+		marshal = jsongraph.Marshal
+	case graphFormatText:
+		marshal = text.Marshal
+	default:
+		return fmt.Errorf("unsupported output format %q.\n\n%s", c.format, usageGraph(flagSet.Name()))
+	}
+
 	ui := cli.UIFromContext(ctx)
 
-	p := file.NewParser()
+	p := c.variableOptions.newParser()
 	brg, diags := p.LoadBridge(filePath)
-	dw := newDiagnosticTextWriter(ui.ErrWriter, p.Files())
+	dw := c.diagnosticWriter(ui, p.Files())
 	if diags.HasErrors() {
 		_ = dw.WriteDiagnostics(diags)
 		return errLoadBridge
@@ -247,19 +739,73 @@ func (c *GraphCommand) Run(ctx context.Context, args []string) error {
 		return errors.New("failed to build bridge graph. See error diagnostics")
 	}
 
-	dg, err := dot.Marshal(g)
+	if c.hideDLS {
+		g = g.Subgraph(nil, func(e *graph.Edge) bool {
+			return e.Kind != graph.EdgeKindDeadLetter
+		})
+	}
+
+	if c.focus != "" {
+		focusV := findComponentVertex(g, c.focus)
+		if focusV == nil {
+			return fmt.Errorf("the Bridge has no component %q", c.focus)
+		}
+
+		dir := graph.UpAndDownstream
+		switch {
+		case c.upstream && !c.downstream:
+			dir = graph.Upstream
+		case c.downstream && !c.upstream:
+			dir = graph.Downstream
+		}
+
+		reachable := g.Reachable(focusV, dir, c.depth)
+		g = g.Subgraph(reachable.Has, nil)
+	}
+
+	mg, err := marshal(g)
 	if err != nil {
-		return fmt.Errorf("marshaling graph to DOT: %w", err)
+		return fmt.Errorf("marshaling graph to %s: %w", c.format, err)
 	}
 
 	stdout := cli.UIFromContext(ctx).StdWriter
-	if _, err := stdout.Write(dg); err != nil {
-		return fmt.Errorf("writing generated DOT graph: %w", err)
+	if _, err := stdout.Write(mg); err != nil {
+		return fmt.Errorf("writing generated graph: %w", err)
 	}
 
 	return nil
 }
 
+// findComponentVertex returns the vertex representing the Bridge component
+// with the given address (e.g. "target.my_target"), or nil if no such vertex
+// exists in the graph.
+func findComponentVertex(g *graph.DirectedGraph, cmpAddr string) graph.Vertex {
+	for _, v := range g.Vertices() {
+		mcv, ok := v.(core.MessagingComponentVertex)
+		if !ok {
+			continue
+		}
+
+		a := mcv.ComponentAddr()
+		if a.Category.String()+"."+a.Identifier == cmpAddr {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// vertexCategory returns the category of the Bridge component represented by
+// the given vertex, or an empty string if the vertex doesn't represent a
+// Bridge component.
+func vertexCategory(v graph.Vertex) string {
+	mcv, ok := v.(core.MessagingComponentVertex)
+	if !ok {
+		return ""
+	}
+	return mcv.ComponentAddr().Category.String()
+}
+
 // splitArgs attempts to separate n positional arguments from the rest of the
 // given arguments list. The caller is responsible for ensuring that the
 // correct number of positional arguments could be extracted.
@@ -283,17 +829,141 @@ func splitArgs(n int, args []string) ( /*positional*/ []string /*flags*/, []stri
 	return args[:n], args[n:]
 }
 
-// newDiagnosticTextWriter returns a hcl.DiagnosticWriter that writes
-// diagnostics to the given writer as formatted text.
-func newDiagnosticTextWriter(out io.Writer, files map[string]*hcl.File) hcl.DiagnosticWriter {
-	const outputWidth = 0
-	const enableColor = true
-	return hcl.NewDiagnosticTextWriter(out, files, outputWidth, enableColor)
+// isFlagSet returns whether the flag with the given name was explicitly set on
+// the command line.
+func isFlagSet(f *flag.FlagSet, name string) bool {
+	set := false
+	f.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// splitList returns the non-empty elements of the given comma-separated list.
+func splitList(s string) []string {
+	var elems []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			elems = append(elems, e)
+		}
+	}
+	return elems
+}
+
+// diagnosticsOptions contains the flags which control how diagnostics are
+// reported. It is meant to be embedded in subcommands which report
+// diagnostics.
+type diagnosticsOptions struct {
+	diagsFormat diagnostics.Format
+	noColor     bool
+}
+
+// addFlags registers the diagnostics flags with the given flag.FlagSet.
+func (o *diagnosticsOptions) addFlags(f *flag.FlagSet) {
+	o.diagsFormat = diagnostics.FormatText
+	f.Var(&o.diagsFormat, "diagnostics-format", "")
+
+	// https://no-color.org/
+	_, noColorEnv := os.LookupEnv("NO_COLOR")
+	f.BoolVar(&o.noColor, "no-color", noColorEnv, "")
+}
+
+// diagnosticWriter returns a hcl.DiagnosticWriter that writes diagnostics in
+// the format selected via flags.
+//
+// Diagnostics are always written to the UI's error writer, regardless of their
+// format, because the standard writer is reserved for the output of commands
+// (e.g. generated manifests).
+func (o *diagnosticsOptions) diagnosticWriter(ui *cli.UI, files map[string]*hcl.File) hcl.DiagnosticWriter {
+	return diagnostics.NewWriter(o.diagsFormat, ui.ErrWriter, files, !o.noColor)
+}
+
+// policyOptions contains the flags which control the enforcement of policies.
+// It is meant to be embedded in subcommands which enforce policies.
+type policyOptions struct {
+	policyDir string
+}
+
+// addFlags registers the policy flags with the given flag.FlagSet.
+func (o *policyOptions) addFlags(f *flag.FlagSet) {
+	f.StringVar(&o.policyDir, "policy-dir", "", "")
+}
+
+// checkPolicies loads the policies contained in the directory selected via
+// flags, and evaluates them against the Bridge of the given Context.
+// Returns no diagnostic if no policy directory was selected.
+func (o *policyOptions) checkPolicies(p *file.Parser, cctx *core.Context) hcl.Diagnostics {
+	if o.policyDir == "" {
+		return nil
+	}
+
+	policies, diags := policy.LoadDir(p, o.policyDir)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	return diags.Extend(policy.Check(cctx, policies))
+}
+
+// variableOptions contains the flags which set the values of the input
+// variables of a Bridge. It is meant to be embedded in subcommands which load a
+// Bridge for generating or analyzing it.
+type variableOptions struct {
+	vars variablesFlag
+}
+
+// addFlags registers the variable flags with the given flag.FlagSet.
+func (o *variableOptions) addFlags(f *flag.FlagSet) {
+	o.vars = make(variablesFlag)
+	f.Var(o.vars, "var", "")
+}
+
+// newParser returns a file.Parser which assigns the values selected via flags
+// to the input variables of the Bridge.
+func (o *variableOptions) newParser() *file.Parser {
+	p := file.NewParser()
+	p.Variables = o.vars
+	return p
 }
 
+// variablesFlag is a flag.Value which collects values of input variables in
+// the format NAME=VALUE.
+type variablesFlag map[string]string
+
+var _ flag.Value = (variablesFlag)(nil)
+
+// String implements flag.Value.
+func (f variablesFlag) String() string {
+	kvs := make([]string, 0, len(f))
+	for k, v := range f {
+		kvs = append(kvs, k+"="+v)
+	}
+	sort.Strings(kvs)
+
+	return strings.Join(kvs, ",")
+}
+
+// Set implements flag.Value.
+func (f variablesFlag) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return fmt.Errorf("expected a value in the format NAME=VALUE, got %q", s)
+	}
+
+	f[kv[0]] = kv[1]
+	return nil
+}
+
+// Value to use as the Bridge identifier in case none is defined in the parsed
+// Bridge description.
+const defaultBridgeIdentifier = "til_generated"
+
 // Errors for common operations performed by commands.
 var (
 	errLoadBridge  = errors.New("failed to load bridge. See error diagnostics")
 	errInitContext = errors.New("failed to initialize command context. See error diagnostics")
 	errGenerate    = errors.New("failed to generate bridge manifests. See error diagnostics")
+	errPolicies    = errors.New("bridge violates policies. See error diagnostics")
 )
@@ -26,6 +26,8 @@ const (
 	BlkTransf  = "transformer"
 	BlkSource  = "source"
 	BlkTarget  = "target"
+
+	BlkVariable = "variable"
 )
 
 // Common identifiers for HCL block labels.
@@ -40,6 +42,18 @@ const (
 	AttrReplyTo = "reply_to"
 )
 
+// Block attributes which document a Bridge or its components.
+const (
+	AttrDescription = "description"
+	AttrOwner       = "owner"
+)
+
+// Meta-attributes which apply to every component block, regardless of its
+// category or type.
+const (
+	AttrEnabled = "enabled"
+)
+
 // BridgeSchema is the shallow structure of a Bridge Description File.
 // Used for validation during decoding.
 var BridgeSchema = &hcl.BodySchema{
@@ -61,6 +75,9 @@ var BridgeSchema = &hcl.BodySchema{
 	}, {
 		Type:       BlkTarget,
 		LabelNames: []string{LblType, LblID},
+	}, {
+		Type:       BlkVariable,
+		LabelNames: []string{LblID},
 	}},
 }
 
@@ -70,8 +87,18 @@ type Bridge struct {
 	Path string
 
 	// Bridge globals.
-	Identifier string
-	Delivery   *Delivery
+	Identifier  string
+	Description string
+	Owner       string
+	Delivery    *Delivery
+	RBAC        *RBAC
+	Defaults    []*Defaults
+
+	// Constraint on the version of the interpreter, e.g. ">= 1.2, < 2.0".
+	RequiredVersion string
+
+	// Input variables, indexed by identifier.
+	Variables map[string]*Variable
 
 	// Indexed lists of messaging components.
 	// Parsers should index each component with a key that uniquely identifies a block.
@@ -80,4 +107,7 @@ type Bridge struct {
 	Transformers map[interface{}]*Transformer
 	Sources      map[interface{}]*Source
 	Targets      map[interface{}]*Target
+
+	// Source location of the "bridge" block, if any.
+	SourceRange hcl.Range
 }
@@ -20,7 +20,18 @@ import "github.com/hashicorp/hcl/v2"
 
 // ChannelBlockSchema is the shallow structure of a "channel" block.
 // Used for validation during decoding.
-var ChannelBlockSchema = &hcl.BodySchema{}
+var ChannelBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{
+		Name:     AttrDescription,
+		Required: false,
+	}, {
+		Name:     AttrOwner,
+		Required: false,
+	}, {
+		Name:     AttrEnabled,
+		Required: false,
+	}},
+}
 
 // Channel represents a generic messaging channel.
 type Channel struct {
@@ -29,6 +40,15 @@ type Channel struct {
 	// An identifier that is unique among all Channels within a Bridge.
 	Identifier string
 
+	// Human-readable documentation of the channel.
+	Description string
+	// Person or team responsible for the channel.
+	Owner string
+
+	// Expression which determines whether the channel is part of the Bridge.
+	// Nil if the channel is unconditionally enabled.
+	Enabled hcl.Expression
+
 	// Configuration of the channel.
 	Config hcl.Body
 
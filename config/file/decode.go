@@ -21,9 +21,11 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 
 	"til/config"
 	"til/config/addr"
+	"til/version"
 )
 
 // decodeBridge performs a partial decoding of the Body of a Bridge Description
@@ -48,9 +50,10 @@ func decodeBridge(b hcl.Body, brg *config.Bridge) hcl.Diagnostics {
 
 	visitedBridgeGlobals := false
 
+	// Global settings are decoded ahead of components, because some of
+	// them (e.g. defaults) apply to the decoding of components.
 	for _, blk := range content.Blocks {
-		switch t := blk.Type; t {
-		case config.BlkBridge:
+		if t := blk.Type; t == config.BlkBridge {
 			if visitedBridgeGlobals {
 				diags = diags.Append(tooManyGlobalBlocksDiagnostic(t, blk.DefRange))
 			}
@@ -58,6 +61,20 @@ func decodeBridge(b hcl.Body, brg *config.Bridge) hcl.Diagnostics {
 
 			setDiags := setGlobals(brg, blk)
 			diags = diags.Extend(setDiags)
+		}
+	}
+
+	// defaults which were merged into at least one component
+	matchedDefaults := make(map[*config.Defaults]struct{}, len(brg.Defaults))
+
+	for _, blk := range content.Blocks {
+		if d := applyDefaults(brg.Defaults, blk); d != nil {
+			matchedDefaults[d] = struct{}{}
+		}
+
+		switch t := blk.Type; t {
+		case config.BlkBridge:
+			// already decoded
 
 		case config.BlkChannel:
 			addDiags := addChannelBlock(brg, blk)
@@ -79,6 +96,10 @@ func decodeBridge(b hcl.Body, brg *config.Bridge) hcl.Diagnostics {
 			addDiags := addTargetBlock(brg, blk)
 			diags = diags.Extend(addDiags)
 
+		case config.BlkVariable:
+			addDiags := addVariableBlock(brg, blk)
+			diags = diags.Extend(addDiags)
+
 		default:
 			// should never occur because the hcl.BodyContent was
 			// validated against a hcl.BodySchema during parsing
@@ -86,6 +107,12 @@ func decodeBridge(b hcl.Body, brg *config.Bridge) hcl.Diagnostics {
 		}
 	}
 
+	for _, d := range brg.Defaults {
+		if _, matched := matchedDefaults[d]; !matched {
+			diags = diags.Append(unmatchedDefaultsDiagnostic(d))
+		}
+	}
+
 	return diags
 }
 
@@ -100,9 +127,20 @@ func setGlobals(brg *config.Bridge, blk *hcl.Block) hcl.Diagnostics {
 	content, contentDiags := blk.Body.Content(config.BridgeBlockSchema)
 	diags = diags.Extend(contentDiags)
 
+	desc, owner, decodeDiags := decodeDocAttributes(content)
+	diags = diags.Extend(decodeDiags)
+
+	reqVersion, decodeDiags := decodeRequiredVersion(content.Attributes[config.AttrRequiredVersion])
+	diags = diags.Extend(decodeDiags)
+
 	var delivery *config.Delivery
 	visitedDelivery := false
 
+	var rbac *config.RBAC
+	visitedRBAC := false
+
+	var defaults []*config.Defaults
+
 	for _, blk := range content.Blocks {
 		switch t := blk.Type; t {
 		case config.BlkDelivery:
@@ -114,11 +152,41 @@ func setGlobals(brg *config.Bridge, blk *hcl.Block) hcl.Diagnostics {
 			var decodeDiags hcl.Diagnostics
 			delivery, decodeDiags = decodeBridgeDeliveryBlock(blk)
 			diags = diags.Extend(decodeDiags)
+
+		case config.BlkRBAC:
+			if visitedRBAC {
+				diags = diags.Append(tooManyGlobalBlocksDiagnostic(t, blk.DefRange))
+			}
+			visitedRBAC = true
+
+			var decodeDiags hcl.Diagnostics
+			rbac, decodeDiags = decodeBridgeRBACBlock(blk)
+			diags = diags.Extend(decodeDiags)
+
+		case config.BlkDefaults:
+			d, decodeDiags := decodeBridgeDefaultsBlock(blk)
+			diags = diags.Extend(decodeDiags)
+
+			if d == nil {
+				continue
+			}
+
+			if findDefaults(defaults, d.Category, d.Type) != nil {
+				diags = diags.Append(duplicateDefaultsDiagnostic(d.Category, d.Type, blk.DefRange))
+				continue
+			}
+			defaults = append(defaults, d)
 		}
 	}
 
 	brg.Identifier = blk.Labels[0]
+	brg.Description = desc
+	brg.Owner = owner
 	brg.Delivery = delivery
+	brg.RBAC = rbac
+	brg.Defaults = defaults
+	brg.RequiredVersion = reqVersion
+	brg.SourceRange = blk.DefRange
 
 	return diags
 }
@@ -253,6 +321,30 @@ func addTargetBlock(brg *config.Bridge, blk *hcl.Block) hcl.Diagnostics {
 	return diags
 }
 
+// addVariableBlock adds an input Variable to a Bridge.
+func addVariableBlock(brg *config.Bridge, blk *hcl.Block) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	v, decodeDiags := decodeVariableBlock(blk)
+	diags = diags.Extend(decodeDiags)
+
+	if v == nil {
+		return diags
+	}
+
+	if brg.Variables == nil {
+		brg.Variables = make(map[string]*config.Variable)
+	}
+
+	if _, exists := brg.Variables[v.Identifier]; exists {
+		diags = diags.Append(duplicateVariableDiagnostic(v.Identifier, blk.DefRange))
+	} else {
+		brg.Variables[v.Identifier] = v
+	}
+
+	return diags
+}
+
 // decodeBridgeDeliveryBlock performs a decoding of the Body of a
 // "bridge.delivery" block into a Delivery struct.
 func decodeBridgeDeliveryBlock(blk *hcl.Block) (*config.Delivery, hcl.Diagnostics) {
@@ -275,6 +367,69 @@ func decodeBridgeDeliveryBlock(blk *hcl.Block) (*config.Delivery, hcl.Diagnostic
 	return d, diags
 }
 
+// decodeBridgeRBACBlock performs a decoding of the Body of a "bridge.rbac"
+// block into a RBAC struct.
+func decodeBridgeRBACBlock(blk *hcl.Block) (*config.RBAC, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := blk.Body.Content(config.RBACBlockSchema)
+	diags = diags.Extend(contentDiags)
+
+	saScope := config.ServiceAccountPerComponent
+
+	if attr := content.Attributes[config.AttrServiceAccount]; attr != nil {
+		v, decodeDiags := decodeStringVal(attr)
+		diags = diags.Extend(decodeDiags)
+
+		switch v {
+		case config.ServiceAccountPerComponent, config.ServiceAccountPerBridge:
+			saScope = v
+		default:
+			if !decodeDiags.HasErrors() {
+				diags = diags.Append(badServiceAccountScopeDiagnostic(v, attr.Expr.Range()))
+			}
+		}
+	}
+
+	r := &config.RBAC{
+		ServiceAccount: saScope,
+	}
+
+	return r, diags
+}
+
+// decodeVariableBlock performs a decoding of the Body of a "variable" block
+// into a Variable struct.
+func decodeVariableBlock(blk *hcl.Block) (*config.Variable, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	if !hclsyntax.ValidIdentifier(blk.Labels[0]) {
+		diags = diags.Append(badIdentifierDiagnostic(blk.LabelRanges[0]))
+	}
+
+	content, contentDiags := blk.Body.Content(config.VariableBlockSchema)
+	diags = diags.Extend(contentDiags)
+
+	desc, decodeDiags := decodeStringVal(content.Attributes[config.AttrDescription])
+	diags = diags.Extend(decodeDiags)
+
+	val := cty.NullVal(cty.DynamicPseudoType)
+	if attr := content.Attributes[config.AttrDefault]; attr != nil {
+		var evalDiags hcl.Diagnostics
+		val, evalDiags = attr.Expr.Value(nil)
+		diags = diags.Extend(evalDiags)
+	}
+
+	v := &config.Variable{
+		Identifier:  blk.Labels[0],
+		Description: desc,
+		Value:       val,
+		SourceRange: blk.DefRange,
+	}
+
+	return v, diags
+}
+
 // decodeChannelBlock performs a partial decoding of the Body of a "channel"
 // block into a Channel struct.
 func decodeChannelBlock(blk *hcl.Block) (*config.Channel, hcl.Diagnostics) {
@@ -287,12 +442,18 @@ func decodeChannelBlock(blk *hcl.Block) (*config.Channel, hcl.Diagnostics) {
 		diags = diags.Append(badIdentifierDiagnostic(blk.LabelRanges[1]))
 	}
 
-	_, remain, contentDiags := blk.Body.PartialContent(config.ChannelBlockSchema)
+	content, remain, contentDiags := blk.Body.PartialContent(config.ChannelBlockSchema)
 	diags = diags.Extend(contentDiags)
 
+	desc, owner, decodeDiags := decodeDocAttributes(content)
+	diags = diags.Extend(decodeDiags)
+
 	ch := &config.Channel{
 		Type:        blk.Labels[0],
 		Identifier:  blk.Labels[1],
+		Description: desc,
+		Owner:       owner,
+		Enabled:     enabledExpr(content),
 		Config:      remain,
 		SourceRange: blk.DefRange,
 	}
@@ -312,12 +473,18 @@ func decodeRouterBlock(blk *hcl.Block) (*config.Router, hcl.Diagnostics) {
 		diags = diags.Append(badIdentifierDiagnostic(blk.LabelRanges[1]))
 	}
 
-	_, remain, contentDiags := blk.Body.PartialContent(config.RouterBlockSchema)
+	content, remain, contentDiags := blk.Body.PartialContent(config.RouterBlockSchema)
 	diags = diags.Extend(contentDiags)
 
+	desc, owner, decodeDiags := decodeDocAttributes(content)
+	diags = diags.Extend(decodeDiags)
+
 	rtr := &config.Router{
 		Type:        blk.Labels[0],
 		Identifier:  blk.Labels[1],
+		Description: desc,
+		Owner:       owner,
+		Enabled:     enabledExpr(content),
 		Config:      remain,
 		SourceRange: blk.DefRange,
 	}
@@ -343,9 +510,15 @@ func decodeTransformerBlock(blk *hcl.Block) (*config.Transformer, hcl.Diagnostic
 	to, decodeDiags := decodeBlockRef(content.Attributes[config.AttrTo])
 	diags = diags.Extend(decodeDiags)
 
+	desc, owner, decodeDiags := decodeDocAttributes(content)
+	diags = diags.Extend(decodeDiags)
+
 	rtr := &config.Transformer{
 		Type:        blk.Labels[0],
 		Identifier:  blk.Labels[1],
+		Description: desc,
+		Owner:       owner,
+		Enabled:     enabledExpr(content),
 		To:          to,
 		Config:      remain,
 		SourceRange: blk.DefRange,
@@ -372,9 +545,15 @@ func decodeSourceBlock(blk *hcl.Block) (*config.Source, hcl.Diagnostics) {
 	to, decodeDiags := decodeBlockRef(content.Attributes[config.AttrTo])
 	diags = diags.Extend(decodeDiags)
 
+	desc, owner, decodeDiags := decodeDocAttributes(content)
+	diags = diags.Extend(decodeDiags)
+
 	src := &config.Source{
 		Type:        blk.Labels[0],
 		Identifier:  blk.Labels[1],
+		Description: desc,
+		Owner:       owner,
+		Enabled:     enabledExpr(content),
 		To:          to,
 		Config:      remain,
 		SourceRange: blk.DefRange,
@@ -401,9 +580,15 @@ func decodeTargetBlock(blk *hcl.Block) (*config.Target, hcl.Diagnostics) {
 	to, decodeDiags := decodeBlockRef(content.Attributes[config.AttrReplyTo])
 	diags = diags.Extend(decodeDiags)
 
+	desc, owner, decodeDiags := decodeDocAttributes(content)
+	diags = diags.Extend(decodeDiags)
+
 	trg := &config.Target{
 		Type:        blk.Labels[0],
 		Identifier:  blk.Labels[1],
+		Description: desc,
+		Owner:       owner,
+		Enabled:     enabledExpr(content),
 		ReplyTo:     to,
 		Config:      remain,
 		SourceRange: blk.DefRange,
@@ -433,6 +618,77 @@ func decodeBlockRef(attr *hcl.Attribute) (hcl.Traversal, hcl.Diagnostics) {
 	return hcl.AbsTraversalForExpr(attr.Expr)
 }
 
+// decodeDocAttributes decodes the attributes which document a Bridge or one of
+// its components.
+func decodeDocAttributes(content *hcl.BodyContent) (desc, owner string, diags hcl.Diagnostics) {
+	desc, decodeDiags := decodeStringVal(content.Attributes[config.AttrDescription])
+	diags = diags.Extend(decodeDiags)
+
+	owner, decodeDiags = decodeStringVal(content.Attributes[config.AttrOwner])
+	diags = diags.Extend(decodeDiags)
+
+	return desc, owner, diags
+}
+
+// enabledExpr returns the expression of the "enabled" meta-attribute of a
+// component, if set.
+func enabledExpr(content *hcl.BodyContent) hcl.Expression {
+	if attr := content.Attributes[config.AttrEnabled]; attr != nil {
+		return attr.Expr
+	}
+	return nil
+}
+
+// decodeRequiredVersion decodes the version constraint of a Bridge, and
+// verifies that the version of the interpreter satisfies it.
+func decodeRequiredVersion(attr *hcl.Attribute) (string, hcl.Diagnostics) {
+	constraint, diags := decodeStringVal(attr)
+	if diags.HasErrors() || constraint == "" {
+		return constraint, diags
+	}
+
+	cs, err := version.ParseConstraints(constraint)
+	if err != nil {
+		return constraint, diags.Append(badVersionConstraintDiagnostic(err, attr.Expr.Range()))
+	}
+
+	current, err := version.Current()
+	if err != nil {
+		return constraint, diags.Append(invalidInterpreterVersionDiagnostic(err, attr.Expr.Range()))
+	}
+
+	if !cs.Check(current) {
+		diags = diags.Append(unsupportedVersionDiagnostic(cs, attr.Expr.Range()))
+	}
+
+	return constraint, diags
+}
+
+// decodeStringVal decodes a string attribute.
+func decodeStringVal(attr *hcl.Attribute) (string, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	if attr == nil {
+		return "", diags
+	}
+
+	val, evalDiags := attr.Expr.Value(nil)
+	diags = diags.Extend(evalDiags)
+	if evalDiags.HasErrors() {
+		return "", diags
+	}
+
+	if val.IsNull() {
+		return "", diags
+	}
+	if val.Type() != cty.String {
+		diags = diags.Append(wrongTypeDiagnostic(val, "string", attr.Expr.Range()))
+		return "", diags
+	}
+
+	return val.AsString(), diags
+}
+
 // decodeInt64Val decodes an integer attribute.
 func decodeInt64Val(attr *hcl.Attribute) (*int64, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
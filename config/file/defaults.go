@@ -0,0 +1,130 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"til/config"
+)
+
+// decodeBridgeDefaultsBlock performs a partial decoding of a "bridge.defaults"
+// block into a Defaults struct.
+//
+// The body of the block is left undecoded, since it can only be validated
+// once merged into the body of a matching component.
+func decodeBridgeDefaultsBlock(blk *hcl.Block) (*config.Defaults, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	cat := config.AsComponentCategory(blk.Labels[0])
+	if cat == config.CategoryUnknown {
+		diags = diags.Append(unknownDefaultsCategoryDiagnostic(blk.Labels[0], blk.LabelRanges[0]))
+	}
+	if !hclsyntax.ValidIdentifier(blk.Labels[1]) {
+		diags = diags.Append(badIdentifierDiagnostic(blk.LabelRanges[1]))
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	d := &config.Defaults{
+		Category:    cat,
+		Type:        blk.Labels[1],
+		Config:      blk.Body,
+		SourceRange: blk.DefRange,
+	}
+
+	return d, diags
+}
+
+// findDefaults returns the Defaults matching the given component category and
+// type, if any.
+func findDefaults(defaults []*config.Defaults, cat config.ComponentCategory, typ string) *config.Defaults {
+	for _, d := range defaults {
+		if d.Category == cat && d.Type == typ {
+			return d
+		}
+	}
+	return nil
+}
+
+// applyDefaults merges the Defaults matching the given component block into
+// the body of that block, and returns the applied Defaults. It returns nil if
+// the block isn't a component block, or if no Defaults match it.
+//
+// Attributes and blocks of the component take precedence over the ones
+// inherited from the Defaults. Inherited blocks are only merged if the
+// component doesn't contain any block of the same type.
+func applyDefaults(defaults []*config.Defaults, blk *hcl.Block) *config.Defaults {
+	cat := config.AsComponentCategory(blk.Type)
+	if cat == config.CategoryUnknown || len(blk.Labels) == 0 {
+		return nil
+	}
+
+	d := findDefaults(defaults, cat, blk.Labels[0])
+	if d == nil {
+		return nil
+	}
+
+	cmpBody, ok := blk.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+	defaultsBody, ok := d.Config.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	blk.Body = mergeBodies(defaultsBody, cmpBody)
+
+	return d
+}
+
+// mergeBodies returns a new body which contains the attributes and blocks of
+// the given base body, overridden by the ones of the given override body.
+// The ranges of the returned body are the ones of the override body.
+func mergeBodies(base, override *hclsyntax.Body) *hclsyntax.Body {
+	attrs := make(hclsyntax.Attributes, len(base.Attributes)+len(override.Attributes))
+	for n, attr := range base.Attributes {
+		attrs[n] = attr
+	}
+	for n, attr := range override.Attributes {
+		attrs[n] = attr
+	}
+
+	overriddenBlocks := make(map[string]struct{}, len(override.Blocks))
+	for _, blk := range override.Blocks {
+		overriddenBlocks[blk.Type] = struct{}{}
+	}
+
+	blocks := make(hclsyntax.Blocks, 0, len(base.Blocks)+len(override.Blocks))
+	for _, blk := range base.Blocks {
+		if _, overridden := overriddenBlocks[blk.Type]; !overridden {
+			blocks = append(blocks, blk)
+		}
+	}
+	blocks = append(blocks, override.Blocks...)
+
+	return &hclsyntax.Body{
+		Attributes: attrs,
+		Blocks:     blocks,
+		SrcRange:   override.SrcRange,
+		EndRange:   override.EndRange,
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/zclconf/go-cty/cty"
 
 	"til/config"
+	"til/version"
 )
 
 // badIdentifierDiagnostic returns a hcl.Diagnostic which indicates that the
@@ -48,6 +49,17 @@ func duplicateBlockDiagnostic(cat config.ComponentCategory, identifier string, s
 	}
 }
 
+// duplicateVariableDiagnostic returns a hcl.Diagnostic which indicates that a
+// variable is declared more than once.
+func duplicateVariableDiagnostic(identifier string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Duplicate variable",
+		Detail:   fmt.Sprintf("The variable %q is declared more than once.", identifier),
+		Subject:  subj.Ptr(),
+	}
+}
+
 // tooManyGlobalBlocksDiagnostic returns a hcl.Diagnostic which indicates that
 // more than one block of the given type was defined in the global configuration.
 func tooManyGlobalBlocksDiagnostic(blkType string, subj hcl.Range) *hcl.Diagnostic {
@@ -69,3 +81,140 @@ func wrongTypeDiagnostic(v cty.Value, expectType string, subj hcl.Range) *hcl.Di
 		Subject:  subj.Ptr(),
 	}
 }
+
+// badVersionConstraintDiagnostic returns a hcl.Diagnostic which indicates that
+// a version constraint can't be parsed.
+func badVersionConstraintDiagnostic(err error, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid version constraint",
+		Detail: fmt.Sprintf("The version constraint is invalid: %s. Constraints are comma-separated "+
+			"lists of versions prefixed with an operator, e.g. \">= 1.2, < 2.0\".", err),
+		Subject: subj.Ptr(),
+	}
+}
+
+// unsupportedVersionDiagnostic returns a hcl.Diagnostic which indicates that
+// the version of the interpreter doesn't satisfy the version constraint of a
+// Bridge.
+func unsupportedVersionDiagnostic(cs version.Constraints, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unsupported interpreter version",
+		Detail: fmt.Sprintf("This Bridge requires a version of the interpreter which satisfies %q, "+
+			"but the current version is %s.", cs, version.Version),
+		Subject: subj.Ptr(),
+	}
+}
+
+// invalidInterpreterVersionDiagnostic returns a hcl.Diagnostic which indicates
+// that the version constraint of a Bridge can't be verified because the
+// version of the interpreter itself is invalid.
+func invalidInterpreterVersionDiagnostic(err error, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid interpreter version",
+		Detail: fmt.Sprintf("The version constraint of this Bridge can't be verified: %s. "+
+			"The interpreter was built with an invalid version number.", err),
+		Subject: subj.Ptr(),
+	}
+}
+
+// undeclaredVariableDiagnostic returns a hcl.Diagnostic which indicates that
+// a value was provided for a variable which isn't declared in the Bridge.
+func undeclaredVariableDiagnostic(name string) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Undeclared variable",
+		Detail: fmt.Sprintf("A value was provided for the variable %q, but the Bridge doesn't declare "+
+			"any variable with this name. Variables are declared using \"variable\" blocks.", name),
+	}
+}
+
+// badVariableValueDiagnostic returns a hcl.Diagnostic which indicates that
+// the value provided for a variable can't be converted to the type of its
+// default value.
+func badVariableValueDiagnostic(name string, t cty.Type, err error, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid variable value",
+		Detail: fmt.Sprintf("The value provided for the variable %q is not a valid %s: %s.",
+			name, t.FriendlyName(), err),
+		Subject: subj.Ptr(),
+	}
+}
+
+// missingVariableValueDiagnostic returns a hcl.Diagnostic which indicates that
+// a variable has neither a default value nor a user-provided value.
+func missingVariableValueDiagnostic(name string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Missing variable value",
+		Detail: fmt.Sprintf("The variable %q doesn't have a default value, and no value was provided "+
+			"for it.", name),
+		Subject: subj.Ptr(),
+	}
+}
+
+// disabledReferenceDiagnostic returns a hcl.Diagnostic which indicates that a
+// reference points at a component which is disabled.
+func disabledReferenceDiagnostic(cmpAddr string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Reference to disabled component",
+		Detail: fmt.Sprintf("The component %q is disabled by its \"enabled\" attribute, and can "+
+			"therefore not be referenced here. Only references inside lists, such as the subscribers "+
+			"of a channel, are discarded automatically when they point at a disabled component.", cmpAddr),
+		Subject: subj.Ptr(),
+	}
+}
+
+// unknownDefaultsCategoryDiagnostic returns a hcl.Diagnostic which indicates
+// that a "defaults" block targets an unknown category of component.
+func unknownDefaultsCategoryDiagnostic(cat string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unknown component category",
+		Detail: fmt.Sprintf("Defaults can not be declared for components of category %q. Supported "+
+			"categories are %q, %q, %q, %q and %q.", cat, config.BlkChannel, config.BlkRouter,
+			config.BlkTransf, config.BlkSource, config.BlkTarget),
+		Subject: subj.Ptr(),
+	}
+}
+
+// duplicateDefaultsDiagnostic returns a hcl.Diagnostic which indicates that
+// defaults are declared more than once for the same category and type of
+// component.
+func duplicateDefaultsDiagnostic(cat config.ComponentCategory, typ string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Duplicate defaults",
+		Detail:   fmt.Sprintf("Defaults are declared more than once for %s components of type %q.", cat, typ),
+		Subject:  subj.Ptr(),
+	}
+}
+
+// unmatchedDefaultsDiagnostic returns a hcl.Diagnostic which indicates that
+// the given defaults don't apply to any component of the Bridge.
+func unmatchedDefaultsDiagnostic(d *config.Defaults) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Unused defaults",
+		Detail: fmt.Sprintf("The Bridge doesn't contain any %s component of type %q, so these "+
+			"defaults don't apply to anything.", d.Category, d.Type),
+		Subject: d.SourceRange.Ptr(),
+	}
+}
+
+// badServiceAccountScopeDiagnostic returns a hcl.Diagnostic which indicates
+// that the scope of the ServiceAccounts generated for a Bridge is not
+// supported.
+func badServiceAccountScopeDiagnostic(scope string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid service account scope",
+		Detail: fmt.Sprintf("The scope of generated ServiceAccounts must be either %q or %q. Got %q.",
+			config.ServiceAccountPerComponent, config.ServiceAccountPerBridge, scope),
+		Subject: subj.Ptr(),
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"til/config"
+)
+
+// removeDisabledComponents evaluates the "enabled" meta-attribute of all
+// components of a Bridge, and removes the components which are disabled.
+//
+// References to disabled components are removed from lists of references,
+// such as the subscribers of a channel, so that the remaining elements of the
+// list still get their events. Any other reference to a disabled component
+// can't be resolved and is reported as an error.
+func removeDisabledComponents(brg *config.Bridge) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	evalCtx := variablesEvalContext(brg)
+
+	// addresses of disabled components, e.g. "target.my_target"
+	disabled := make(map[string]struct{})
+
+	isEnabled := func(cat config.ComponentCategory, id string, expr hcl.Expression) bool {
+		if expr == nil {
+			return true
+		}
+
+		enabled, evalDiags := evalEnabled(expr, evalCtx)
+		diags = diags.Extend(evalDiags)
+		if evalDiags.HasErrors() || enabled {
+			return true
+		}
+
+		disabled[cat.String()+"."+id] = struct{}{}
+		return false
+	}
+
+	for k, ch := range brg.Channels {
+		if !isEnabled(config.CategoryChannels, ch.Identifier, ch.Enabled) {
+			delete(brg.Channels, k)
+		}
+	}
+	for k, rtr := range brg.Routers {
+		if !isEnabled(config.CategoryRouters, rtr.Identifier, rtr.Enabled) {
+			delete(brg.Routers, k)
+		}
+	}
+	for k, trsf := range brg.Transformers {
+		if !isEnabled(config.CategoryTransformers, trsf.Identifier, trsf.Enabled) {
+			delete(brg.Transformers, k)
+		}
+	}
+	for k, src := range brg.Sources {
+		if !isEnabled(config.CategorySources, src.Identifier, src.Enabled) {
+			delete(brg.Sources, k)
+		}
+	}
+	for k, trg := range brg.Targets {
+		if !isEnabled(config.CategoryTargets, trg.Identifier, trg.Enabled) {
+			delete(brg.Targets, k)
+		}
+	}
+
+	if len(disabled) == 0 {
+		return diags
+	}
+
+	for _, ch := range brg.Channels {
+		diags = diags.Extend(pruneDisabledReferences(ch.Config, disabled))
+	}
+	for _, rtr := range brg.Routers {
+		diags = diags.Extend(pruneDisabledReferences(rtr.Config, disabled))
+	}
+	for _, trsf := range brg.Transformers {
+		diags = diags.Extend(pruneDisabledReferences(trsf.Config, disabled))
+	}
+	for _, src := range brg.Sources {
+		diags = diags.Extend(pruneDisabledReferences(src.Config, disabled))
+	}
+	for _, trg := range brg.Targets {
+		diags = diags.Extend(pruneDisabledReferences(trg.Config, disabled))
+	}
+
+	if dlv := brg.Delivery; dlv != nil && dlv.DeadLetterSink != nil {
+		if a, ok := referencedAddr(dlv.DeadLetterSink); ok {
+			if _, isDisabled := disabled[a]; isDisabled {
+				diags = diags.Append(disabledReferenceDiagnostic(a, dlv.DeadLetterSink.SourceRange()))
+			}
+		}
+	}
+
+	return diags
+}
+
+// evalEnabled evaluates the value of an "enabled" meta-attribute.
+func evalEnabled(expr hcl.Expression, evalCtx *hcl.EvalContext) (bool, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	val, evalDiags := expr.Value(evalCtx)
+	diags = diags.Extend(evalDiags)
+	if evalDiags.HasErrors() {
+		return true, diags
+	}
+
+	val, err := convert.Convert(val, cty.Bool)
+	if err != nil || val.IsNull() || !val.IsKnown() {
+		return true, diags.Append(wrongTypeDiagnostic(val, "bool", expr.Range()))
+	}
+
+	return val.True(), diags
+}
+
+// pruneDisabledReferences removes references to disabled components from the
+// lists of references contained in the given configuration body, and reports
+// any other reference to a disabled component.
+//
+// The body is modified in place. Only native syntax bodies are supported.
+func pruneDisabledReferences(b hcl.Body, disabled map[string]struct{}) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	body, ok := b.(*hclsyntax.Body)
+	if !ok {
+		return diags
+	}
+
+	_ = hclsyntax.VisitAll(body, func(n hclsyntax.Node) hcl.Diagnostics {
+		switch e := n.(type) {
+		case *hclsyntax.TupleConsExpr:
+			// Elements are filtered before the visitor descends into
+			// them, so that removed references aren't reported.
+			kept := e.Exprs[:0]
+			for _, elem := range e.Exprs {
+				if isDisabledReference(elem, disabled) {
+					continue
+				}
+				kept = append(kept, elem)
+			}
+			e.Exprs = kept
+
+		case *hclsyntax.ScopeTraversalExpr:
+			if isDisabledReference(e, disabled) {
+				a, _ := referencedAddr(e.Traversal)
+				diags = diags.Append(disabledReferenceDiagnostic(a, e.SrcRange))
+			}
+		}
+
+		return nil
+	})
+
+	return diags
+}
+
+// isDisabledReference returns whether the given expression is a reference to
+// one of the given disabled components.
+func isDisabledReference(expr hclsyntax.Expression, disabled map[string]struct{}) bool {
+	st, ok := expr.(*hclsyntax.ScopeTraversalExpr)
+	if !ok {
+		return false
+	}
+
+	a, ok := referencedAddr(st.Traversal)
+	if !ok {
+		return false
+	}
+
+	_, isDisabled := disabled[a]
+	return isDisabled
+}
+
+// referencedAddr returns the address of the component referenced by the given
+// traversal, e.g. "target.my_target".
+func referencedAddr(t hcl.Traversal) (string, bool) {
+	if len(t) < 2 || t.IsRelative() {
+		return "", false
+	}
+
+	attr, ok := t[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+
+	return t.RootName() + "." + attr.Name, true
+}
@@ -0,0 +1,24 @@
+# This file contains a Bridge description with defaults which apply to
+# components of a given category and type.
+
+bridge "defaults" {
+  defaults source some_source {
+    to          = channel.MyChannel
+    description = "default description"
+  }
+
+  defaults target some_target {
+    #! defaults which don't match any component are reported
+  }
+}
+
+source some_source "InheritingSource" {
+}
+
+source some_source "OverridingSource" {
+  description = "explicit description"
+}
+
+channel some_channel "MyChannel" {
+  subscribers = []
+}
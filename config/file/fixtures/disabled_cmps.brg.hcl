@@ -0,0 +1,22 @@
+# This file contains a Bridge description in which a disabled component is
+# referenced by other components.
+
+variable "debug" {
+  default = false
+}
+
+source some_source "MySource" {
+  #! references to disabled components can't be resolved
+  to = target.DebugTarget
+}
+
+channel some_channel "MyChannel" {
+  subscribers = [target.SomeTarget, target.DebugTarget]
+}
+
+target some_target "SomeTarget" {
+}
+
+target some_target "DebugTarget" {
+  enabled = var.debug
+}
@@ -0,0 +1,15 @@
+# This file contains a Bridge description which requires an unsupported
+# version of the interpreter.
+
+bridge "some_bridge_id" {
+  #! no interpreter can have such a version
+  required_til_version = ">= 999.0"
+}
+
+source some_source "MySource" {
+  some_block { }
+
+  some_attribute = "xyz"
+
+  to = target.SomeTarget
+}
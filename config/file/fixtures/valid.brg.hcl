@@ -4,13 +4,34 @@
 # one valid occurence of each supported block type and top-level attribute.
 
 bridge "some_bridge" {
+  description = "Some Bridge"
+  owner       = "some-team"
+
+  required_til_version = ">= 0.1"
+
   delivery {
     retries = 2
     dead_letter_sink = channel.foo
   }
+
+  rbac {
+    service_account = "per_bridge"
+  }
+
+  defaults source some_source {
+    some_attribute = "default"
+  }
+}
+
+variable "some_variable" {
+  default     = true
+  description = "Some variable"
 }
 
 source some_source "MySource" {
+  description = "Some source"
+  owner       = "some-team"
+
   some_block { }
 
   some_attribute = "xyz"
@@ -39,6 +60,8 @@ channel some_channel "MyChannel" {
 }
 
 target sometarget "MyTarget" {
+  enabled = var.some_variable
+
   some_block { }
 
   some_attribute = "xyz"
@@ -33,6 +33,16 @@ import (
 type Parser struct {
 	*hclparse.Parser
 	FS fs.FS
+
+	// Values of input variables provided by the user, indexed by variable
+	// name. Those take precedence over the default values declared in the
+	// Bridge.
+	Variables map[string]string
+
+	// Whether all components should be loaded regardless of the value of
+	// their "enabled" meta-attribute. Useful for operating on the source
+	// code of a Bridge rather than on its deployable form.
+	IgnoreEnabled bool
 }
 
 // NewParser returns an new Parser initialized with a fs.FS backed by the OS.
@@ -65,6 +75,11 @@ func (p *Parser) LoadBridge(filePath string) (*config.Bridge, hcl.Diagnostics) {
 		Path: absFilePath,
 	}
 	diags = decodeBridge(hclFile.Body, brg)
+	diags = diags.Extend(setVariables(brg, p.Variables))
+
+	if !p.IgnoreEnabled {
+		diags = diags.Extend(removeDisabledComponents(brg))
+	}
 
 	return brg, diags
 }
@@ -24,6 +24,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 
 	. "til/config/file"
 	"til/fs"
@@ -40,6 +41,9 @@ const (
 	bridgeMissingAttrs = "missing_attrs.brg.hcl"
 	bridgeDuplIDs      = "dupl_ids.brg.hcl"
 	bridgeDuplGlobals  = "dupl_globals.brg.hcl"
+	bridgeUnsuppVer    = "unsupp_version.brg.hcl"
+	bridgeDisabledCmps = "disabled_cmps.brg.hcl"
+	bridgeDefaults     = "defaults.brg.hcl"
 )
 
 func TestLoadBridge(t *testing.T) {
@@ -69,6 +73,23 @@ func TestLoadBridge(t *testing.T) {
 		if n := len(brg.Targets); n != 1 {
 			t.Error("Expected 1 target, got", n)
 		}
+
+		if brg.Description != "Some Bridge" || brg.Owner != "some-team" {
+			t.Errorf("Unexpected documentation of the Bridge: %q, %q", brg.Description, brg.Owner)
+		}
+		for _, src := range brg.Sources {
+			if src.Description != "Some source" || src.Owner != "some-team" {
+				t.Errorf("Unexpected documentation of the source: %q, %q", src.Description, src.Owner)
+			}
+		}
+
+		if n := len(brg.Variables); n != 1 {
+			t.Error("Expected 1 variable, got", n)
+		}
+
+		if brg.RBAC == nil || brg.RBAC.ServiceAccount != "per_bridge" {
+			t.Errorf("Unexpected RBAC options of the Bridge: %+v", brg.RBAC)
+		}
 	})
 
 	t.Run("with unknown block", func(t *testing.T) {
@@ -306,6 +327,139 @@ func TestLoadBridge(t *testing.T) {
 			t.Error("Expected 1 source, got", n)
 		}
 	})
+
+	t.Run("with unsupported interpreter version", func(t *testing.T) {
+		brg, diags := p.LoadBridge(bridgeUnsuppVer)
+
+		errDiags := diags.Errs()
+
+		const expectNumErrDiags = 1
+		if len(errDiags) != expectNumErrDiags {
+			t.Fatalf("Expected %d error diagnostic:\n%s", expectNumErrDiags, errDiagsAsString(diags))
+		}
+
+		if errDiags[0].(*hcl.Diagnostic).Summary != "Unsupported interpreter version" {
+			t.Fatal("Unexpected type of error diagnostic:", errDiags[0])
+		}
+
+		if errDiags[0].(*hcl.Diagnostic).Subject.Start.Line != 6 {
+			t.Fatal("Unexpected location of error diagnostic:", errDiags[0])
+		}
+
+		if n := len(brg.Sources); n != 1 {
+			t.Error("Expected 1 source, got", n)
+		}
+	})
+}
+
+func TestLoadBridgeDisabledComponents(t *testing.T) {
+	fixtureFS := populatedFixtureFS(t)
+
+	t.Run("with default variable values", func(t *testing.T) {
+		p := &Parser{
+			Parser: hclparse.NewParser(),
+			FS:     fixtureFS,
+		}
+
+		brg, diags := p.LoadBridge(bridgeDisabledCmps)
+
+		errDiags := diags.Errs()
+
+		const expectNumErrDiags = 1
+		if len(errDiags) != expectNumErrDiags {
+			t.Fatalf("Expected %d error diagnostic:\n%s", expectNumErrDiags, errDiagsAsString(diags))
+		}
+
+		if errDiags[0].(*hcl.Diagnostic).Summary != "Reference to disabled component" {
+			t.Fatal("Unexpected type of error diagnostic:", errDiags[0])
+		}
+
+		if errDiags[0].(*hcl.Diagnostic).Subject.Start.Line != 10 {
+			t.Fatal("Unexpected location of error diagnostic:", errDiags[0])
+		}
+
+		if n := len(brg.Targets); n != 1 {
+			t.Error("Expected 1 target, got", n)
+		}
+
+		for _, ch := range brg.Channels {
+			subs := ch.Config.(*hclsyntax.Body).Attributes["subscribers"].Expr.(*hclsyntax.TupleConsExpr)
+			if n := len(subs.Exprs); n != 1 {
+				t.Error("Expected 1 subscriber, got", n)
+			}
+		}
+	})
+
+	t.Run("with user-provided variable values", func(t *testing.T) {
+		p := &Parser{
+			Parser:    hclparse.NewParser(),
+			FS:        fixtureFS,
+			Variables: map[string]string{"debug": "true"},
+		}
+
+		brg, diags := p.LoadBridge(bridgeDisabledCmps)
+		if diags.HasErrors() {
+			t.Fatalf("Returned error diagnostics:\n%s", errDiagsAsString(diags))
+		}
+
+		if n := len(brg.Targets); n != 2 {
+			t.Error("Expected 2 targets, got", n)
+		}
+	})
+
+	t.Run("with undeclared variable", func(t *testing.T) {
+		p := &Parser{
+			Parser:    hclparse.NewParser(),
+			FS:        fixtureFS,
+			Variables: map[string]string{"undeclared": "true"},
+		}
+
+		_, diags := p.LoadBridge(bridgeDisabledCmps)
+
+		errDiags := diags.Errs()
+		if len(errDiags) == 0 || errDiags[0].(*hcl.Diagnostic).Summary != "Undeclared variable" {
+			t.Fatalf("Expected an undeclared variable diagnostic:\n%s", errDiagsAsString(diags))
+		}
+	})
+}
+
+func TestLoadBridgeDefaults(t *testing.T) {
+	p := &Parser{
+		Parser: hclparse.NewParser(),
+		FS:     populatedFixtureFS(t),
+	}
+
+	brg, diags := p.LoadBridge(bridgeDefaults)
+	if diags.HasErrors() {
+		t.Fatalf("Returned error diagnostics:\n%s", errDiagsAsString(diags))
+	}
+
+	if len(diags) != 1 || diags[0].Summary != "Unused defaults" {
+		t.Fatalf("Expected a single unused defaults diagnostic, got:\n%v", diags)
+	}
+	if diags[0].Subject.Start.Line != 10 {
+		t.Error("Unexpected location of warning diagnostic:", diags[0])
+	}
+
+	if n := len(brg.Defaults); n != 2 {
+		t.Fatal("Expected 2 defaults, got", n)
+	}
+
+	expectDescriptions := map[string]string{
+		"InheritingSource": "default description",
+		"OverridingSource": "explicit description",
+	}
+
+	for _, src := range brg.Sources {
+		if expect := expectDescriptions[src.Identifier]; src.Description != expect {
+			t.Errorf("Expected source %q to have the description %q, got %q",
+				src.Identifier, expect, src.Description)
+		}
+
+		if src.To.RootName() != "channel" {
+			t.Errorf("Expected source %q to inherit its destination, got %v", src.Identifier, src.To)
+		}
+	}
 }
 
 // errDiagsAsString returns a string representation of all given error
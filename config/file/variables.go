@@ -0,0 +1,90 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"til/config"
+)
+
+// Root name of traversals which reference input variables.
+const varRoot = "var"
+
+// setVariables sets the values of the input variables of a Bridge from the
+// given user-provided values, which take precedence over default values.
+//
+// User-provided values are converted to the type of the corresponding default
+// value, if any, so that e.g. "true" can be assigned to a boolean variable.
+func setVariables(brg *config.Bridge, vals map[string]string) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	// sort for predictable diagnostics
+	names := make([]string, 0, len(vals))
+	for n := range vals {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		v, declared := brg.Variables[n]
+		if !declared {
+			diags = diags.Append(undeclaredVariableDiagnostic(n))
+			continue
+		}
+
+		val := cty.StringVal(vals[n])
+
+		if def := v.Value; !def.IsNull() && def.Type() != cty.String {
+			conv, err := convert.Convert(val, def.Type())
+			if err != nil {
+				diags = diags.Append(badVariableValueDiagnostic(n, def.Type(), err, v.SourceRange))
+				continue
+			}
+			val = conv
+		}
+
+		v.Value = val
+	}
+
+	for _, v := range brg.Variables {
+		if v.Value.IsNull() {
+			diags = diags.Append(missingVariableValueDiagnostic(v.Identifier, v.SourceRange))
+		}
+	}
+
+	return diags
+}
+
+// variablesEvalContext returns a hcl.EvalContext which exposes the input
+// variables of the given Bridge as attributes of the "var" object.
+func variablesEvalContext(brg *config.Bridge) *hcl.EvalContext {
+	vars := make(map[string]cty.Value, len(brg.Variables))
+	for n, v := range brg.Variables {
+		vars[n] = v.Value
+	}
+
+	return &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			varRoot: cty.ObjectVal(vars),
+		},
+	}
+}
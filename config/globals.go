@@ -21,20 +21,48 @@ import "github.com/hashicorp/hcl/v2"
 // HCL blocks supported in global settings ("bridge" block).
 const (
 	BlkDelivery = "delivery"
+	BlkDefaults = "defaults"
+	BlkRBAC     = "rbac"
+)
+
+// Identifiers for the labels of a "bridge.defaults" block.
+const (
+	LblCategory = "category"
 )
 
 // Block attributes that can appear in global settings (sub-blocks of "bridge" block).
 const (
 	AttrRetries        = "retries"
 	AttrDeadLetterSink = "dead_letter_sink"
+	AttrServiceAccount = "service_account"
+)
+
+// Block attributes that can appear in the "bridge" block.
+const (
+	AttrRequiredVersion = "required_til_version"
 )
 
 // BridgeBlockSchema is the shallow structure of a "bridge" block.
 // There can be at most one such block declared inside a Bridge.
 // Used for validation during decoding.
 var BridgeBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{
+		Name:     AttrDescription,
+		Required: false,
+	}, {
+		Name:     AttrOwner,
+		Required: false,
+	}, {
+		Name:     AttrRequiredVersion,
+		Required: false,
+	}},
 	Blocks: []hcl.BlockHeaderSchema{{
 		Type: BlkDelivery,
+	}, {
+		Type:       BlkDefaults,
+		LabelNames: []string{LblCategory, LblType},
+	}, {
+		Type: BlkRBAC,
 	}},
 }
 
@@ -50,8 +78,50 @@ var DeliveryBlockSchema = &hcl.BodySchema{
 	}},
 }
 
+// RBACBlockSchema is the shallow structure of a "bridge.rbac" block.
+// Used for validation during decoding.
+var RBACBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{
+		Name:     AttrServiceAccount,
+		Required: false,
+	}},
+}
+
 // Delivery represents the message delivery options which apply to the Bridge.
 type Delivery struct {
 	Retries        *int64
 	DeadLetterSink hcl.Traversal
 }
+
+// Defaults represents default settings which apply to all components of a
+// given category and type.
+//
+// The attributes and blocks of a Defaults' configuration are merged into the
+// configuration of every matching component, unless that component sets them
+// explicitly.
+type Defaults struct {
+	Category ComponentCategory
+	Type     string
+
+	// Default configuration of matching components.
+	Config hcl.Body
+
+	// Source location of the block.
+	SourceRange hcl.Range
+}
+
+// Supported scopes of the ServiceAccounts generated for a Bridge.
+const (
+	ServiceAccountPerComponent = "per_component"
+	ServiceAccountPerBridge    = "per_bridge"
+)
+
+// RBAC represents the access control options which apply to the Bridge.
+//
+// When set, a dedicated ServiceAccount is generated for the workloads of the
+// Bridge, and granted the permissions these workloads require.
+type RBAC struct {
+	// Scope of the generated ServiceAccounts.
+	// Either ServiceAccountPerComponent or ServiceAccountPerBridge.
+	ServiceAccount string
+}
@@ -20,7 +20,18 @@ import "github.com/hashicorp/hcl/v2"
 
 // RouterBlockSchema is the shallow structure of a "router" block.
 // Used for validation during decoding.
-var RouterBlockSchema = &hcl.BodySchema{}
+var RouterBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{
+		Name:     AttrDescription,
+		Required: false,
+	}, {
+		Name:     AttrOwner,
+		Required: false,
+	}, {
+		Name:     AttrEnabled,
+		Required: false,
+	}},
+}
 
 // Router represents a generic message router.
 type Router struct {
@@ -29,6 +40,15 @@ type Router struct {
 	// An identifier that is unique among all Routers within a Bridge.
 	Identifier string
 
+	// Human-readable documentation of the router.
+	Description string
+	// Person or team responsible for the router.
+	Owner string
+
+	// Expression which determines whether the router is part of the Bridge.
+	// Nil if the router is unconditionally enabled.
+	Enabled hcl.Expression
+
 	// Configuration of the router.
 	Config hcl.Body
 
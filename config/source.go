@@ -24,6 +24,15 @@ var SourceBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{{
 		Name:     AttrTo,
 		Required: true,
+	}, {
+		Name:     AttrDescription,
+		Required: false,
+	}, {
+		Name:     AttrOwner,
+		Required: false,
+	}, {
+		Name:     AttrEnabled,
+		Required: false,
 	}},
 }
 
@@ -37,6 +46,15 @@ type Source struct {
 	// Destination of events.
 	To hcl.Traversal
 
+	// Human-readable documentation of the source.
+	Description string
+	// Person or team responsible for the source.
+	Owner string
+
+	// Expression which determines whether the source is part of the Bridge.
+	// Nil if the source is unconditionally enabled.
+	Enabled hcl.Expression
+
 	// Configuration of the source.
 	Config hcl.Body
 
@@ -24,6 +24,15 @@ var TargetBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{{
 		Name:     AttrReplyTo,
 		Required: false,
+	}, {
+		Name:     AttrDescription,
+		Required: false,
+	}, {
+		Name:     AttrOwner,
+		Required: false,
+	}, {
+		Name:     AttrEnabled,
+		Required: false,
 	}},
 }
 
@@ -37,6 +46,15 @@ type Target struct {
 	// Destination of event responses.
 	ReplyTo hcl.Traversal
 
+	// Human-readable documentation of the target.
+	Description string
+	// Person or team responsible for the target.
+	Owner string
+
+	// Expression which determines whether the target is part of the Bridge.
+	// Nil if the target is unconditionally enabled.
+	Enabled hcl.Expression
+
 	// Configuration of the target.
 	Config hcl.Body
 
@@ -24,6 +24,15 @@ var TransformerBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{{
 		Name:     AttrTo,
 		Required: true,
+	}, {
+		Name:     AttrDescription,
+		Required: false,
+	}, {
+		Name:     AttrOwner,
+		Required: false,
+	}, {
+		Name:     AttrEnabled,
+		Required: false,
 	}},
 }
 
@@ -37,6 +46,15 @@ type Transformer struct {
 	// Destination of events.
 	To hcl.Traversal
 
+	// Human-readable documentation of the transformer.
+	Description string
+	// Person or team responsible for the transformer.
+	Owner string
+
+	// Expression which determines whether the transformer is part of the Bridge.
+	// Nil if the transformer is unconditionally enabled.
+	Enabled hcl.Expression
+
 	// Configuration of the transformer.
 	Config hcl.Body
 
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Block attributes that can appear in a "variable" block.
+const (
+	AttrDefault = "default"
+)
+
+// VariableBlockSchema is the shallow structure of a "variable" block.
+// Used for validation during decoding.
+var VariableBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{
+		Name:     AttrDefault,
+		Required: false,
+	}, {
+		Name:     AttrDescription,
+		Required: false,
+	}},
+}
+
+// Variable represents an input variable of a Bridge. Variables are referenced
+// as "var.<identifier>" in expressions which support them, such as the
+// "enabled" meta-attribute of components.
+type Variable struct {
+	// An identifier that is unique among all Variables within a Bridge.
+	Identifier string
+
+	// Human-readable documentation of the variable.
+	Description string
+
+	// Value of the variable. Either the default value declared in the
+	// Bridge description, or a value provided by the user.
+	Value cty.Value
+
+	// Source location of the block.
+	SourceRange hcl.Range
+}
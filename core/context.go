@@ -34,6 +34,17 @@ type Context struct {
 
 	// interface used by functions that access the file system
 	FS fs.FS
+
+	// namespace the Bridge is deployed to, if known. Set on all the
+	// generated objects.
+	Namespace string
+
+	// whether NetworkPolicies are generated for the Bridge's workloads
+	NetworkPolicies bool
+	// namespaces of the Knative data plane, from which NetworkPolicies
+	// allow traffic. Defaults to the namespaces of a standard Knative
+	// installation if empty.
+	KnativeNamespaces []string
 }
 
 func NewContext(brg *config.Bridge) (*Context, hcl.Diagnostics) {
@@ -71,14 +82,37 @@ func (c *Context) Generate() ([]interface{}, hcl.Diagnostics) {
 		return nil, diags
 	}
 
-	t := &BridgeTranslator{
+	return c.translator().Translate(g)
+}
+
+// GenerateComponents generates the deployment manifests for a Bridge, grouped
+// by Bridge component.
+func (c *Context) GenerateComponents() ([]*ComponentManifests, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	g, graphDiags := c.Graph()
+	diags = diags.Extend(graphDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return c.translator().TranslateComponents(g)
+}
+
+// translator returns a BridgeTranslator for the Bridge.
+func (c *Context) translator() *BridgeTranslator {
+	return &BridgeTranslator{
 		Impls: c.Impls,
 
 		BaseDir: filepath.Dir(c.Bridge.Path),
 		FS:      c.FS,
 
-		Delivery: c.Bridge.Delivery,
-	}
+		BridgeIdentifier: c.Bridge.Identifier,
+		Delivery:         c.Bridge.Delivery,
+		RBAC:             c.Bridge.RBAC,
+		Namespace:        c.Namespace,
 
-	return t.Translate(g)
+		NetworkPolicies:   c.NetworkPolicies,
+		KnativeNamespaces: c.KnativeNamespaces,
+	}
 }
@@ -35,8 +35,9 @@ func noComponentImplDiagnostic(cmp addr.MessagingComponent) *hcl.Diagnostic {
 	}
 }
 
-// noTranslatableDiagnostic returns a hcl.Diagnostic which indicates that a
-// Translatable interface can not be acquired for a given component type.
+// noTranslatableDiagnostic returns a hcl.Diagnostic which indicates that
+// neither a Translatable nor a TranslatableV2 interface can be acquired for a
+// given component type.
 func noTranslatableDiagnostic(cmp addr.MessagingComponent) *hcl.Diagnostic {
 	return &hcl.Diagnostic{
 		Severity: hcl.DiagError,
@@ -93,3 +94,53 @@ func undecodableDiagnostic(cmp addr.MessagingComponent) *hcl.Diagnostic {
 		Subject: cmp.SourceRange.Ptr(),
 	}
 }
+
+// unsupportedServiceAccountDiagnostic returns a hcl.Diagnostic which indicates
+// that the generated ServiceAccount can't be set on a workload which belongs to
+// a component.
+func unsupportedServiceAccountDiagnostic(cmp addr.MessagingComponent, kind, name string) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Unsupported ServiceAccount",
+		Detail: fmt.Sprintf("The %s %q generated for the %s %q doesn't support a custom ServiceAccount. "+
+			"It runs with the default ServiceAccount of its namespace, which isn't granted the "+
+			"permissions of the generated Role.", kind, name, cmp.Category, cmp.Identifier),
+		Subject: cmp.SourceRange.Ptr(),
+	}
+}
+
+// unselectableWorkloadDiagnostic returns a hcl.Diagnostic which indicates that
+// the workloads of a component can't be selected by NetworkPolicies.
+func unselectableWorkloadDiagnostic(cmp addr.MessagingComponent) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Unselectable workload",
+		Detail: fmt.Sprintf("The workloads of a %s of type %q can't be selected by NetworkPolicies. "+
+			"They aren't protected by any NetworkPolicy, and events they send directly to other "+
+			"components of the Bridge are blocked.", cmp.Category, cmp.Type),
+		Subject: cmp.SourceRange.Ptr(),
+	}
+}
+
+// invalidBridgeManifestDiagnostic returns a hcl.Diagnostic which indicates that
+// the Kubernetes manifests of the Bridge itself could not be generated.
+func invalidBridgeManifestDiagnostic(brgID string, err error) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid manifest",
+		Detail: fmt.Sprintf("Failed to generate the Kubernetes manifests of the Bridge %q: %s",
+			brgID, err),
+	}
+}
+
+// invalidManifestDiagnostic returns a hcl.Diagnostic which indicates that the
+// Kubernetes manifests of a component could not be generated.
+func invalidManifestDiagnostic(cmp addr.MessagingComponent, err error) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid manifest",
+		Detail: fmt.Sprintf("Failed to generate the Kubernetes manifests of the %s %q: %s",
+			cmp.Category, cmp.Identifier, err),
+		Subject: cmp.SourceRange.Ptr(),
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"til/config/addr"
+	"til/graph"
+)
+
+// referenceEdge returns the kind and label of the graph edge formed by a
+// reference from the given vertex.
+func referenceEdge(v graph.Vertex, ref *addr.Reference) (graph.EdgeKind, string) {
+	switch vt := v.(type) {
+	case *SourceVertex, *TransformerVertex:
+		return graph.EdgeKindDestination, ""
+
+	case *TargetVertex:
+		return graph.EdgeKindReply, ""
+
+	case *RouterVertex:
+		attr, body := attributeAt(vt.Router.Config, ref.SourceRange)
+		if attr == nil {
+			return graph.EdgeKindRoute, ""
+		}
+		return graph.EdgeKindRoute, routeFilter(body)
+
+	case *ChannelVertex:
+		attr, _ := attributeAt(vt.Channel.Config, ref.SourceRange)
+		if attr == nil {
+			return graph.EdgeKindDestination, ""
+		}
+
+		switch attr.Name {
+		case "subscribers":
+			return graph.EdgeKindSubscription, ""
+		case "dead_letter_sink":
+			return graph.EdgeKindDeadLetter, ""
+		}
+		return graph.EdgeKindDestination, ""
+	}
+
+	return graph.EdgeKindUnspecified, ""
+}
+
+// attributeAt returns the attribute which expression contains the given source
+// range, together with the body this attribute belongs to. Nested blocks are
+// searched recursively.
+//
+// Returns a nil attribute if the given body isn't a native HCL syntax body, or
+// if no attribute contains the given range.
+func attributeAt(b hcl.Body, rng hcl.Range) (*hclsyntax.Attribute, *hclsyntax.Body) {
+	body, ok := b.(*hclsyntax.Body)
+	if !ok {
+		return nil, nil
+	}
+
+	for _, attr := range body.Attributes {
+		if attr.Expr.Range().Overlaps(rng) {
+			return attr, body
+		}
+	}
+
+	for _, blk := range body.Blocks {
+		if attr, body := attributeAt(blk.Body, rng); attr != nil {
+			return attr, body
+		}
+	}
+
+	return nil, nil
+}
+
+// routeFilter returns a textual representation of the event filters defined
+// by the "attributes" and "condition" attributes of the given body, if any.
+func routeFilter(b *hclsyntax.Body) string {
+	var filters []string
+
+	if attr, ok := b.Attributes["attributes"]; ok {
+		if v, diags := attr.Expr.Value(nil); !diags.HasErrors() && isKnownMap(v) {
+			var ceAttrs []string
+			for k, v := range v.AsValueMap() {
+				if v.IsKnown() && !v.IsNull() && v.Type() == cty.String {
+					ceAttrs = append(ceAttrs, k+": "+v.AsString())
+				}
+			}
+			sort.Strings(ceAttrs)
+			filters = append(filters, ceAttrs...)
+		}
+	}
+
+	if attr, ok := b.Attributes["condition"]; ok {
+		if v, diags := attr.Expr.Value(nil); !diags.HasErrors() && v.IsKnown() && !v.IsNull() && v.Type() == cty.String {
+			filters = append(filters, v.AsString())
+		}
+	}
+
+	return strings.Join(filters, ", ")
+}
+
+// isKnownMap returns whether the given value is a known, non-null map or object.
+func isKnownMap(v cty.Value) bool {
+	if !v.IsWhollyKnown() || v.IsNull() {
+		return false
+	}
+	t := v.Type()
+	return t.IsMapType() || t.IsObjectType()
+}
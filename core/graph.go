@@ -20,6 +20,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 
 	"til/config"
+	"til/config/addr"
 	"til/graph"
 )
 
@@ -85,3 +86,14 @@ const (
 	dotNodeColor4 = "/set26/4"
 	dotNodeColor5 = "/set26/5"
 )
+
+// describeComponent returns a graph.VertexDescription of the given Bridge
+// component.
+func describeComponent(cmp addr.MessagingComponent) graph.VertexDescription {
+	return graph.VertexDescription{
+		Category:    cmp.Category.String(),
+		Type:        cmp.Type,
+		Identifier:  cmp.Identifier,
+		SourceRange: cmp.SourceRange,
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"til/config/file"
+	. "til/core"
+	"til/fs"
+	"til/graph"
+)
+
+func TestGraphEdges(t *testing.T) {
+	const bridgeFile = "test.brg.hcl"
+
+	memFS := fs.NewMemFS()
+	if err := memFS.CreateFile(bridgeFile, []byte(testBridge)); err != nil {
+		t.Fatal("Failed to create Bridge file:", err)
+	}
+
+	p := &file.Parser{
+		Parser: hclparse.NewParser(),
+		FS:     memFS,
+	}
+
+	brg, diags := p.LoadBridge(bridgeFile)
+	if diags.HasErrors() {
+		t.Fatal("Failed to load Bridge:", diags)
+	}
+	cctx, diags := NewContext(brg)
+	if diags.HasErrors() {
+		t.Fatal("Failed to initialize context:", diags)
+	}
+	g, diags := cctx.Graph()
+	if diags.HasErrors() {
+		t.Fatal("Failed to build graph:", diags)
+	}
+
+	type edge struct {
+		tail, head string
+		kind       graph.EdgeKind
+		label      string
+	}
+
+	expectEdges := []edge{
+		{"channel.bus", "router.dispatch", graph.EdgeKindSubscription, ""},
+		{"channel.bus", "target.dls", graph.EdgeKindDeadLetter, ""},
+		{"channel.bus", "target.fn", graph.EdgeKindSubscription, ""},
+		{"router.dispatch", "target.display", graph.EdgeKindRoute, "type: a, $n.(int64) > 0\ntype: b"},
+		{"router.dispatch", "target.dls", graph.EdgeKindDeadLetter, ""},
+		{"source.hook", "channel.bus", graph.EdgeKindDestination, ""},
+		{"source.hook", "target.dls", graph.EdgeKindDeadLetter, ""},
+		{"target.fn", "target.display", graph.EdgeKindReply, ""},
+		{"target.fn", "target.dls", graph.EdgeKindDeadLetter, ""},
+	}
+
+	var edges []edge
+	for _, e := range g.SortedEdges() {
+		edges = append(edges, edge{
+			tail:  graph.NodeFor(e.Tail).ID(),
+			head:  graph.NodeFor(e.Head).ID(),
+			kind:  e.Kind,
+			label: e.Label,
+		})
+	}
+
+	if diff := cmp.Diff(expectEdges, edges, cmp.AllowUnexported(edge{})); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+const testBridge = `
+bridge "test" {
+  delivery {
+    retries          = 2
+    dead_letter_sink = target.dls
+  }
+}
+
+source webhook "hook" {
+  event_type = "my.type"
+
+  to = channel.bus
+}
+
+channel pubsub "bus" {
+  subscribers = [
+    router.dispatch,
+    target.fn
+  ]
+}
+
+router content_based "dispatch" {
+  route {
+    attributes = {
+      type = "a"
+    }
+    condition = "$n.(int64) > 0"
+    to        = target.display
+  }
+
+  route {
+    attributes = {
+      type = "b"
+    }
+    to = target.display
+  }
+}
+
+target function "fn" {
+  runtime = "python"
+  code    = "def main(event, context): return event"
+
+  reply_to = target.display
+}
+
+target event_display "display" {}
+
+target event_display "dls" {}
+`
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"til/config/file"
+	. "til/core"
+	"til/fs"
+)
+
+func TestGenerateLongNames(t *testing.T) {
+	const bridgeFile = "test.brg.hcl"
+
+	brgID := strings.Repeat("my_bridge_", 30) + "x"
+	srcID := strings.Repeat("my_source_", 24) + "x"
+	trgID := strings.Repeat("my_target_", 8) + "x"
+
+	memFS := fs.NewMemFS()
+	if err := memFS.CreateFile(bridgeFile, []byte(longNamesTestBridge(brgID, srcID, trgID))); err != nil {
+		t.Fatal("Failed to create Bridge file:", err)
+	}
+
+	p := &file.Parser{
+		Parser: hclparse.NewParser(),
+		FS:     memFS,
+	}
+
+	brg, diags := p.LoadBridge(bridgeFile)
+	if diags.HasErrors() {
+		t.Fatal("Failed to load Bridge:", diags)
+	}
+	cctx, diags := NewContext(brg)
+	if diags.HasErrors() {
+		t.Fatal("Failed to initialize context:", diags)
+	}
+
+	manifests, diags := cctx.Generate()
+	if diags.HasErrors() {
+		t.Fatal("Failed to generate manifests:", diags)
+	}
+
+	names := make(map[string]struct{}, len(manifests))
+
+	for _, m := range manifests {
+		u := m.(*unstructured.Unstructured)
+		name := u.GetName()
+
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			t.Errorf("Invalid name for %s %q: %v", u.GetKind(), name, errs)
+		}
+		if u.GetKind() == "Service" {
+			if errs := validation.IsDNS1035Label(name); len(errs) > 0 {
+				t.Errorf("Invalid name for Service %q: %v", name, errs)
+			}
+		}
+
+		names[name] = struct{}{}
+	}
+
+	// all references between objects must resolve to a generated object
+	refFields := [][]string{
+		{"spec", "sink", "ref", "name"},
+		{"spec", "channel", "name"},
+		{"spec", "subscriber", "ref", "name"},
+	}
+
+	for _, m := range manifests {
+		u := m.(*unstructured.Unstructured)
+
+		for _, f := range refFields {
+			ref, found, _ := unstructured.NestedString(u.Object, f...)
+			if !found {
+				continue
+			}
+			if _, ok := names[ref]; !ok {
+				t.Errorf("%s %q references unknown object %q at %s",
+					u.GetKind(), u.GetName(), ref, strings.Join(f, "."))
+			}
+		}
+	}
+}
+
+func TestGenerateInvalidName(t *testing.T) {
+	const bridgeFile = "test.brg.hcl"
+
+	// Trailing underscores translate to object names which end with a
+	// dash, which Kubernetes doesn't allow.
+	testCases := map[string]struct {
+		bridge        string
+		expectSubject *hcl.Range // nil for diagnostics about the Bridge itself
+	}{
+		"component identifier": {
+			bridge: `
+target container "display_" {
+  image = "example.com/display"
+}
+`,
+			expectSubject: &hcl.Range{
+				Filename: bridgeFile,
+				Start:    hcl.Pos{Line: 2, Column: 1, Byte: 1},
+				End:      hcl.Pos{Line: 2, Column: 28, Byte: 28},
+			},
+		},
+		"bridge identifier with per-bridge RBAC": {
+			bridge: `
+bridge "my_bridge_" {
+  rbac {
+    service_account = "per_bridge"
+  }
+}
+
+target container "display" {
+  image = "example.com/display"
+}
+`,
+			expectSubject: nil,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			memFS := fs.NewMemFS()
+			if err := memFS.CreateFile(bridgeFile, []byte(tc.bridge)); err != nil {
+				t.Fatal("Failed to create Bridge file:", err)
+			}
+
+			p := &file.Parser{
+				Parser: hclparse.NewParser(),
+				FS:     memFS,
+			}
+
+			brg, diags := p.LoadBridge(bridgeFile)
+			if diags.HasErrors() {
+				t.Fatal("Failed to load Bridge:", diags)
+			}
+			cctx, diags := NewContext(brg)
+			if diags.HasErrors() {
+				t.Fatal("Failed to initialize context:", diags)
+			}
+
+			_, diags = cctx.Generate()
+
+			if len(diags) != 1 || diags[0].Severity != hcl.DiagError || diags[0].Summary != "Invalid manifest" {
+				t.Fatal("Expected a single invalid manifest diagnostic, got:", diags)
+			}
+			if diff := cmp.Diff(tc.expectSubject, diags[0].Subject); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+		})
+	}
+}
+
+func longNamesTestBridge(brgID, srcID, trgID string) string {
+	return `
+bridge "` + brgID + `" {
+  delivery {
+    retries = 2
+  }
+
+  rbac {
+    service_account = "per_bridge"
+  }
+}
+
+source webhook "` + srcID + `" {
+  event_type = "my.type"
+  to         = target.` + trgID + `
+}
+
+target container "` + trgID + `" {
+  image = "example.com/display"
+}
+`
+}
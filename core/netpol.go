@@ -0,0 +1,218 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config"
+	"til/graph"
+	"til/internal/sdk/k8s"
+)
+
+// DefaultKnativeNamespaces are the namespaces of the Knative data plane in a
+// standard installation of Knative Eventing and Knative Serving with the
+// Kourier ingress. The data plane delivers events to the workloads of a
+// Bridge on behalf of its channels and routers, and routes HTTP requests to
+// Knative Services.
+var DefaultKnativeNamespaces = []string{
+	"knative-eventing",
+	"knative-serving",
+	"kourier-system",
+}
+
+// componentKey uniquely identifies a Bridge component.
+type componentKey struct {
+	category   config.ComponentCategory
+	identifier string
+}
+
+// applyNetworkPolicies generates a NetworkPolicy for each workload contained
+// in the given component manifests. Each NetworkPolicy only allows ingress
+// traffic from the workloads of the components which have an edge to the
+// workload's component in the given graph, and from the given namespaces of
+// the Knative data plane.
+//
+// NetworkPolicies are named after the category and identifier of their
+// component, since identifiers are only unique within a category.
+//
+// Components which generate workloads that can't be selected by a
+// NetworkPolicy are reported as warnings, and NetworkPolicies which can't be
+// generated are reported as errors.
+func applyNetworkPolicies(g *graph.DirectedGraph, cmpsManifests []*ComponentManifests,
+	knNamespaces []string) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	workloads := make(map[componentKey][]*unstructured.Unstructured, len(cmpsManifests))
+
+	for _, cm := range cmpsManifests {
+		key := componentKey{category: cm.Component.Category, identifier: cm.Component.Identifier}
+
+		selectable, unselectable := classifyObjects(cm.Manifests)
+		workloads[key] = selectable
+
+		if unselectable {
+			diags = diags.Append(unselectableWorkloadDiagnostic(cm.Component))
+		}
+	}
+
+	predecessors := make(map[componentKey][]componentKey)
+	for _, e := range g.SortedEdges() {
+		tail, ok := e.Tail.(MessagingComponentVertex)
+		if !ok {
+			continue
+		}
+		head, ok := e.Head.(MessagingComponentVertex)
+		if !ok {
+			continue
+		}
+
+		tailAddr, headAddr := tail.ComponentAddr(), head.ComponentAddr()
+		headKey := componentKey{category: headAddr.Category, identifier: headAddr.Identifier}
+		tailKey := componentKey{category: tailAddr.Category, identifier: tailAddr.Identifier}
+
+		predecessors[headKey] = append(predecessors[headKey], tailKey)
+	}
+
+	for _, cm := range cmpsManifests {
+		key := componentKey{category: cm.Component.Category, identifier: cm.Component.Identifier}
+
+		var peers []k8s.NetworkPolicyPeer
+		seenPeers := make(map[string]struct{})
+
+		for _, pred := range predecessors[key] {
+			for _, w := range workloads[pred] {
+				lbls := workloadLabels(w)
+
+				peerID := labelsString(lbls)
+				if _, seen := seenPeers[peerID]; seen {
+					continue
+				}
+				seenPeers[peerID] = struct{}{}
+
+				peers = append(peers, k8s.NetworkPolicyPeer{PodLabels: lbls})
+			}
+		}
+
+		peers = append(peers, k8s.NetworkPolicyPeer{Namespaces: knNamespaces})
+
+		name := cm.Component.Category.String() + "-" + cm.Component.Identifier
+
+		for _, w := range workloads[key] {
+			// the names of components which have several workloads are
+			// disambiguated with the kind and name of each workload
+			npName := name
+			if len(workloads[key]) > 1 {
+				npName += "-" + strings.ToLower(w.GetKind()) + "-" + w.GetName()
+			}
+
+			np, err := newNetworkPolicy(k8s.RFC1123Name(npName), workloadLabels(w), peers)
+			if err != nil {
+				diags = diags.Append(invalidManifestDiagnostic(cm.Component, err))
+				break
+			}
+
+			cm.Manifests = append(cm.Manifests, np)
+		}
+	}
+
+	return diags
+}
+
+// newNetworkPolicy returns a NetworkPolicy with the given attributes, or the
+// error thrown by the manifest SDK if they are invalid.
+func newNetworkPolicy(name string, podLabels map[string]string, from []k8s.NetworkPolicyPeer) (
+	np *unstructured.Unstructured, err error) {
+
+	defer recoverSDKError(func(sdkErr *k8s.Error) {
+		np, err = nil, sdkErr
+	})
+
+	return k8s.NewNetworkPolicy(name, podLabels, from...), nil
+}
+
+// classifyObjects returns the workloads contained in the given manifests which
+// can be selected by a NetworkPolicy, and whether the manifests contain
+// workloads which can't be selected.
+func classifyObjects(manifests []interface{}) (selectable []*unstructured.Unstructured, unselectable bool) {
+	for _, m := range manifests {
+		u, ok := m.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case workloadLabels(u) != nil:
+			selectable = append(selectable, u)
+		case !isPassiveObject(u):
+			unselectable = true
+		}
+	}
+
+	return selectable, unselectable
+}
+
+// workloadLabels returns the labels which select the Pods of the given
+// workload. It returns nil if the object is not a workload, or if its Pods
+// can't be selected.
+func workloadLabels(u *unstructured.Unstructured) map[string]string {
+	switch u.GetAPIVersion() {
+	case k8s.APIServing:
+		if u.GetKind() == "Service" {
+			return map[string]string{
+				"serving.knative.dev/service": u.GetName(),
+			}
+		}
+	case k8s.APISources, k8s.APITargets:
+		// labels set by the TriggerMesh controllers on adapters
+		return map[string]string{
+			"app.kubernetes.io/name":     strings.ToLower(u.GetKind()),
+			"app.kubernetes.io/instance": u.GetName(),
+		}
+	}
+
+	return nil
+}
+
+// isPassiveObject returns whether the given object doesn't run any Pod in the
+// namespace of the Bridge, such as Knative eventing objects, which are
+// implemented by the Knative data plane.
+func isPassiveObject(u *unstructured.Unstructured) bool {
+	switch u.GetAPIVersion() {
+	case k8s.APIEventing, k8s.APIEventingV1Alpha1, k8s.APIMessaging,
+		k8s.APICore, k8s.APIRBAC, k8s.APINetworking:
+		return true
+	}
+
+	return false
+}
+
+// labelsString returns a deterministic string representation of the given
+// labels.
+func labelsString(lbls map[string]string) string {
+	kvs := make([]string, 0, len(lbls))
+	for k, v := range lbls {
+		kvs = append(kvs, k+"="+v)
+	}
+	sort.Strings(kvs)
+
+	return strings.Join(kvs, ",")
+}
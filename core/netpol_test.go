@@ -0,0 +1,184 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config/file"
+	. "til/core"
+	"til/fs"
+)
+
+func TestGenerateNetworkPolicies(t *testing.T) {
+	cctx := netpolTestContext(t)
+
+	manifests, diags := cctx.Generate()
+	if diags.HasErrors() {
+		t.Fatal("Failed to generate manifests:", diags)
+	}
+
+	if len(diags) != 1 || diags[0].Severity != hcl.DiagWarning || diags[0].Summary != "Unselectable workload" {
+		t.Fatal("Expected a single unselectable workload diagnostic, got:", diags)
+	}
+	if line := diags[0].Subject.Start.Line; line != 7 {
+		t.Error("Unexpected location of warning diagnostic:", diags[0])
+	}
+
+	// allowed ingress peers, indexed by NetworkPolicy name
+	peers := make(map[string][]interface{})
+
+	for _, m := range manifests {
+		u := m.(*unstructured.Unstructured)
+		if u.GetKind() != "NetworkPolicy" {
+			continue
+		}
+
+		ingress, _, _ := unstructured.NestedSlice(u.Object, "spec", "ingress")
+		if len(ingress) != 1 {
+			t.Fatalf("Expected NetworkPolicy %q to contain 1 ingress rule, got %d", u.GetName(), len(ingress))
+		}
+		peers[u.GetName()] = ingress[0].(map[string]interface{})["from"].([]interface{})
+	}
+
+	knativePeer := map[string]interface{}{
+		"namespaceSelector": map[string]interface{}{
+			"matchExpressions": []interface{}{
+				map[string]interface{}{
+					"key":      "kubernetes.io/metadata.name",
+					"operator": "In",
+					"values":   []interface{}{"knative-eventing", "knative-serving", "kourier-system"},
+				},
+			},
+		},
+	}
+
+	expectPeers := map[string][]interface{}{
+		"source-hook": {
+			knativePeer,
+		},
+		"target-display": {
+			map[string]interface{}{
+				"podSelector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						"app.kubernetes.io/name":     "webhooksource",
+						"app.kubernetes.io/instance": "hook",
+					},
+				},
+			},
+			knativePeer,
+		},
+	}
+
+	if diff := cmp.Diff(expectPeers, peers); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+func TestGenerateNetworkPoliciesKnativeNamespaces(t *testing.T) {
+	cctx := netpolTestContext(t)
+	cctx.KnativeNamespaces = []string{"my-knative"}
+
+	manifests, diags := cctx.Generate()
+	if diags.HasErrors() {
+		t.Fatal("Failed to generate manifests:", diags)
+	}
+
+	// namespaces allowed by the last ingress peer, indexed by NetworkPolicy name
+	namespaces := make(map[string][]interface{})
+
+	for _, m := range manifests {
+		u := m.(*unstructured.Unstructured)
+		if u.GetKind() != "NetworkPolicy" {
+			continue
+		}
+
+		ingress, _, _ := unstructured.NestedSlice(u.Object, "spec", "ingress")
+		from := ingress[0].(map[string]interface{})["from"].([]interface{})
+		exprs, _, _ := unstructured.NestedSlice(from[len(from)-1].(map[string]interface{}),
+			"namespaceSelector", "matchExpressions")
+		namespaces[u.GetName()] = exprs[0].(map[string]interface{})["values"].([]interface{})
+	}
+
+	expectNamespaces := map[string][]interface{}{
+		"source-hook":    {"my-knative"},
+		"target-display": {"my-knative"},
+	}
+
+	if diff := cmp.Diff(expectNamespaces, namespaces); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+// netpolTestContext returns a Context for netpolTestBridge, with the
+// generation of NetworkPolicies enabled.
+func netpolTestContext(t *testing.T) *Context {
+	t.Helper()
+
+	const bridgeFile = "test.brg.hcl"
+
+	memFS := fs.NewMemFS()
+	if err := memFS.CreateFile(bridgeFile, []byte(netpolTestBridge)); err != nil {
+		t.Fatal("Failed to create Bridge file:", err)
+	}
+
+	p := &file.Parser{
+		Parser: hclparse.NewParser(),
+		FS:     memFS,
+	}
+
+	brg, diags := p.LoadBridge(bridgeFile)
+	if diags.HasErrors() {
+		t.Fatal("Failed to load Bridge:", diags)
+	}
+	cctx, diags := NewContext(brg)
+	if diags.HasErrors() {
+		t.Fatal("Failed to initialize context:", diags)
+	}
+
+	cctx.NetworkPolicies = true
+
+	return cctx
+}
+
+const netpolTestBridge = `
+source webhook "hook" {
+  event_type = "my.type"
+  to         = target.display
+}
+
+transformer function "fn" {
+  runtime = "python"
+  code    = "def main(event, context): return event"
+
+  ce_context {
+    type   = "my.type"
+    source = "fn"
+  }
+
+  to = target.display
+}
+
+target container "display" {
+  image = "example.com/display"
+}
+`
@@ -0,0 +1,251 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config"
+	"til/config/addr"
+	"til/internal/sdk/k8s"
+)
+
+// workloadPolicyRules are the permissions granted to the workloads of a Bridge
+// when RBAC objects are generated. Workloads read their logging, observability
+// and tracing settings from ConfigMaps, and record Kubernetes events.
+var workloadPolicyRules = []k8s.PolicyRule{{
+	APIGroups: []string{""},
+	Resources: []string{"configmaps"},
+	Verbs:     []string{"get", "list", "watch"},
+}, {
+	APIGroups: []string{""},
+	Resources: []string{"events"},
+	Verbs:     []string{"create", "patch"},
+}}
+
+// applyRBAC generates a ServiceAccount, a Role and a RoleBinding for the
+// workloads contained in the given component manifests, and sets the generated
+// ServiceAccount on these workloads.
+//
+// Depending on the given RBAC options, objects are generated either for each
+// component, or once for the entire Bridge. In the latter case, they are
+// appended to the manifests of the component which sorts first by category and
+// identifier, so that the result doesn't depend on the translation order.
+//
+// Per-component objects are named after the category and identifier of their
+// component, since identifiers are only unique within a category.
+//
+// Workloads which already run with a ServiceAccount, such as components which
+// use a cloud workload identity, keep it, and get the generated Role bound to
+// that ServiceAccount instead. Workloads of a kind which doesn't support a
+// custom ServiceAccount keep running with the default ServiceAccount of their
+// namespace, and are reported as warnings.
+//
+// RBAC objects which can't be generated, for instance because the identifier
+// they are named after doesn't yield a valid name, are reported as errors.
+func applyRBAC(cmpsManifests []*ComponentManifests, rbac *config.RBAC, brgID string) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	if rbac == nil || len(cmpsManifests) == 0 {
+		return diags
+	}
+
+	perBridge := rbac.ServiceAccount == config.ServiceAccountPerBridge
+	brgName := k8s.RFC1123Name(brgID)
+
+	var brgSubjects []string
+	brgNeedsSA := false
+
+	for _, cm := range cmpsManifests {
+		name := k8s.RFC1123Name(cm.Component.Category.String() + "-" + cm.Component.Identifier)
+
+		saName := name
+		if perBridge {
+			saName = brgName
+		}
+
+		subjects, needsSA, unsupported := setServiceAccount(cm.Manifests, saName)
+		for _, u := range unsupported {
+			diags = diags.Append(unsupportedServiceAccountDiagnostic(cm.Component, u.GetKind(), u.GetName()))
+		}
+
+		if len(subjects) == 0 {
+			continue
+		}
+
+		if perBridge {
+			brgSubjects = appendUnique(brgSubjects, subjects...)
+			brgNeedsSA = brgNeedsSA || needsSA
+			continue
+		}
+
+		objs, err := rbacManifests(name, needsSA, subjects)
+		if err != nil {
+			diags = diags.Append(invalidManifestDiagnostic(cm.Component, err))
+			continue
+		}
+
+		cm.Manifests = append(cm.Manifests, objs...)
+	}
+
+	if len(brgSubjects) > 0 {
+		first := cmpsManifests[0]
+		for _, cm := range cmpsManifests[1:] {
+			if lessComponent(cm.Component, first.Component) {
+				first = cm
+			}
+		}
+
+		objs, err := rbacManifests(brgName, brgNeedsSA, brgSubjects)
+		if err != nil {
+			return diags.Append(invalidBridgeManifestDiagnostic(brgID, err))
+		}
+
+		first.Manifests = append(first.Manifests, objs...)
+	}
+
+	return diags
+}
+
+// lessComponent returns whether the component a sorts before the component b.
+func lessComponent(a, b addr.MessagingComponent) bool {
+	if a.Category != b.Category {
+		return a.Category < b.Category
+	}
+	return a.Identifier < b.Identifier
+}
+
+// setServiceAccount sets the ServiceAccount with the given name on all
+// workloads which support it and don't already run with a ServiceAccount.
+// It returns the names of the ServiceAccounts the workloads run with, whether
+// the given ServiceAccount is used by at least one of them, and the workloads
+// which don't support a custom ServiceAccount.
+func setServiceAccount(manifests []interface{}, saName string) (subjects []string, used bool,
+	unsupported []*unstructured.Unstructured) {
+
+	for _, m := range manifests {
+		u, ok := m.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+
+		fields, isWorkload := serviceAccountNameFields(u)
+		if !isWorkload {
+			continue
+		}
+		if fields == nil {
+			unsupported = append(unsupported, u)
+			continue
+		}
+
+		if existing, _, _ := unstructured.NestedString(u.Object, fields...); existing != "" {
+			subjects = appendUnique(subjects, existing)
+			continue
+		}
+
+		_ = unstructured.SetNestedField(u.Object, saName, fields...)
+		subjects = appendUnique(subjects, saName)
+		used = true
+	}
+
+	return subjects, used, unsupported
+}
+
+// serviceAccountNameFields returns the path of the field which holds the name
+// of the ServiceAccount of the given workload, and whether the object is a
+// workload at all. The returned path is nil if the object is a workload whose
+// ServiceAccount can't be set.
+func serviceAccountNameFields(u *unstructured.Unstructured) (fields []string, isWorkload bool) {
+	switch u.GetAPIVersion() {
+	case k8s.APIServing:
+		if u.GetKind() == "Service" {
+			return []string{"spec", "template", "spec", "serviceAccountName"}, true
+		}
+	case k8s.APISources, k8s.APITargets:
+		if _, ok := serviceAccountKinds[u.GetKind()]; ok {
+			return []string{"spec", "serviceAccountName"}, true
+		}
+		return nil, true
+	}
+
+	return nil, false
+}
+
+// serviceAccountKinds are the kinds of TriggerMesh sources and targets whose
+// spec supports the serviceAccountName field, which are the ones that can
+// authenticate using a cloud workload identity.
+var serviceAccountKinds = map[string]struct{}{
+	"AWSCloudWatchLogsSource":      {},
+	"AWSCloudWatchSource":          {},
+	"AWSCodeCommitSource":          {},
+	"AWSCognitoUserPoolSource":     {},
+	"AWSDynamoDBSource":            {},
+	"AWSKinesisSource":             {},
+	"AWSPerformanceInsightsSource": {},
+	"AWSS3Source":                  {},
+	"AWSSNSSource":                 {},
+	"AWSSQSSource":                 {},
+	"AzureActivityLogsSource":      {},
+	"AzureBlobStorageSource":       {},
+	"AzureEventHubSource":          {},
+
+	"AWSDynamoDBTarget":          {},
+	"AWSKinesisTarget":           {},
+	"AWSLambdaTarget":            {},
+	"AWSS3Target":                {},
+	"AWSSNSTarget":               {},
+	"AWSSQSTarget":               {},
+	"GoogleCloudFirestoreTarget": {},
+	"GoogleCloudStorageTarget":   {},
+}
+
+// rbacManifests returns the RBAC objects which grant the workload permissions
+// to the given ServiceAccounts. A ServiceAccount with the given name is
+// included if withSA is true.
+func rbacManifests(name string, withSA bool, subjects []string) (manifests []interface{}, err error) {
+	defer recoverSDKError(func(sdkErr *k8s.Error) {
+		manifests, err = nil, sdkErr
+	})
+
+	if withSA {
+		manifests = append(manifests, k8s.NewServiceAccount(name, nil))
+	}
+
+	return append(manifests,
+		k8s.NewRole(name, workloadPolicyRules...),
+		k8s.NewRoleBinding(name, name, subjects...),
+	), nil
+}
+
+// appendUnique appends to the given slice the given elements which it doesn't
+// already contain.
+func appendUnique(ss []string, elems ...string) []string {
+	for _, e := range elems {
+		found := false
+		for _, s := range ss {
+			if s == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ss = append(ss, e)
+		}
+	}
+	return ss
+}
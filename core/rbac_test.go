@@ -0,0 +1,173 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config/file"
+	. "til/core"
+	"til/fs"
+)
+
+func TestGenerateRBAC(t *testing.T) {
+	const bridgeFile = "test.brg.hcl"
+
+	testCases := map[string]struct {
+		saScope       string
+		expectObjects map[string][]string
+		expectSANames map[string]string
+		// summaries of expected warnings, indexed by the source code of
+		// their subject
+		expectWarnings map[string][]string
+	}{
+		"per component": {
+			saScope: "per_component",
+			expectObjects: map[string][]string{
+				"source.a": {"WebhookSource/a"},
+				"source.b": {"ServiceAccount/source-b", "AWSSQSSource/b", "Role/source-b", "RoleBinding/source-b"},
+				"target.a": {"Service/a", "ServiceAccount/target-a", "Role/target-a", "RoleBinding/target-a"},
+				"target.b": {"Service/b", "ServiceAccount/target-b", "Role/target-b", "RoleBinding/target-b"},
+			},
+			expectSANames: map[string]string{
+				"WebhookSource/a": "",
+				"AWSSQSSource/b":  "source-b",
+				"Service/a":       "target-a",
+				"Service/b":       "target-b",
+			},
+			expectWarnings: map[string][]string{
+				`source webhook "a"`: {"Unsupported ServiceAccount"},
+			},
+		},
+		"per bridge": {
+			saScope: "per_bridge",
+			expectObjects: map[string][]string{
+				"source.a": {"WebhookSource/a", "ServiceAccount/test", "Role/test", "RoleBinding/test"},
+				"source.b": {"ServiceAccount/source-b", "AWSSQSSource/b"},
+				"target.a": {"Service/a"},
+				"target.b": {"Service/b"},
+			},
+			expectSANames: map[string]string{
+				"WebhookSource/a": "",
+				"AWSSQSSource/b":  "source-b",
+				"Service/a":       "test",
+				"Service/b":       "test",
+			},
+			expectWarnings: map[string][]string{
+				`source webhook "a"`: {"Unsupported ServiceAccount"},
+			},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			memFS := fs.NewMemFS()
+			if err := memFS.CreateFile(bridgeFile, []byte(rbacTestBridge(tc.saScope))); err != nil {
+				t.Fatal("Failed to create Bridge file:", err)
+			}
+
+			p := &file.Parser{
+				Parser: hclparse.NewParser(),
+				FS:     memFS,
+			}
+
+			brg, diags := p.LoadBridge(bridgeFile)
+			if diags.HasErrors() {
+				t.Fatal("Failed to load Bridge:", diags)
+			}
+			cctx, diags := NewContext(brg)
+			if diags.HasErrors() {
+				t.Fatal("Failed to initialize context:", diags)
+			}
+			cmpsManifests, diags := cctx.GenerateComponents()
+			if diags.HasErrors() {
+				t.Fatal("Failed to generate manifests:", diags)
+			}
+
+			warnings := make(map[string][]string)
+			for _, d := range diags {
+				subj := string(d.Subject.SliceBytes(p.Files()[bridgeFile].Bytes))
+				warnings[subj] = append(warnings[subj], d.Summary)
+			}
+
+			objects := make(map[string][]string)
+			saNames := make(map[string]string)
+
+			for _, cm := range cmpsManifests {
+				for _, m := range cm.Manifests {
+					u := m.(*unstructured.Unstructured)
+					obj := u.GetKind() + "/" + u.GetName()
+
+					cmpAddr := cm.Component.Category.String() + "." + cm.Component.Identifier
+					objects[cmpAddr] = append(objects[cmpAddr], obj)
+
+					switch u.GetKind() {
+					case "Service":
+						saNames[obj], _, _ = unstructured.NestedString(u.Object,
+							"spec", "template", "spec", "serviceAccountName")
+					case "WebhookSource", "AWSSQSSource":
+						saNames[obj], _, _ = unstructured.NestedString(u.Object,
+							"spec", "serviceAccountName")
+					}
+				}
+			}
+
+			if diff := cmp.Diff(tc.expectObjects, objects); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+			if diff := cmp.Diff(tc.expectSANames, saNames); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+			if diff := cmp.Diff(tc.expectWarnings, warnings); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+		})
+	}
+}
+
+func rbacTestBridge(saScope string) string {
+	return `
+bridge "test" {
+  rbac {
+    service_account = "` + saScope + `"
+  }
+}
+
+source webhook "a" {
+  event_type = "com.example.test"
+  to         = target.a
+}
+
+source aws_sqs "b" {
+  arn      = "arn:aws:sqs:us-east-1:123456789012:b"
+  iam_role = "arn:aws:iam::123456789012:role/b"
+  to       = target.b
+}
+
+target container "a" {
+  image = "example.com/a"
+}
+
+target container "b" {
+  image = "example.com/b"
+}
+`
+}
@@ -91,7 +91,7 @@ func (t *ConnectDeadLetterSinkTransformer) Transform(g *graph.DirectedGraph) hcl
 		//	continue
 		//}
 
-		g.Connect(v, dlsV)
+		g.ConnectWithKind(v, dlsV, graph.EdgeKindDeadLetter, "")
 	}
 
 	return diags
@@ -86,11 +86,23 @@ func (t *ConnectReferencesTransformer) Transform(g *graph.DirectedGraph) hcl.Dia
 	rm := NewReferenceMap(vs)
 
 	for _, v := range vs {
-		refs, refDiags := rm.References(v)
+		rfr, ok := v.(ReferencerVertex)
+		if !ok {
+			continue
+		}
+
+		refs, refDiags := rfr.References()
 		diags = diags.Extend(refDiags)
 
 		for _, ref := range refs {
-			g.Connect(v, ref)
+			refV, exists := rm[ref.Subject.Addr()]
+			if !exists {
+				diags = diags.Append(unknownReferenceDiagnostic(ref.Subject, ref.SourceRange))
+				continue
+			}
+
+			kind, label := referenceEdge(v, ref)
+			g.ConnectWithKind(v, refV, kind, label)
 		}
 	}
 
@@ -19,12 +19,15 @@ package core
 import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"til/config"
+	"til/config/addr"
 	"til/config/globals"
 	"til/core/diagnostic"
 	"til/fs"
 	"til/graph"
+	sdkk8s "til/internal/sdk/k8s"
 	"til/lang/k8s"
 	"til/translation"
 )
@@ -39,14 +42,44 @@ type BridgeTranslator struct {
 	FS      fs.FS
 
 	// global Bridge settings
-	Delivery *config.Delivery
+	BridgeIdentifier string
+	Delivery         *config.Delivery
+	RBAC             *config.RBAC
+	Namespace        string
+
+	// whether NetworkPolicies are generated for the Bridge's workloads
+	NetworkPolicies bool
+	// namespaces of the Knative data plane, from which NetworkPolicies
+	// allow traffic. Defaults to the namespaces of a standard Knative
+	// installation if empty.
+	KnativeNamespaces []string
+}
+
+// ComponentManifests is a collection of Kubernetes API objects generated for a
+// given Bridge component.
+type ComponentManifests struct {
+	Component addr.MessagingComponent
+	Manifests []interface{}
 }
 
 // Translate performs the translation.
 func (t *BridgeTranslator) Translate(g *graph.DirectedGraph) ([]interface{}, hcl.Diagnostics) {
-	var diags hcl.Diagnostics
+	cmpsManifests, diags := t.TranslateComponents(g)
 
 	var bridgeManifests []interface{}
+	for _, cm := range cmpsManifests {
+		bridgeManifests = append(bridgeManifests, cm.Manifests...)
+	}
+
+	return bridgeManifests, diags
+}
+
+// TranslateComponents performs the translation, and returns the generated
+// Kubernetes API objects grouped by Bridge component.
+func (t *BridgeTranslator) TranslateComponents(g *graph.DirectedGraph) ([]*ComponentManifests, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	var bridgeManifests []*ComponentManifests
 
 	eval := NewEvaluator(t.BaseDir, t.FS, t.Delivery)
 
@@ -67,18 +100,98 @@ func (t *BridgeTranslator) Translate(g *graph.DirectedGraph) ([]interface{}, hcl
 	// all addresses in the cycle have been determined.
 	sccs := g.StronglyConnectedComponents()
 
+	brgCtx := t.bridgeContext()
+
 	for _, scc := range sccs {
-		manifests, translDiags := translateComponents(eval, scc)
+		manifests, translDiags := translateComponents(eval, scc, brgCtx)
 		diags = diags.Extend(translDiags)
 
 		bridgeManifests = append(bridgeManifests, manifests...)
 	}
 
+	diags = diags.Extend(applyRBAC(bridgeManifests, t.RBAC, t.BridgeIdentifier))
+
+	if t.NetworkPolicies {
+		knNamespaces := t.KnativeNamespaces
+		if len(knNamespaces) == 0 {
+			knNamespaces = DefaultKnativeNamespaces
+		}
+		diags = diags.Extend(applyNetworkPolicies(g, bridgeManifests, knNamespaces))
+	}
+
+	if t.Namespace != "" {
+		setNamespace(bridgeManifests, t.Namespace)
+	}
+
+	setLabels(bridgeManifests, brgCtx.Labels)
+
 	return bridgeManifests, diags
 }
 
+// setNamespace sets the given namespace on all the objects contained in the
+// given component manifests which don't already have one.
+func setNamespace(cmpsManifests []*ComponentManifests, ns string) {
+	for _, cm := range cmpsManifests {
+		for _, m := range cm.Manifests {
+			if u, ok := m.(*unstructured.Unstructured); ok && u.GetNamespace() == "" {
+				u.SetNamespace(ns)
+			}
+		}
+	}
+}
+
+// setLabels sets the given labels on all the objects contained in the given
+// component manifests, including objects which weren't generated by a
+// translator, such as RBAC objects and NetworkPolicies. Labels which are
+// already set on an object are left untouched.
+func setLabels(cmpsManifests []*ComponentManifests, lbls map[string]string) {
+	if len(lbls) == 0 {
+		return
+	}
+
+	for _, cm := range cmpsManifests {
+		for _, m := range cm.Manifests {
+			u, ok := m.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+
+			objLbls := u.GetLabels()
+			if objLbls == nil {
+				objLbls = make(map[string]string, len(lbls))
+			}
+			for k, v := range lbls {
+				if _, isSet := objLbls[k]; !isSet {
+					objLbls[k] = v
+				}
+			}
+
+			u.SetLabels(objLbls)
+		}
+	}
+}
+
+// bridgeContext returns a translation.Context populated with the Bridge-wide
+// information which is passed to translators of components.
+func (t *BridgeTranslator) bridgeContext() *translation.Context {
+	var lbls map[string]string
+	if t.BridgeIdentifier != "" {
+		lbls = map[string]string{
+			translation.LabelBridgeIdentifier: t.BridgeIdentifier,
+		}
+	}
+
+	return &translation.Context{
+		BridgeIdentifier: t.BridgeIdentifier,
+		Namespace:        t.Namespace,
+		Labels:           lbls,
+	}
+}
+
 // translateComponents translates all components from a list of graph vertices.
-func translateComponents(e *Evaluator, vs []graph.Vertex) ([]interface{}, hcl.Diagnostics) {
+func translateComponents(e *Evaluator, vs []graph.Vertex, brgCtx *translation.Context) (
+	[]*ComponentManifests, hcl.Diagnostics) {
+
 	// A deduplicating diagnostic accumulator is used in this particular
 	// part of the translation because HCL bodies are decoded twice below,
 	// therefore the same diagnostic could be returned twice:
@@ -86,7 +199,7 @@ func translateComponents(e *Evaluator, vs []graph.Vertex) ([]interface{}, hcl.Di
 	//  - once to generate Kubernetes manifests
 	diags := diagnostic.NewDedupDiagnostics()
 
-	var manifests []interface{}
+	var manifests []*ComponentManifests
 	var incompleteDecodeQueue []MessagingComponentVertex
 
 	// first pass: push addresses of visited components to the Evaluator,
@@ -118,10 +231,13 @@ func translateComponents(e *Evaluator, vs []graph.Vertex) ([]interface{}, hcl.Di
 			continue
 		}
 
-		res, translDiags := translate(cmp, cfg, evDst, e.Globals())
+		res, translDiags := translate(cmp, cfg, evDst, e.Globals(), brgCtx)
 		diags = diags.Extend(translDiags)
 
-		manifests = append(manifests, res...)
+		manifests = append(manifests, &ComponentManifests{
+			Component: cmp.ComponentAddr(),
+			Manifests: res,
+		})
 	}
 
 	// second pass: remaining components which evaluation was delayed due
@@ -142,38 +258,107 @@ func translateComponents(e *Evaluator, vs []graph.Vertex) ([]interface{}, hcl.Di
 			continue
 		}
 
-		res, translDiags := translate(cmp, cfg, evDst, e.Globals())
+		res, translDiags := translate(cmp, cfg, evDst, e.Globals(), brgCtx)
 		diags = diags.Extend(translDiags)
 
-		manifests = append(manifests, res...)
+		manifests = append(manifests, &ComponentManifests{
+			Component: cmp.ComponentAddr(),
+			Manifests: res,
+		})
 	}
 
 	return manifests, diags.Diagnostics()
 }
 
 // translate invokes the translator of the given component.
-func translate(cmp MessagingComponentVertex, cfg, evDst cty.Value, glb globals.Accessor) (
-	[]interface{}, hcl.Diagnostics) {
-
-	var diags hcl.Diagnostics
+//
+// Component types which implement translation.TranslatableV2 are translated
+// using that interface in priority, with a copy of the given Bridge context
+// extended with information about the component.
+//
+// Errors thrown by the manifest SDK (sdk/k8s.Error) while generating manifests
+// are recovered and returned as diagnostics on the component. Other panics are
+// considered bugs and are propagated.
+func translate(cmp MessagingComponentVertex, cfg, evDst cty.Value, glb globals.Accessor,
+	brgCtx *translation.Context) (manifests []interface{}, diags hcl.Diagnostics) {
 
 	cmpAddr := cmp.ComponentAddr()
 
-	transl, ok := cmp.Implementation().(translation.Translatable)
-	if !ok {
+	defer recoverSDKError(func(err *sdkk8s.Error) {
+		manifests = nil
+		diags = diags.Append(invalidManifestDiagnostic(cmpAddr, err))
+	})
+
+	switch transl := cmp.Implementation().(type) {
+	case translation.TranslatableV2:
+		tctx := *brgCtx
+		tctx.Identifier = cmpAddr.Identifier
+		tctx.Config = cfg
+		tctx.EventDestination = evDst
+		tctx.Globals = glb
+		tctx.SourceRange = cmpAddr.SourceRange
+		tctx.Labels = copyLabels(brgCtx.Labels)
+
+		manifests, diags = transl.ManifestsV2(&tctx)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return manifests, diags
+
+	case translation.Translatable:
+		return transl.Manifests(cmpAddr.Identifier, cfg, evDst, glb), diags
+
+	default:
 		diags = diags.Append(noTranslatableDiagnostic(cmpAddr))
 		return nil, diags
 	}
+}
+
+// recoverSDKError recovers from a panic thrown by the manifest SDK
+// (sdk/k8s.Error), and passes the error to the given handler. Other panics are
+// considered bugs and are propagated.
+//
+// It must be deferred directly, for recover to stop the panic.
+func recoverSDKError(handle func(*sdkk8s.Error)) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	err, ok := r.(*sdkk8s.Error)
+	if !ok {
+		panic(r)
+	}
+	handle(err)
+}
+
+// copyLabels returns a copy of the given labels, so that translators can't
+// alter the labels seen by other translators.
+func copyLabels(lbls map[string]string) map[string]string {
+	if lbls == nil {
+		return nil
+	}
+
+	cpy := make(map[string]string, len(lbls))
+	for k, v := range lbls {
+		cpy[k] = v
+	}
 
-	return transl.Manifests(cmpAddr.Identifier, cfg, evDst, glb), diags
+	return cpy
 }
 
 // appendToEvaluator appends the event address of the given referenceable
 // Bridge component to an Evaluator.
-func appendToEvaluator(e *Evaluator, cmp ReferenceableVertex) hcl.Diagnostics {
-	var diags hcl.Diagnostics
-
+//
+// Errors thrown by the manifest SDK while determining the event address are
+// recovered and returned as diagnostics on the component.
+func appendToEvaluator(e *Evaluator, cmp ReferenceableVertex) (diags hcl.Diagnostics) {
 	cmpAddr := cmp.ComponentAddr()
+
+	defer recoverSDKError(func(err *sdkk8s.Error) {
+		diags = diags.Append(invalidManifestDiagnostic(cmpAddr, err))
+	})
+
 	if e.HasVariable(cmpAddr.Category.String(), cmpAddr.Identifier) {
 		return diags
 	}
@@ -0,0 +1,318 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config/file"
+	. "til/core"
+	"til/fs"
+)
+
+// Ensures that components implementing either version of the translation
+// interface are translated.
+func TestGenerateTranslatableVersions(t *testing.T) {
+	const bridgeFile = "test.brg.hcl"
+
+	memFS := fs.NewMemFS()
+	if err := memFS.CreateFile(bridgeFile, []byte(translTestBridge)); err != nil {
+		t.Fatal("Failed to create Bridge file:", err)
+	}
+
+	p := &file.Parser{
+		Parser: hclparse.NewParser(),
+		FS:     memFS,
+	}
+
+	brg, diags := p.LoadBridge(bridgeFile)
+	if diags.HasErrors() {
+		t.Fatal("Failed to load Bridge:", diags)
+	}
+	cctx, diags := NewContext(brg)
+	if diags.HasErrors() {
+		t.Fatal("Failed to initialize context:", diags)
+	}
+
+	cmpsManifests, diags := cctx.GenerateComponents()
+	if diags.HasErrors() {
+		t.Fatal("Failed to generate manifests:", diags)
+	}
+
+	// kinds of generated objects, indexed by component identifier
+	kinds := make(map[string][]string)
+	var sink string
+
+	for _, cm := range cmpsManifests {
+		for _, m := range cm.Manifests {
+			u := m.(*unstructured.Unstructured)
+			kinds[cm.Component.Identifier] = append(kinds[cm.Component.Identifier], u.GetKind())
+
+			if s, found, _ := unstructured.NestedString(u.Object, "spec", "sink", "ref", "name"); found {
+				sink = s
+			}
+		}
+	}
+
+	expectKinds := map[string][]string{
+		// translation.Translatable
+		"hook": {"WebhookSource"},
+		// translation.TranslatableV2
+		"display": {"Service"},
+	}
+
+	if diff := cmp.Diff(expectKinds, kinds); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+	if sink != "display" {
+		t.Errorf("Expected source to send events to %q, got %q", "display", sink)
+	}
+}
+
+func TestGenerateNamespace(t *testing.T) {
+	const bridgeFile = "test.brg.hcl"
+
+	testCases := map[string]struct {
+		namespace string
+	}{
+		"no namespace": {
+			namespace: "",
+		},
+		"namespace": {
+			namespace: "my-namespace",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			memFS := fs.NewMemFS()
+			if err := memFS.CreateFile(bridgeFile, []byte(translTestBridge)); err != nil {
+				t.Fatal("Failed to create Bridge file:", err)
+			}
+
+			p := &file.Parser{
+				Parser: hclparse.NewParser(),
+				FS:     memFS,
+			}
+
+			brg, diags := p.LoadBridge(bridgeFile)
+			if diags.HasErrors() {
+				t.Fatal("Failed to load Bridge:", diags)
+			}
+			cctx, diags := NewContext(brg)
+			if diags.HasErrors() {
+				t.Fatal("Failed to initialize context:", diags)
+			}
+
+			cctx.Namespace = tc.namespace
+
+			manifests, diags := cctx.Generate()
+			if diags.HasErrors() {
+				t.Fatal("Failed to generate manifests:", diags)
+			}
+
+			for _, m := range manifests {
+				u := m.(*unstructured.Unstructured)
+				if ns := u.GetNamespace(); ns != tc.namespace {
+					t.Errorf("Expected %s %q to have namespace %q, got %q", u.GetKind(), u.GetName(), tc.namespace, ns)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateLabels(t *testing.T) {
+	const bridgeFile = "test.brg.hcl"
+
+	testCases := map[string]struct {
+		bridge      string
+		expectLabel string
+	}{
+		"no bridge identifier": {
+			bridge:      "",
+			expectLabel: "",
+		},
+		"bridge identifier": {
+			bridge: `bridge "my_bridge" {
+  rbac {}
+}`,
+			expectLabel: "my_bridge",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			memFS := fs.NewMemFS()
+			if err := memFS.CreateFile(bridgeFile, []byte(tc.bridge+translTestBridge)); err != nil {
+				t.Fatal("Failed to create Bridge file:", err)
+			}
+
+			p := &file.Parser{
+				Parser: hclparse.NewParser(),
+				FS:     memFS,
+			}
+
+			brg, diags := p.LoadBridge(bridgeFile)
+			if diags.HasErrors() {
+				t.Fatal("Failed to load Bridge:", diags)
+			}
+			cctx, diags := NewContext(brg)
+			if diags.HasErrors() {
+				t.Fatal("Failed to initialize context:", diags)
+			}
+
+			manifests, diags := cctx.Generate()
+			if diags.HasErrors() {
+				t.Fatal("Failed to generate manifests:", diags)
+			}
+
+			for _, m := range manifests {
+				u := m.(*unstructured.Unstructured)
+				if lbl := u.GetLabels()["bridges.triggermesh.io/id"]; lbl != tc.expectLabel {
+					t.Errorf("Expected %s %q to have Bridge label %q, got %q", u.GetKind(), u.GetName(), tc.expectLabel, lbl)
+				}
+			}
+		})
+	}
+}
+
+// Ensures that components which use a workload identity run with distinct
+// ServiceAccounts when they share an identifier across categories.
+func TestGenerateWorkloadIdentity(t *testing.T) {
+	const bridgeFile = "test.brg.hcl"
+
+	testCases := map[string]struct {
+		rbac          string
+		expectObjects map[string][]string
+	}{
+		"without RBAC": {
+			rbac: "",
+			expectObjects: map[string][]string{
+				"source.orders": {"ServiceAccount/source-orders", "AWSSQSSource/orders"},
+				"target.orders": {"ServiceAccount/target-orders", "AWSLambdaTarget/orders"},
+			},
+		},
+		"with RBAC": {
+			rbac: "rbac {}",
+			expectObjects: map[string][]string{
+				"source.orders": {"ServiceAccount/source-orders", "AWSSQSSource/orders",
+					"Role/source-orders", "RoleBinding/source-orders"},
+				"target.orders": {"ServiceAccount/target-orders", "AWSLambdaTarget/orders",
+					"Role/target-orders", "RoleBinding/target-orders"},
+			},
+		},
+	}
+
+	expectSANames := map[string]string{
+		"AWSSQSSource/orders":    "source-orders",
+		"AWSLambdaTarget/orders": "target-orders",
+	}
+	expectRoles := map[string]string{
+		"ServiceAccount/source-orders": "arn:aws:iam::123456789012:role/source",
+		"ServiceAccount/target-orders": "arn:aws:iam::123456789012:role/target",
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			memFS := fs.NewMemFS()
+			if err := memFS.CreateFile(bridgeFile, []byte(identityTestBridge(tc.rbac))); err != nil {
+				t.Fatal("Failed to create Bridge file:", err)
+			}
+
+			p := &file.Parser{
+				Parser: hclparse.NewParser(),
+				FS:     memFS,
+			}
+
+			brg, diags := p.LoadBridge(bridgeFile)
+			if diags.HasErrors() {
+				t.Fatal("Failed to load Bridge:", diags)
+			}
+			cctx, diags := NewContext(brg)
+			if diags.HasErrors() {
+				t.Fatal("Failed to initialize context:", diags)
+			}
+			cmpsManifests, diags := cctx.GenerateComponents()
+			if diags.HasErrors() {
+				t.Fatal("Failed to generate manifests:", diags)
+			}
+
+			objects := make(map[string][]string)
+			saNames := make(map[string]string)
+			roles := make(map[string]string)
+
+			for _, cm := range cmpsManifests {
+				for _, m := range cm.Manifests {
+					u := m.(*unstructured.Unstructured)
+					obj := u.GetKind() + "/" + u.GetName()
+
+					cmpAddr := cm.Component.Category.String() + "." + cm.Component.Identifier
+					objects[cmpAddr] = append(objects[cmpAddr], obj)
+
+					switch u.GetKind() {
+					case "ServiceAccount":
+						roles[obj] = u.GetAnnotations()["eks.amazonaws.com/role-arn"]
+					case "AWSSQSSource", "AWSLambdaTarget":
+						saNames[obj], _, _ = unstructured.NestedString(u.Object, "spec", "serviceAccountName")
+					}
+				}
+			}
+
+			if diff := cmp.Diff(tc.expectObjects, objects); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+			if diff := cmp.Diff(expectSANames, saNames); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+			if diff := cmp.Diff(expectRoles, roles); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+		})
+	}
+}
+
+const translTestBridge = `
+source webhook "hook" {
+  event_type = "my.type"
+  to         = target.display
+}
+
+target event_display "display" {}
+`
+
+func identityTestBridge(rbac string) string {
+	return `
+bridge "test" {
+  ` + rbac + `
+}
+
+source aws_sqs "orders" {
+  arn      = "arn:aws:sqs:us-east-1:123456789012:orders"
+  iam_role = "arn:aws:iam::123456789012:role/source"
+  to       = target.orders
+}
+
+target aws_lambda "orders" {
+  arn      = "arn:aws:lambda:us-east-1:123456789012:function:orders"
+  iam_role = "arn:aws:iam::123456789012:role/target"
+}
+`
+}
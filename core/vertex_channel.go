@@ -47,6 +47,7 @@ var (
 	_ AttachableImplVertex     = (*ChannelVertex)(nil)
 	_ DecodableConfigVertex    = (*ChannelVertex)(nil)
 	_ graph.DOTableVertex      = (*ChannelVertex)(nil)
+	_ graph.DescribableVertex  = (*ChannelVertex)(nil)
 )
 
 // ComponentAddr implements MessagingComponentVertex.
@@ -141,3 +142,8 @@ func (ch *ChannelVertex) Node() graph.DOTNode {
 		},
 	}
 }
+
+// Description implements graph.DescribableVertex.
+func (ch *ChannelVertex) Description() graph.VertexDescription {
+	return describeComponent(ch.ComponentAddr())
+}
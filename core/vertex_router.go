@@ -48,6 +48,7 @@ var (
 	_ AttachableImplVertex     = (*RouterVertex)(nil)
 	_ DecodableConfigVertex    = (*RouterVertex)(nil)
 	_ graph.DOTableVertex      = (*RouterVertex)(nil)
+	_ graph.DescribableVertex  = (*RouterVertex)(nil)
 )
 
 // ComponentAddr implements MessagingComponentVertex.
@@ -142,3 +143,8 @@ func (rtr *RouterVertex) Node() graph.DOTNode {
 		},
 	}
 }
+
+// Description implements graph.DescribableVertex.
+func (rtr *RouterVertex) Description() graph.VertexDescription {
+	return describeComponent(rtr.ComponentAddr())
+}
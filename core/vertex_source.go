@@ -43,6 +43,7 @@ var (
 	_ AttachableImplVertex     = (*SourceVertex)(nil)
 	_ DecodableConfigVertex    = (*SourceVertex)(nil)
 	_ graph.DOTableVertex      = (*SourceVertex)(nil)
+	_ graph.DescribableVertex  = (*SourceVertex)(nil)
 )
 
 // ComponentAddr implements MessagingComponentVertex.
@@ -111,3 +112,8 @@ func (src *SourceVertex) Node() graph.DOTNode {
 		},
 	}
 }
+
+// Description implements graph.DescribableVertex.
+func (src *SourceVertex) Description() graph.VertexDescription {
+	return describeComponent(src.ComponentAddr())
+}
@@ -48,6 +48,7 @@ var (
 	_ AttachableImplVertex     = (*TargetVertex)(nil)
 	_ DecodableConfigVertex    = (*TargetVertex)(nil)
 	_ graph.DOTableVertex      = (*TargetVertex)(nil)
+	_ graph.DescribableVertex  = (*TargetVertex)(nil)
 )
 
 // ComponentAddr implements MessagingComponentVertex.
@@ -152,3 +153,8 @@ func (trg *TargetVertex) Node() graph.DOTNode {
 		},
 	}
 }
+
+// Description implements graph.DescribableVertex.
+func (trg *TargetVertex) Description() graph.VertexDescription {
+	return describeComponent(trg.ComponentAddr())
+}
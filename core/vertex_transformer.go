@@ -48,6 +48,7 @@ var (
 	_ AttachableImplVertex     = (*TransformerVertex)(nil)
 	_ DecodableConfigVertex    = (*TransformerVertex)(nil)
 	_ graph.DOTableVertex      = (*TransformerVertex)(nil)
+	_ graph.DescribableVertex  = (*TransformerVertex)(nil)
 )
 
 // ComponentAddr implements MessagingComponentVertex.
@@ -149,3 +150,8 @@ func (trsf *TransformerVertex) Node() graph.DOTNode {
 		},
 	}
 }
+
+// Description implements graph.DescribableVertex.
+func (trsf *TransformerVertex) Description() graph.VertexDescription {
+	return describeComponent(trsf.ComponentAddr())
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// JSONWriter is a hcl.DiagnosticWriter that writes diagnostics as a JSON
+// document.
+//
+// Each call to WriteDiagnostic or WriteDiagnostics produces a separate
+// document.
+type JSONWriter struct {
+	out io.Writer
+}
+
+var _ hcl.DiagnosticWriter = (*JSONWriter)(nil)
+
+// NewJSONWriter returns a JSONWriter that writes to the given writer.
+func NewJSONWriter(out io.Writer) *JSONWriter {
+	return &JSONWriter{
+		out: out,
+	}
+}
+
+// WriteDiagnostic implements hcl.DiagnosticWriter.
+func (w *JSONWriter) WriteDiagnostic(d *hcl.Diagnostic) error {
+	return w.WriteDiagnostics(hcl.Diagnostics{d})
+}
+
+// WriteDiagnostics implements hcl.DiagnosticWriter.
+func (w *JSONWriter) WriteDiagnostics(diags hcl.Diagnostics) error {
+	doc := jsonDocument{
+		Diagnostics: make([]*jsonDiagnostic, 0, len(diags)),
+	}
+
+	for _, d := range diags {
+		switch d.Severity {
+		case hcl.DiagError:
+			doc.ErrorCount++
+		case hcl.DiagWarning:
+			doc.WarningCount++
+		}
+
+		doc.Diagnostics = append(doc.Diagnostics, &jsonDiagnostic{
+			Severity: severityString(d.Severity),
+			Summary:  d.Summary,
+			Detail:   d.Detail,
+			Subject:  newJSONRange(d.Subject),
+			Context:  newJSONRange(d.Context),
+		})
+	}
+
+	enc := json.NewEncoder(w.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// jsonDocument is the top-level object of the JSON output.
+type jsonDocument struct {
+	ErrorCount   int               `json:"error_count"`
+	WarningCount int               `json:"warning_count"`
+	Diagnostics  []*jsonDiagnostic `json:"diagnostics"`
+}
+
+// jsonDiagnostic is the JSON representation of a hcl.Diagnostic.
+type jsonDiagnostic struct {
+	Severity string     `json:"severity"`
+	Summary  string     `json:"summary"`
+	Detail   string     `json:"detail,omitempty"`
+	Subject  *jsonRange `json:"subject,omitempty"`
+	Context  *jsonRange `json:"context,omitempty"`
+}
+
+// jsonRange is the JSON representation of a hcl.Range.
+type jsonRange struct {
+	Filename string  `json:"filename"`
+	Start    jsonPos `json:"start"`
+	End      jsonPos `json:"end"`
+}
+
+// jsonPos is the JSON representation of a hcl.Pos.
+type jsonPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// newJSONRange returns the JSON representation of the given hcl.Range.
+func newJSONRange(r *hcl.Range) *jsonRange {
+	if r == nil {
+		return nil
+	}
+
+	return &jsonRange{
+		Filename: r.Filename,
+		Start:    jsonPos(r.Start),
+		End:      jsonPos(r.End),
+	}
+}
+
+// severityString returns a lower-case textual representation of the given
+// hcl.DiagnosticSeverity.
+func severityString(s hcl.DiagnosticSeverity) string {
+	switch s {
+	case hcl.DiagError:
+		return "error"
+	case hcl.DiagWarning:
+		return "warning"
+	default:
+		return "invalid"
+	}
+}
@@ -0,0 +1,297 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Metadata about the SARIF specification and the producing tool.
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	sarifToolName = "til"
+	sarifToolURI  = "https://github.com/triggermesh/til"
+
+	// base identifier of artifact URIs which are relative to the root of
+	// the analyzed sources, as expected by code scanning services
+	sarifSrcRoot = "%SRCROOT%"
+)
+
+// SARIFWriter is a hcl.DiagnosticWriter that writes diagnostics as a Static
+// Analysis Results Interchange Format (SARIF) log, which can be uploaded to
+// code scanning services such as GitHub's.
+//
+// Each diagnostic is reported as a result of a rule which is identified by a
+// slug of its summary (e.g. "Unsupported argument" becomes
+// "til/unsupported-argument"), so that results of the same nature are grouped
+// together.
+//
+// The locations of results are expressed relative to the working directory,
+// which is assumed to be the root of the analyzed sources (%SRCROOT%). Files
+// located outside of that directory are referenced by absolute URIs.
+//
+// Each call to WriteDiagnostic or WriteDiagnostics produces a separate log.
+type SARIFWriter struct {
+	out io.Writer
+
+	// absolute path of the source root, empty if undetermined
+	srcRoot string
+}
+
+var _ hcl.DiagnosticWriter = (*SARIFWriter)(nil)
+
+// NewSARIFWriter returns a SARIFWriter that writes to the given writer.
+func NewSARIFWriter(out io.Writer) *SARIFWriter {
+	wd, _ := os.Getwd()
+
+	return &SARIFWriter{
+		out:     out,
+		srcRoot: wd,
+	}
+}
+
+// WriteDiagnostic implements hcl.DiagnosticWriter.
+func (w *SARIFWriter) WriteDiagnostic(d *hcl.Diagnostic) error {
+	return w.WriteDiagnostics(hcl.Diagnostics{d})
+}
+
+// WriteDiagnostics implements hcl.DiagnosticWriter.
+func (w *SARIFWriter) WriteDiagnostics(diags hcl.Diagnostics) error {
+	rules := make(map[string]*sarifRule)
+	results := make([]*sarifResult, 0, len(diags))
+
+	for _, d := range diags {
+		ruleID := sarifRuleID(d.Summary)
+		if _, exists := rules[ruleID]; !exists {
+			rules[ruleID] = &sarifRule{
+				ID:               ruleID,
+				ShortDescription: &sarifMessage{Text: d.Summary},
+			}
+		}
+
+		msg := d.Summary
+		if d.Detail != "" {
+			msg += ": " + d.Detail
+		}
+
+		res := &sarifResult{
+			RuleID:  ruleID,
+			Level:   sarifLevel(d.Severity),
+			Message: &sarifMessage{Text: msg},
+		}
+
+		if d.Subject != nil {
+			res.Locations = []*sarifLocation{{
+				PhysicalLocation: &sarifPhysicalLocation{
+					ArtifactLocation: w.artifactLocation(d.Subject.Filename),
+					Region: &sarifRegion{
+						StartLine:   d.Subject.Start.Line,
+						StartColumn: d.Subject.Start.Column,
+						EndLine:     d.Subject.End.Line,
+						EndColumn:   d.Subject.End.Column,
+					},
+				},
+			}}
+		}
+
+		results = append(results, res)
+	}
+
+	run := &sarifRun{
+		Tool: &sarifTool{
+			Driver: &sarifDriver{
+				Name:           sarifToolName,
+				InformationURI: sarifToolURI,
+				Rules:          sortedRules(rules),
+			},
+		},
+		Results: results,
+	}
+
+	if w.srcRoot != "" {
+		run.OriginalURIBaseIDs = map[string]*sarifArtifactLocation{
+			sarifSrcRoot: {URI: fileURI(w.srcRoot) + "/"},
+		}
+	}
+
+	log := &sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []*sarifRun{run},
+	}
+
+	enc := json.NewEncoder(w.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// artifactLocation returns the location of the given file, relative to the
+// source root whenever possible.
+func (w *SARIFWriter) artifactLocation(filename string) *sarifArtifactLocation {
+	if w.srcRoot == "" {
+		return &sarifArtifactLocation{URI: filepath.ToSlash(filename)}
+	}
+
+	abs := filename
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(w.srcRoot, abs)
+	}
+
+	rel, err := filepath.Rel(w.srcRoot, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return &sarifArtifactLocation{URI: fileURI(abs)}
+	}
+
+	return &sarifArtifactLocation{
+		URI:       filepath.ToSlash(rel),
+		URIBaseID: sarifSrcRoot,
+	}
+}
+
+// fileURI returns a "file" URI for the given absolute path.
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		// Windows drive letter
+		path = "/" + path
+	}
+
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// sarifRuleID returns the identifier of the rule matching the given
+// diagnostic summary.
+func sarifRuleID(summary string) string {
+	var sb strings.Builder
+	sb.WriteString(sarifToolName + "/")
+
+	// collapse consecutive non-alphanumeric characters into a single dash
+	dash := false
+	for _, r := range strings.ToLower(summary) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && sb.Len() > len(sarifToolName)+1 {
+				sb.WriteByte('-')
+			}
+			dash = false
+			sb.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+
+	return sb.String()
+}
+
+// sarifLevel returns the SARIF level matching the given
+// hcl.DiagnosticSeverity.
+func sarifLevel(s hcl.DiagnosticSeverity) string {
+	switch s {
+	case hcl.DiagError:
+		return "error"
+	case hcl.DiagWarning:
+		return "warning"
+	default:
+		return "none"
+	}
+}
+
+// sortedRules returns the values of the given map sorted by rule ID.
+func sortedRules(rules map[string]*sarifRule) []*sarifRule {
+	sorted := make([]*sarifRule, 0, len(rules))
+	for _, r := range rules {
+		sorted = append(sorted, r)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	return sorted
+}
+
+// Subset of the SARIF 2.1.0 object model.
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type (
+	sarifLog struct {
+		Version string      `json:"version"`
+		Schema  string      `json:"$schema"`
+		Runs    []*sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool               *sarifTool                        `json:"tool"`
+		OriginalURIBaseIDs map[string]*sarifArtifactLocation `json:"originalUriBaseIds,omitempty"`
+		Results            []*sarifResult                    `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver *sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string       `json:"name"`
+		InformationURI string       `json:"informationUri"`
+		Rules          []*sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID               string        `json:"id"`
+		ShortDescription *sarifMessage `json:"shortDescription"`
+	}
+
+	sarifResult struct {
+		RuleID    string           `json:"ruleId"`
+		Level     string           `json:"level"`
+		Message   *sarifMessage    `json:"message"`
+		Locations []*sarifLocation `json:"locations,omitempty"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation *sarifArtifactLocation `json:"artifactLocation"`
+		Region           *sarifRegion           `json:"region"`
+	}
+
+	sarifArtifactLocation struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId,omitempty"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndLine     int `json:"endLine"`
+		EndColumn   int `json:"endColumn"`
+	}
+)
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Format is an output format for diagnostics.
+//
+// It implements flag.Value so that it can be set from a command-line flag.
+type Format string
+
+// Supported output formats.
+const (
+	// Formatted text, with source snippets.
+	FormatText Format = "text"
+	// JSON document.
+	FormatJSON Format = "json"
+	// Static Analysis Results Interchange Format (SARIF) log.
+	FormatSARIF Format = "sarif"
+)
+
+// Formats returns all supported output formats.
+func Formats() []Format {
+	return []Format{
+		FormatText,
+		FormatJSON,
+		FormatSARIF,
+	}
+}
+
+// String implements flag.Value.
+func (f *Format) String() string {
+	return string(*f)
+}
+
+// Set implements flag.Value.
+func (f *Format) Set(v string) error {
+	for _, supported := range Formats() {
+		if Format(v) == supported {
+			*f = supported
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported format %q. Expected one of [%s]", v, formatsList())
+}
+
+// formatsList returns a comma-separated list of supported formats.
+func formatsList() string {
+	fmts := Formats()
+
+	strs := make([]string, len(fmts))
+	for i, f := range fmts {
+		strs[i] = string(f)
+	}
+
+	return strings.Join(strs, ", ")
+}
+
+// NewWriter returns a hcl.DiagnosticWriter that writes diagnostics to the
+// given writer in the given format.
+//
+// The files are used by the text format to print source snippets. The color
+// option is ignored by formats other than text.
+func NewWriter(f Format, out io.Writer, files map[string]*hcl.File, color bool) hcl.DiagnosticWriter {
+	switch f {
+	case FormatJSON:
+		return NewJSONWriter(out)
+	case FormatSARIF:
+		return NewSARIFWriter(out)
+	default:
+		return NewTextWriter(out, files, color)
+	}
+}
+
+// NewTextWriter returns a hcl.DiagnosticWriter that writes diagnostics to the
+// given writer as formatted text, optionally colored with ANSI escape
+// sequences.
+func NewTextWriter(out io.Writer, files map[string]*hcl.File, color bool) hcl.DiagnosticWriter {
+	const outputWidth = 0
+	return hcl.NewDiagnosticTextWriter(out, files, outputWidth, color)
+}
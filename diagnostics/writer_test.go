@@ -0,0 +1,316 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diagnostics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+
+	. "til/diagnostics"
+)
+
+func TestFormatSet(t *testing.T) {
+	testCases := map[string]struct {
+		in        string
+		expect    Format
+		expectErr bool
+	}{
+		"text": {
+			in:     "text",
+			expect: FormatText,
+		},
+		"json": {
+			in:     "json",
+			expect: FormatJSON,
+		},
+		"sarif": {
+			in:     "sarif",
+			expect: FormatSARIF,
+		},
+		"unsupported format": {
+			in:        "xml",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var f Format
+			err := f.Set(tc.in)
+
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("Expected an error")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal("Unexpected error:", err)
+			}
+			if f != tc.expect {
+				t.Errorf("Expected format %q, got %q", tc.expect, f)
+			}
+		})
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := NewJSONWriter(&buf).WriteDiagnostics(testDiagnostics()); err != nil {
+		t.Fatal("Error writing diagnostics:", err)
+	}
+
+	expect := map[string]interface{}{
+		"error_count":   1.0,
+		"warning_count": 1.0,
+		"diagnostics": []interface{}{
+			map[string]interface{}{
+				"severity": "error",
+				"summary":  "Unsupported argument",
+				"detail":   `An argument named "foo" is not expected here.`,
+				"subject": map[string]interface{}{
+					"filename": "bridges/test.brg.hcl",
+					"start":    map[string]interface{}{"line": 3.0, "column": 3.0, "byte": 30.0},
+					"end":      map[string]interface{}{"line": 3.0, "column": 6.0, "byte": 33.0},
+				},
+				"context": map[string]interface{}{
+					"filename": "bridges/test.brg.hcl",
+					"start":    map[string]interface{}{"line": 2.0, "column": 1.0, "byte": 10.0},
+					"end":      map[string]interface{}{"line": 4.0, "column": 2.0, "byte": 40.0},
+				},
+			},
+			map[string]interface{}{
+				"severity": "warning",
+				"summary":  "Deprecated",
+			},
+		},
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal("Error decoding JSON output:", err)
+	}
+
+	if diff := cmp.Diff(expect, got); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+func TestSARIFWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := NewSARIFWriter(&buf).WriteDiagnostics(testDiagnostics()); err != nil {
+		t.Fatal("Error writing diagnostics:", err)
+	}
+
+	var got struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID  string `json:"ruleId"`
+				Level   string `json:"level"`
+				Message struct {
+					Text string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI       string `json:"uri"`
+							URIBaseID string `json:"uriBaseId"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+							EndLine     int `json:"endLine"`
+							EndColumn   int `json:"endColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal("Error decoding SARIF output:", err)
+	}
+
+	if got.Version != "2.1.0" {
+		t.Errorf("Expected SARIF version 2.1.0, got %q", got.Version)
+	}
+	if len(got.Runs) != 1 {
+		t.Fatalf("Expected 1 run, got %d", len(got.Runs))
+	}
+
+	run := got.Runs[0]
+
+	if run.Tool.Driver.Name != "til" {
+		t.Errorf("Expected tool name til, got %q", run.Tool.Driver.Name)
+	}
+
+	var ruleIDs []string
+	for _, r := range run.Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, r.ID)
+	}
+	expectRuleIDs := []string{"til/deprecated", "til/unsupported-argument"}
+	if diff := cmp.Diff(expectRuleIDs, ruleIDs); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+
+	if len(run.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(run.Results))
+	}
+
+	res := run.Results[0]
+	if res.RuleID != "til/unsupported-argument" || res.Level != "error" {
+		t.Errorf("Unexpected rule ID or level: %q, %q", res.RuleID, res.Level)
+	}
+	if expectMsg := `Unsupported argument: An argument named "foo" is not expected here.`; res.Message.Text != expectMsg {
+		t.Errorf("Expected message %q, got %q", expectMsg, res.Message.Text)
+	}
+	if len(res.Locations) != 1 {
+		t.Fatalf("Expected 1 location, got %d", len(res.Locations))
+	}
+	loc := res.Locations[0].PhysicalLocation
+	if loc.ArtifactLocation.URI != "bridges/test.brg.hcl" || loc.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+		t.Errorf("Unexpected artifact location %+v", loc.ArtifactLocation)
+	}
+	if r := loc.Region; r.StartLine != 3 || r.StartColumn != 3 || r.EndLine != 3 || r.EndColumn != 6 {
+		t.Errorf("Unexpected region %+v", r)
+	}
+
+	if res := run.Results[1]; res.Level != "warning" || len(res.Locations) != 0 {
+		t.Errorf("Unexpected level or locations for warning: %q, %v", res.Level, res.Locations)
+	}
+}
+
+func TestSARIFWriterArtifactLocation(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal("Error getting working directory:", err)
+	}
+
+	testCases := map[string]struct {
+		filename     string
+		expectURI    string
+		expectBaseID string
+	}{
+		"relative path": {
+			filename:     "bridges/test.brg.hcl",
+			expectURI:    "bridges/test.brg.hcl",
+			expectBaseID: "%SRCROOT%",
+		},
+		"absolute path inside working directory": {
+			filename:     filepath.Join(wd, "bridges", "test.brg.hcl"),
+			expectURI:    "bridges/test.brg.hcl",
+			expectBaseID: "%SRCROOT%",
+		},
+		"path outside working directory": {
+			filename:  filepath.Join(filepath.Dir(wd), "test.brg.hcl"),
+			expectURI: "file://" + filepath.ToSlash(filepath.Join(filepath.Dir(wd), "test.brg.hcl")),
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			diag := &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Test",
+				Subject:  &hcl.Range{Filename: tc.filename},
+			}
+
+			var buf bytes.Buffer
+			if err := NewSARIFWriter(&buf).WriteDiagnostic(diag); err != nil {
+				t.Fatal("Error writing diagnostic:", err)
+			}
+
+			var got struct {
+				Runs []struct {
+					OriginalURIBaseIDs map[string]struct {
+						URI string `json:"uri"`
+					} `json:"originalUriBaseIds"`
+					Results []struct {
+						Locations []struct {
+							PhysicalLocation struct {
+								ArtifactLocation struct {
+									URI       string `json:"uri"`
+									URIBaseID string `json:"uriBaseId"`
+								} `json:"artifactLocation"`
+							} `json:"physicalLocation"`
+						} `json:"locations"`
+					} `json:"results"`
+				} `json:"runs"`
+			}
+
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatal("Error decoding SARIF output:", err)
+			}
+
+			run := got.Runs[0]
+
+			loc := run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation
+			if loc.URI != tc.expectURI || loc.URIBaseID != tc.expectBaseID {
+				t.Errorf("Unexpected artifact location %+v", loc)
+			}
+
+			expectSrcRoot := "file://" + filepath.ToSlash(wd) + "/"
+			if got := run.OriginalURIBaseIDs["%SRCROOT%"].URI; got != expectSrcRoot {
+				t.Errorf("Expected source root %q, got %q", expectSrcRoot, got)
+			}
+		})
+	}
+}
+
+// testDiagnostics returns a set of diagnostics used in tests.
+func testDiagnostics() hcl.Diagnostics {
+	return hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Unsupported argument",
+			Detail:   `An argument named "foo" is not expected here.`,
+			Subject: &hcl.Range{
+				Filename: "bridges/test.brg.hcl",
+				Start:    hcl.Pos{Line: 3, Column: 3, Byte: 30},
+				End:      hcl.Pos{Line: 3, Column: 6, Byte: 33},
+			},
+			Context: &hcl.Range{
+				Filename: "bridges/test.brg.hcl",
+				Start:    hcl.Pos{Line: 2, Column: 1, Byte: 10},
+				End:      hcl.Pos{Line: 4, Column: 2, Byte: 40},
+			},
+		},
+		{
+			Severity: hcl.DiagWarning,
+			Summary:  "Deprecated",
+		},
+	}
+}
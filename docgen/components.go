@@ -0,0 +1,198 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docgen
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"til/config"
+)
+
+// component is a generic representation of a messaging component, for the
+// purpose of documenting it.
+type component struct {
+	category    config.ComponentCategory
+	typ         string
+	identifier  string
+	description string
+	owner       string
+
+	config hcl.Body
+}
+
+// components returns all components of the given Bridge, sorted by category
+// and identifier.
+func components(brg *config.Bridge) []*component {
+	var cmps []*component
+
+	for _, ch := range brg.Channels {
+		cmps = append(cmps, &component{
+			category:    config.CategoryChannels,
+			typ:         ch.Type,
+			identifier:  ch.Identifier,
+			description: ch.Description,
+			owner:       ch.Owner,
+			config:      ch.Config,
+		})
+	}
+	for _, rtr := range brg.Routers {
+		cmps = append(cmps, &component{
+			category:    config.CategoryRouters,
+			typ:         rtr.Type,
+			identifier:  rtr.Identifier,
+			description: rtr.Description,
+			owner:       rtr.Owner,
+			config:      rtr.Config,
+		})
+	}
+	for _, trsf := range brg.Transformers {
+		cmps = append(cmps, &component{
+			category:    config.CategoryTransformers,
+			typ:         trsf.Type,
+			identifier:  trsf.Identifier,
+			description: trsf.Description,
+			owner:       trsf.Owner,
+			config:      trsf.Config,
+		})
+	}
+	for _, src := range brg.Sources {
+		cmps = append(cmps, &component{
+			category:    config.CategorySources,
+			typ:         src.Type,
+			identifier:  src.Identifier,
+			description: src.Description,
+			owner:       src.Owner,
+			config:      src.Config,
+		})
+	}
+	for _, trg := range brg.Targets {
+		cmps = append(cmps, &component{
+			category:    config.CategoryTargets,
+			typ:         trg.Type,
+			identifier:  trg.Identifier,
+			description: trg.Description,
+			owner:       trg.Owner,
+			config:      trg.Config,
+		})
+	}
+
+	sort.Slice(cmps, func(i, j int) bool {
+		if cmps[i].category != cmps[j].category {
+			return cmps[i].category < cmps[j].category
+		}
+		return cmps[i].identifier < cmps[j].identifier
+	})
+
+	return cmps
+}
+
+// keySettings returns a short description of the top-level attributes of the
+// given configuration body, sorted by attribute name.
+//
+// Only attributes that have a single-line literal primitive value or a
+// reference to another component are described, since other expressions can
+// only be evaluated in the context of a full translation of the Bridge, and
+// multi-line strings (e.g. code) don't fit in a summary.
+func keySettings(b hcl.Body) []string {
+	body, ok := b.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(body.Attributes))
+	for name := range body.Attributes {
+		// documentation attributes are described separately
+		if name == config.AttrDescription || name == config.AttrOwner {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var settings []string
+
+	for _, name := range names {
+		if s, ok := exprString(body.Attributes[name].Expr); ok {
+			settings = append(settings, name+" = "+s)
+		}
+	}
+
+	return settings
+}
+
+// exprString returns a string representation of the given expression if that
+// expression is either a literal primitive value or a reference.
+func exprString(e hcl.Expression) (string, bool) {
+	if t, diags := hcl.AbsTraversalForExpr(e); !diags.HasErrors() {
+		return traversalString(t), true
+	}
+
+	v, diags := e.Value(nil)
+	if diags.HasErrors() || !v.IsWhollyKnown() || v.IsNull() {
+		return "", false
+	}
+
+	return valueString(v)
+}
+
+// valueString returns a string representation of the given primitive value.
+// Multi-line strings, such as code, are not represented.
+func valueString(v cty.Value) (string, bool) {
+	switch v.Type() {
+	case cty.String:
+		if strings.ContainsRune(v.AsString(), '\n') {
+			return "", false
+		}
+		return strconv.Quote(v.AsString()), true
+	case cty.Number:
+		return v.AsBigFloat().Text('f', -1), true
+	case cty.Bool:
+		if v.True() {
+			return "true", true
+		}
+		return "false", true
+	}
+
+	return "", false
+}
+
+// traversalString returns the string representation of a traversal, such as
+// "target.my_target".
+func traversalString(t hcl.Traversal) string {
+	var b strings.Builder
+
+	for _, step := range t {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			b.WriteString(s.Name)
+		case hcl.TraverseAttr:
+			b.WriteString("." + s.Name)
+		case hcl.TraverseIndex:
+			if str, ok := valueString(s.Key); ok {
+				b.WriteString("[" + str + "]")
+			}
+		}
+	}
+
+	return b.String()
+}
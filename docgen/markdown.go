@@ -0,0 +1,247 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docgen
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"til/config"
+	"til/core"
+	"til/graph/mermaid"
+	"til/secrets"
+)
+
+// Type of router which routing rules are documented.
+const contentBasedRouterType = "content_based"
+
+// Markdown generates the documentation of a Bridge in the Markdown format.
+//
+// The documentation contains the description of the Bridge, a diagram of its
+// event flows, a summary of its components, the routing rules of its
+// content-based routers and the Kubernetes Secrets it depends on.
+func Markdown(cctx *core.Context) ([]byte, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	g, graphDiags := cctx.Graph()
+	diags = diags.Extend(graphDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	diagram, err := mermaid.Marshal(g)
+	if err != nil {
+		return nil, diags.Append(graphEncodingDiagnostic(err))
+	}
+
+	secrs, secrDiags := secrets.Inventory(cctx)
+	diags = diags.Extend(secrDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	brg := cctx.Bridge
+	cmps := components(brg)
+
+	var b bytes.Buffer
+
+	b.WriteString("# " + brg.Identifier + "\n\n")
+	if brg.Description != "" {
+		b.WriteString(brg.Description + "\n\n")
+	}
+	if brg.Owner != "" {
+		b.WriteString("**Owner:** " + brg.Owner + "\n\n")
+	}
+
+	b.WriteString("## Event flow\n\n")
+	b.WriteString("```mermaid\n")
+	b.Write(diagram)
+	b.WriteString("```\n\n")
+
+	writeComponents(&b, cmps)
+	writeRoutingRules(&b, cmps)
+	writeSecrets(&b, secrs)
+
+	return bytes.TrimSuffix(b.Bytes(), []byte{'\n'}), diags
+}
+
+// writeComponents writes a table which summarizes the given components.
+func writeComponents(b *bytes.Buffer, cmps []*component) {
+	b.WriteString("## Components\n\n")
+
+	if len(cmps) == 0 {
+		b.WriteString("This Bridge doesn't contain any component.\n\n")
+		return
+	}
+
+	b.WriteString("| Component | Type | Owner | Description | Key settings |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+
+	for _, cmp := range cmps {
+		settings := keySettings(cmp.config)
+		for i := range settings {
+			settings[i] = inlineCode(settings[i])
+		}
+
+		writeRow(b,
+			cmp.category.String()+"."+cmp.identifier,
+			cmp.typ,
+			cmp.owner,
+			cmp.description,
+			strings.Join(settings, "<br>"),
+		)
+	}
+
+	b.WriteByte('\n')
+}
+
+// writeRoutingRules writes a table of routing rules for each content-based
+// router among the given components.
+func writeRoutingRules(b *bytes.Buffer, cmps []*component) {
+	var rtrs []*component
+	for _, cmp := range cmps {
+		if cmp.category == config.CategoryRouters && cmp.typ == contentBasedRouterType {
+			rtrs = append(rtrs, cmp)
+		}
+	}
+
+	if len(rtrs) == 0 {
+		return
+	}
+
+	b.WriteString("## Routing rules\n\n")
+
+	for _, rtr := range rtrs {
+		b.WriteString("### router." + rtr.identifier + "\n\n")
+
+		body, ok := rtr.config.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+
+		b.WriteString("| # | Attributes | Condition | Destination |\n")
+		b.WriteString("|---|---|---|---|\n")
+
+		var i int
+		for _, blk := range body.Blocks {
+			if blk.Type != "route" {
+				continue
+			}
+			i++
+
+			attrs, cond, to := routeRule(blk.Body)
+			writeRow(b, strconv.Itoa(i), attrs, cond, to)
+		}
+
+		b.WriteByte('\n')
+	}
+}
+
+// routeRule returns a description of the attributes filter, condition and
+// destination of the given "route" block.
+func routeRule(body *hclsyntax.Body) (attrs, cond, to string) {
+	if attr, ok := body.Attributes["attributes"]; ok {
+		if v, diags := attr.Expr.Value(nil); !diags.HasErrors() && v.CanIterateElements() && v.IsWhollyKnown() {
+			var kvs []string
+			for it := v.ElementIterator(); it.Next(); {
+				k, elem := it.Element()
+				if s, ok := valueString(elem); ok {
+					kvs = append(kvs, inlineCode(k.AsString()+" = "+s))
+				}
+			}
+			attrs = strings.Join(kvs, "<br>")
+		}
+	}
+
+	if attr, ok := body.Attributes["condition"]; ok {
+		if v, diags := attr.Expr.Value(nil); !diags.HasErrors() && v.Type() == cty.String && v.IsKnown() && !v.IsNull() {
+			cond = inlineCode(v.AsString())
+		}
+	}
+
+	if attr, ok := body.Attributes[config.AttrTo]; ok {
+		if s, ok := exprString(attr.Expr); ok {
+			to = s
+		}
+	}
+
+	return attrs, cond, to
+}
+
+// writeSecrets writes a table of the given Kubernetes Secrets.
+func writeSecrets(b *bytes.Buffer, secrs []*secrets.Secret) {
+	b.WriteString("## Secrets\n\n")
+
+	if len(secrs) == 0 {
+		b.WriteString("This Bridge doesn't reference any Kubernetes Secret.\n\n")
+		return
+	}
+
+	b.WriteString("| Secret | Keys | Used by |\n")
+	b.WriteString("|---|---|---|\n")
+
+	for _, s := range secrs {
+		keys := make([]string, len(s.Keys))
+		for i, k := range s.Keys {
+			keys[i] = inlineCode(k)
+		}
+
+		usedBy := make([]string, len(s.Components))
+		for i, cmp := range s.Components {
+			usedBy[i] = cmp.Category.String() + "." + cmp.Identifier
+		}
+
+		writeRow(b, s.Name, strings.Join(keys, "<br>"), strings.Join(usedBy, "<br>"))
+	}
+
+	b.WriteByte('\n')
+}
+
+// inlineCode formats the given text as a Markdown code span.
+func inlineCode(s string) string {
+	if strings.ContainsRune(s, '`') {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+// writeRow writes a row of a Markdown table.
+func writeRow(b *bytes.Buffer, cells ...string) {
+	b.WriteByte('|')
+	for _, c := range cells {
+		b.WriteString(" " + escapeCell(c) + " |")
+	}
+	b.WriteByte('\n')
+}
+
+// cellEscaper escapes characters which have a special meaning inside cells
+// of Markdown tables.
+var cellEscaper = strings.NewReplacer(
+	"|", `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+)
+
+// escapeCell escapes the given content of a Markdown table cell.
+func escapeCell(s string) string {
+	return cellEscaper.Replace(s)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docgen_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"til/config/file"
+	"til/core"
+	. "til/docgen"
+	"til/fs"
+)
+
+func TestMarkdown(t *testing.T) {
+	const bridgeFile = "test.brg.hcl"
+
+	memFS := fs.NewMemFS()
+	if err := memFS.CreateFile(bridgeFile, []byte(testBridge)); err != nil {
+		t.Fatal("Failed to create Bridge file:", err)
+	}
+
+	p := &file.Parser{
+		Parser: hclparse.NewParser(),
+		FS:     memFS,
+	}
+
+	brg, diags := p.LoadBridge(bridgeFile)
+	if diags.HasErrors() {
+		t.Fatal("Failed to load Bridge:", diags)
+	}
+	cctx, diags := core.NewContext(brg)
+	if diags.HasErrors() {
+		t.Fatal("Failed to initialize context:", diags)
+	}
+
+	out, diags := Markdown(cctx)
+	if diags.HasErrors() {
+		t.Fatal("Failed to generate documentation:", diags)
+	}
+
+	if diff := cmp.Diff(expectMarkdown, string(out)); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+const testBridge = `
+bridge "orders" {
+  description = "Dispatches order events to the teams in charge."
+  owner       = "platform-team"
+}
+
+source aws_sqs "orders_queue" {
+  description = "Queue of order events."
+  owner       = "orders-team"
+
+  arn         = "arn:aws:sqs:us-east-2:123456789012:orders"
+  credentials = secret_name("aws-creds")
+
+  to = router.dispatch
+}
+
+router content_based "dispatch" {
+  route {
+    attributes = {
+      type = "order.created"
+    }
+    to = target.notify
+  }
+
+  route {
+    condition = "$amount.(float64) > 100"
+    to        = target.display
+  }
+}
+
+target slack "notify" {
+  auth = secret_name("slack-token")
+}
+
+target event_display "display" {}
+`
+
+const expectMarkdown = "# orders\n" +
+	"\n" +
+	"Dispatches order events to the teams in charge.\n" +
+	"\n" +
+	"**Owner:** platform-team\n" +
+	"\n" +
+	"## Event flow\n" +
+	"\n" +
+	"```mermaid\n" +
+	"flowchart LR\n" +
+	"    router_dispatch[\"router<br/><b>dispatch</b>\"]\n" +
+	"    source_orders_queue[\"source<br/><b>orders_queue</b>\"]\n" +
+	"    target_display[\"target<br/><b>display</b>\"]\n" +
+	"    target_notify[\"target<br/><b>notify</b>\"]\n" +
+	"\n" +
+	"    router_dispatch -- \"$amount.(float64) #gt; 100\" --> target_display\n" +
+	"    router_dispatch -- \"type: order.created\" --> target_notify\n" +
+	"    source_orders_queue --> router_dispatch\n" +
+	"\n" +
+	"    style router_dispatch fill:#fc8d62,stroke:#fc8d62,color:white\n" +
+	"    style source_orders_queue fill:#e78ac3,stroke:#e78ac3,color:white\n" +
+	"    style target_display fill:#a6d854,stroke:#a6d854,color:white\n" +
+	"    style target_notify fill:#a6d854,stroke:#a6d854,color:white\n" +
+	"```\n" +
+	"\n" +
+	"## Components\n" +
+	"\n" +
+	"| Component | Type | Owner | Description | Key settings |\n" +
+	"|---|---|---|---|---|\n" +
+	"| router.dispatch | content_based |  |  |  |\n" +
+	"| source.orders_queue | aws_sqs | orders-team | Queue of order events. | `arn = \"arn:aws:sqs:us-east-2:123456789012:orders\"`<br>`to = router.dispatch` |\n" +
+	"| target.display | event_display |  |  |  |\n" +
+	"| target.notify | slack |  |  |  |\n" +
+	"\n" +
+	"## Routing rules\n" +
+	"\n" +
+	"### router.dispatch\n" +
+	"\n" +
+	"| # | Attributes | Condition | Destination |\n" +
+	"|---|---|---|---|\n" +
+	"| 1 | `type = \"order.created\"` |  | target.notify |\n" +
+	"| 2 |  | `$amount.(float64) > 100` | target.display |\n" +
+	"\n" +
+	"## Secrets\n" +
+	"\n" +
+	"| Secret | Keys | Used by |\n" +
+	"|---|---|---|\n" +
+	"| aws-creds | `access_key_id`<br>`secret_access_key` | source.orders_queue |\n" +
+	"| slack-token | `token` | target.notify |\n"
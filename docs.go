@@ -0,0 +1,76 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"til/cli"
+	"til/core"
+	"til/docgen"
+)
+
+type DocsCommand struct {
+	// flags
+	variableOptions
+	diagnosticsOptions
+}
+
+// Run implements cli.Command.
+func (c *DocsCommand) Run(ctx context.Context, args []string) error {
+	flagSet := cli.FlagSetFromContext(ctx)
+	setUsageFn(flagSet, usageDocs)
+
+	c.variableOptions.addFlags(flagSet)
+	c.diagnosticsOptions.addFlags(flagSet)
+
+	pos, flags := splitArgs(1, args)
+	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
+
+	if len(pos) != 1 {
+		return fmt.Errorf("unexpected number of positional arguments.\n\n%s", usageDocs(flagSet.Name()))
+	}
+	filePath := pos[0]
+
+	ui := cli.UIFromContext(ctx)
+
+	p := c.variableOptions.newParser()
+	brg, diags := p.LoadBridge(filePath)
+
+	dw := c.diagnosticWriter(ui, p.Files())
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errLoadBridge
+	}
+
+	cctx, diags := core.NewContext(brg)
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errInitContext
+	}
+
+	out, diags := docgen.Markdown(cctx)
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errors.New("failed to generate bridge documentation. See error diagnostics")
+	}
+
+	_, err := ui.StdWriter.Write(out)
+	return err
+}
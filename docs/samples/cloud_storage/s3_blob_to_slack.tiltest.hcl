@@ -0,0 +1,50 @@
+/*
+  Routing tests for the s3_blob_to_slack Bridge.
+
+  Run with:
+    til test docs/samples/cloud_storage/s3_blob_to_slack.brg.hcl
+*/
+
+test "s3_events_are_monitored" {
+  from = source.my_bucket
+
+  event {
+    type   = "com.amazon.s3.objectcreated"
+    source = "arn:aws:s3:::my-bucket"
+
+    data = {
+      eventName = "ObjectCreated:Put"
+      s3 = {
+        bucket = { name = "my-bucket" }
+        object = { key = "report.pdf" }
+      }
+    }
+  }
+
+  expect {
+    target = target.sockeye
+
+    attributes = {
+      source = "arn:aws:s3:::my-bucket"
+    }
+
+    data = {
+      eventName = "ObjectCreated:Put"
+    }
+  }
+}
+
+test "unknown_sources_are_only_monitored" {
+  from = source.my_files
+
+  event {
+    type   = "Microsoft.Storage.BlobCreated"
+    source = "/subscriptions/1234/some/other/account"
+  }
+
+  expect {
+    target = target.sockeye
+  }
+
+  not_reached = [target.chat_notifications]
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config/addr"
+	"til/core"
+)
+
+// Name of the Kustomize configuration file which references all the files
+// written to an output directory.
+const kustomizationFileName = "kustomization.yaml"
+
+// Annotation which marks a Kustomize configuration file as written by
+// WriteManifestsDir, and the value of that annotation.
+const (
+	kustomizationGeneratedByAnnotation = "bridges.triggermesh.io/generated-by"
+	kustomizationGeneratedByValue      = "til"
+)
+
+// WriteManifestsDir writes each of the given manifests to its own YAML file
+// inside dir, and references all written files from a kustomization.yaml file
+// located at the root of dir.
+//
+// Files are grouped by Bridge component, in sub-directories named after the
+// category and identifier of each component. For example, the AWSSQSSource
+// generated for the component 'source aws_sqs "sqs_orders"' is written to
+// "sources/sqs-orders/awssqssource.yaml".
+//
+// Files which were written by a previous invocation but don't correspond to
+// any of the given manifests are removed. Those files are determined from the
+// resources listed in the existing kustomization.yaml file, so that files
+// which were not generated by this function are never removed. The written
+// kustomization.yaml file is annotated to record that it was generated, and an
+// existing kustomization.yaml file which lacks that annotation, such as one
+// written by hand, causes an error instead of being adopted.
+func (s *Serializer) WriteManifestsDir(dir string, cmpsManifests []*core.ComponentManifests) error {
+	prevResources, err := readKustomizationResources(dir)
+	if err != nil {
+		return err
+	}
+
+	files, err := s.manifestFiles(cmpsManifests)
+	if err != nil {
+		return err
+	}
+
+	resources := make([]string, 0, len(files))
+	for p := range files {
+		resources = append(resources, p)
+	}
+	sort.Strings(resources)
+
+	for _, p := range resources {
+		if err := writeFile(dir, p, files[p]); err != nil {
+			return err
+		}
+	}
+
+	if err := writeKustomization(dir, resources); err != nil {
+		return err
+	}
+
+	for _, p := range prevResources {
+		if _, isCurrent := files[p]; isCurrent {
+			continue
+		}
+		if err := removeFile(dir, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// manifestFiles serializes the given manifests to YAML, and returns the
+// results indexed by the slash-separated path of their file, relative to the
+// output directory.
+func (s *Serializer) manifestFiles(cmpsManifests []*core.ComponentManifests) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	for _, cm := range cmpsManifests {
+		cmpDir := componentDir(cm.Component)
+
+		kindCount := make(map[string]int, len(cm.Manifests))
+		for _, m := range cm.Manifests {
+			kindCount[m.(*unstructured.Unstructured).GetKind()]++
+		}
+
+		for _, m := range cm.Manifests {
+			m = injectBridgeLabels(m, s.BridgeIdentifier)
+			u := m.(*unstructured.Unstructured)
+
+			// The file name is the lowercased kind of the object,
+			// suffixed with the object's name only when that kind
+			// is generated more than once for the same component.
+			fileName := strings.ToLower(u.GetKind())
+			if kindCount[u.GetKind()] > 1 {
+				fileName += "-" + u.GetName()
+			}
+
+			p := path.Join(cmpDir, fileName+".yaml")
+			if _, exists := files[p]; exists {
+				return nil, fmt.Errorf("more than one generated manifest would be written to %q", p)
+			}
+
+			b, err := yaml.Marshal(m)
+			if err != nil {
+				return nil, fmt.Errorf("marshaling manifest to YAML: %w", err)
+			}
+
+			files[p] = b
+		}
+	}
+
+	return files, nil
+}
+
+// componentDir returns the slash-separated path of the directory which
+// contains the manifests of the given Bridge component, relative to the output
+// directory.
+func componentDir(cmp addr.MessagingComponent) string {
+	// all category names have a regular plural form
+	return path.Join(cmp.Category.String()+"s", sanitizeBridgeIdentifier(cmp.Identifier))
+}
+
+// kustomization is a minimal representation of a Kustomize configuration.
+type kustomization struct {
+	APIVersion string                 `json:"apiVersion"`
+	Kind       string                 `json:"kind"`
+	Metadata   *kustomizationMetadata `json:"metadata,omitempty"`
+	Resources  []string               `json:"resources"`
+}
+
+// kustomizationMetadata is the metadata of a Kustomize configuration.
+type kustomizationMetadata struct {
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// writeKustomization writes a kustomization.yaml file listing the given
+// resources at the root of dir.
+func writeKustomization(dir string, resources []string) error {
+	k := &kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Metadata: &kustomizationMetadata{
+			Annotations: map[string]string{
+				kustomizationGeneratedByAnnotation: kustomizationGeneratedByValue,
+			},
+		},
+		Resources: resources,
+	}
+
+	b, err := yaml.Marshal(k)
+	if err != nil {
+		return fmt.Errorf("marshaling kustomization to YAML: %w", err)
+	}
+
+	return writeFile(dir, kustomizationFileName, b)
+}
+
+// readKustomizationResources returns the resources listed in the
+// kustomization.yaml file located at the root of dir, if this file exists.
+// It returns an error if that file wasn't written by WriteManifestsDir.
+func readKustomizationResources(dir string) ([]string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, kustomizationFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading existing kustomization: %w", err)
+	}
+
+	k := &kustomization{}
+	if err := yaml.Unmarshal(b, k); err != nil {
+		return nil, fmt.Errorf("parsing existing kustomization: %w", err)
+	}
+
+	if k.Metadata == nil || k.Metadata.Annotations[kustomizationGeneratedByAnnotation] != kustomizationGeneratedByValue {
+		return nil, fmt.Errorf("the existing %s file in %q wasn't generated from a Bridge description, "+
+			"refusing to overwrite it", kustomizationFileName, dir)
+	}
+
+	return k.Resources, nil
+}
+
+// writeFile writes data to the file located at the slash-separated path p
+// inside dir, creating parent directories as needed.
+func writeFile(dir, p string, data []byte) error {
+	fp := filepath.Join(dir, filepath.FromSlash(p))
+
+	if err := os.MkdirAll(filepath.Dir(fp), 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
+	if err := ioutil.WriteFile(fp, data, 0644); err != nil {
+		return fmt.Errorf("writing generated manifest: %w", err)
+	}
+
+	return nil
+}
+
+// removeFile removes the file located at the slash-separated path p inside
+// dir, as well as the parent directories which become empty as a result, up to
+// dir (excluded).
+//
+// Paths which point outside of dir are ignored, since such files can not have
+// been written by WriteManifestsDir.
+func removeFile(dir, p string) error {
+	p = path.Clean(p)
+	if path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+		return nil
+	}
+
+	fp := filepath.Join(dir, filepath.FromSlash(p))
+
+	if err := os.Remove(fp); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing stale manifest: %w", err)
+	}
+
+	for d := path.Dir(p); d != "."; d = path.Dir(d) {
+		// an error is expected here if the directory isn't empty
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(d))); err != nil {
+			break
+		}
+	}
+
+	return nil
+}
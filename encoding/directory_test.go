@@ -0,0 +1,228 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"til/config"
+	"til/config/addr"
+	"til/core"
+
+	. "til/encoding"
+)
+
+func TestWriteManifestsDir(t *testing.T) {
+	const testBrgID = "Test_Bridge"
+
+	s := NewSerializer(testBrgID)
+
+	dir := t.TempDir()
+
+	// file which wasn't generated by the Serializer, and should therefore
+	// never be pruned
+	writeTestFile(t, filepath.Join(dir, "sources", "README.md"))
+
+	cmpsManifests := []*core.ComponentManifests{
+		{
+			Component: addr.MessagingComponent{
+				Category:   config.CategorySources,
+				Identifier: "my_source",
+			},
+			Manifests: []interface{}{
+				newUnstructured("fake/v0", "FakeSource", "my-source"),
+			},
+		}, {
+			Component: addr.MessagingComponent{
+				Category:   config.CategoryRouters,
+				Identifier: "my_router",
+			},
+			Manifests: []interface{}{
+				newUnstructured("fake/v0", "Router", "my-router"),
+				newUnstructured("fake/v0", "Route", "my-router-r0"),
+				newUnstructured("fake/v0", "Route", "my-router-r1"),
+			},
+		},
+	}
+
+	if err := s.WriteManifestsDir(dir, cmpsManifests); err != nil {
+		t.Fatal("Returned an error:", err)
+	}
+
+	expectFiles := []string{
+		"kustomization.yaml",
+		"routers/my-router/route-my-router-r0.yaml",
+		"routers/my-router/route-my-router-r1.yaml",
+		"routers/my-router/router.yaml",
+		"sources/README.md",
+		"sources/my-source/fakesource.yaml",
+	}
+	if diff := cmp.Diff(expectFiles, listFiles(t, dir)); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+
+	const expectKustomization = "" +
+		"apiVersion: kustomize.config.k8s.io/v1beta1\n" +
+		"kind: Kustomization\n" +
+		"metadata:\n" +
+		"  annotations:\n" +
+		"    bridges.triggermesh.io/generated-by: til\n" +
+		"resources:\n" +
+		"- routers/my-router/route-my-router-r0.yaml\n" +
+		"- routers/my-router/route-my-router-r1.yaml\n" +
+		"- routers/my-router/router.yaml\n" +
+		"- sources/my-source/fakesource.yaml\n"
+	if diff := cmp.Diff(expectKustomization, readFile(t, filepath.Join(dir, "kustomization.yaml"))); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+
+	const expectManifest = "" +
+		"apiVersion: fake/v0\n" +
+		"kind: FakeSource\n" +
+		"metadata:\n" +
+		"  labels:\n" +
+		"    bridges.triggermesh.io/id: Test_Bridge\n" +
+		"  name: my-source\n"
+	if diff := cmp.Diff(expectManifest, readFile(t, filepath.Join(dir, "sources", "my-source", "fakesource.yaml"))); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+
+	// Regenerate after removing the router and one of the source's
+	// manifests changed kind. Stale files and directories are expected to
+	// be pruned.
+
+	cmpsManifests = cmpsManifests[:1]
+	cmpsManifests[0].Manifests = []interface{}{
+		newUnstructured("fake/v0", "OtherFakeSource", "my-source"),
+	}
+
+	if err := s.WriteManifestsDir(dir, cmpsManifests); err != nil {
+		t.Fatal("Returned an error:", err)
+	}
+
+	expectFiles = []string{
+		"kustomization.yaml",
+		"sources/README.md",
+		"sources/my-source/otherfakesource.yaml",
+	}
+	if diff := cmp.Diff(expectFiles, listFiles(t, dir)); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+func TestWriteManifestsDirForeignKustomization(t *testing.T) {
+	s := NewSerializer("test")
+
+	dir := t.TempDir()
+
+	// kustomization written by hand, which references a file that isn't
+	// part of the generated manifests
+	const foreignKustomization = "" +
+		"apiVersion: kustomize.config.k8s.io/v1beta1\n" +
+		"kind: Kustomization\n" +
+		"resources:\n" +
+		"- my-deployment.yaml\n"
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(foreignKustomization), 0644); err != nil {
+		t.Fatal("Error writing file:", err)
+	}
+	writeTestFile(t, filepath.Join(dir, "my-deployment.yaml"))
+
+	cmpsManifests := []*core.ComponentManifests{{
+		Component: addr.MessagingComponent{
+			Category:   config.CategorySources,
+			Identifier: "my_source",
+		},
+		Manifests: []interface{}{
+			newUnstructured("fake/v0", "FakeSource", "my-source"),
+		},
+	}}
+
+	if err := s.WriteManifestsDir(dir, cmpsManifests); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	expectFiles := []string{
+		"kustomization.yaml",
+		"my-deployment.yaml",
+	}
+	if diff := cmp.Diff(expectFiles, listFiles(t, dir)); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+	if diff := cmp.Diff(foreignKustomization, readFile(t, filepath.Join(dir, "kustomization.yaml"))); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+// listFiles returns the slash-separated paths of all regular files inside dir.
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal("Error listing files:", err)
+	}
+
+	sort.Strings(files)
+
+	return files
+}
+
+func writeTestFile(t *testing.T, p string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal("Error creating directory:", err)
+	}
+	if err := ioutil.WriteFile(p, []byte("test"), 0644); err != nil {
+		t.Fatal("Error writing file:", err)
+	}
+}
+
+func readFile(t *testing.T, p string) string {
+	t.Helper()
+
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		t.Fatal("Error reading file:", err)
+	}
+
+	return string(b)
+}
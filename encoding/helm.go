@@ -0,0 +1,297 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config/addr"
+	"til/core"
+)
+
+// Layout of a Helm chart.
+const (
+	helmChartFileName  = "Chart.yaml"
+	helmValuesFileName = "values.yaml"
+	helmTemplatesDir   = "templates"
+)
+
+// Version assigned to generated Helm charts.
+const helmChartVersion = "0.1.0"
+
+// WriteHelmChart packages the given manifests as a Helm chart inside dir.
+//
+// The chart is named after the Bridge identifier. Its templates are split per
+// Bridge component, and the following values are exposed in values.yaml so
+// that they can be overridden at install time:
+//   - secrets: names of the Kubernetes Secrets referenced by components,
+//     indexed by their original name
+//   - images: container images run by components, indexed by component
+//     address (e.g. "target.my_container")
+//
+// Input variables of the Bridge are not exposed as values, since they were
+// already resolved when the given manifests were generated.
+//
+// Templates which were written by a previous invocation but don't correspond
+// to any of the given components are removed.
+func (s *Serializer) WriteHelmChart(dir string, cmpsManifests []*core.ComponentManifests) error {
+	vals := &helmValues{
+		Secrets: make(map[string]string),
+		Images:  make(map[string]string),
+	}
+
+	templates := make(map[string][]byte, len(cmpsManifests))
+
+	for _, cm := range cmpsManifests {
+		if len(cm.Manifests) == 0 {
+			continue
+		}
+
+		tpl, err := s.helmTemplate(cm, vals)
+		if err != nil {
+			return err
+		}
+
+		templates[helmTemplateFileName(cm.Component)] = tpl
+	}
+
+	chart, err := yaml.Marshal(newHelmChart(s.BridgeIdentifier))
+	if err != nil {
+		return fmt.Errorf("marshaling Helm chart metadata to YAML: %w", err)
+	}
+	if err := writeFile(dir, helmChartFileName, chart); err != nil {
+		return err
+	}
+
+	values, err := yaml.Marshal(vals)
+	if err != nil {
+		return fmt.Errorf("marshaling Helm values to YAML: %w", err)
+	}
+	if err := writeFile(dir, helmValuesFileName, values); err != nil {
+		return err
+	}
+
+	for p, tpl := range templates {
+		if err := writeFile(dir, p, tpl); err != nil {
+			return err
+		}
+	}
+
+	return pruneHelmTemplates(dir, templates)
+}
+
+// helmChart is a minimal representation of a Helm chart's metadata
+// (Chart.yaml).
+type helmChart struct {
+	APIVersion  string `json:"apiVersion"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Version     string `json:"version"`
+}
+
+// newHelmChart returns the metadata of a Helm chart for the Bridge with the
+// given identifier.
+func newHelmChart(brgID string) *helmChart {
+	return &helmChart{
+		APIVersion:  "v2",
+		Name:        sanitizeBridgeIdentifier(brgID),
+		Description: fmt.Sprintf("TriggerMesh Bridge %q", brgID),
+		Type:        "application",
+		Version:     helmChartVersion,
+	}
+}
+
+// helmValues is the content of a Helm chart's values.yaml file.
+type helmValues struct {
+	Secrets map[string]string `json:"secrets"`
+	Images  map[string]string `json:"images"`
+}
+
+// helmTemplateFileName returns the slash-separated path of the template which
+// contains the manifests of the given Bridge component, relative to the chart
+// directory.
+func helmTemplateFileName(cmp addr.MessagingComponent) string {
+	return path.Join(helmTemplatesDir,
+		cmp.Category.String()+"-"+sanitizeBridgeIdentifier(cmp.Identifier)+".yaml")
+}
+
+// helmTemplate returns a Helm template containing the manifests of the given
+// Bridge component, where overridable values are replaced with references to
+// entries of vals.
+func (s *Serializer) helmTemplate(cm *core.ComponentManifests, vals *helmValues) ([]byte, error) {
+	p := &helmParameterizer{
+		cmpKey: cm.Component.Category.String() + "." + cm.Component.Identifier,
+		vals:   vals,
+	}
+
+	var tpl strings.Builder
+
+	for i, m := range cm.Manifests {
+		if i > 0 {
+			tpl.WriteString("---\n")
+		}
+
+		// work on a copy to avoid leaking placeholders into the
+		// caller's objects
+		u := m.(*unstructured.Unstructured).DeepCopy()
+		injectBridgeLabels(u, s.BridgeIdentifier)
+		p.parameterize(u.Object)
+
+		b, err := yaml.Marshal(u)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling manifest to YAML: %w", err)
+		}
+
+		tpl.WriteString(escapeHelmTemplate(string(b)))
+	}
+
+	return []byte(p.render(tpl.String())), nil
+}
+
+// helmParameterizer replaces overridable values inside Kubernetes objects with
+// placeholders, and records those values.
+type helmParameterizer struct {
+	// address of the component that owns the parameterized objects
+	cmpKey string
+	vals   *helmValues
+
+	// template expressions indexed by placeholder
+	exprs map[string]string
+}
+
+// parameterize walks the given object recursively and replaces overridable
+// values with placeholders.
+func (p *helmParameterizer) parameterize(obj map[string]interface{}) {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	// ensures the deterministic naming of values
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		switch v := obj[k].(type) {
+		case map[string]interface{}:
+			if k == "secretKeyRef" || k == "valueFromSecret" {
+				if name, ok := v["name"].(string); ok {
+					p.vals.Secrets[name] = name
+					v["name"] = p.placeholder(fmt.Sprintf("index .Values.secrets %q", name))
+					continue
+				}
+			}
+			p.parameterize(v)
+
+		case []interface{}:
+			for _, e := range v {
+				if m, ok := e.(map[string]interface{}); ok {
+					p.parameterize(m)
+				}
+			}
+
+		case string:
+			if k == "image" {
+				key := p.imageKey(v)
+				p.vals.Images[key] = v
+				obj[k] = p.placeholder(fmt.Sprintf("index .Values.images %q", key))
+			}
+		}
+	}
+}
+
+// imageKey returns the key of the given image inside the "images" values. The
+// key is the address of the component, suffixed with an index in case that
+// component runs multiple different images.
+func (p *helmParameterizer) imageKey(img string) string {
+	key := p.cmpKey
+	for i := 1; ; i++ {
+		if existing, ok := p.vals.Images[key]; !ok || existing == img {
+			return key
+		}
+		key = fmt.Sprintf("%s.%d", p.cmpKey, i)
+	}
+}
+
+// placeholder registers the given template expression and returns the
+// placeholder that stands for it until the template is rendered.
+func (p *helmParameterizer) placeholder(expr string) string {
+	if p.exprs == nil {
+		p.exprs = make(map[string]string)
+	}
+
+	ph := fmt.Sprintf("__til_helm_value_%d__", len(p.exprs))
+	p.exprs[ph] = "{{ " + expr + " | quote }}"
+
+	return ph
+}
+
+// render replaces all placeholders inside the given template with their
+// template expression.
+func (p *helmParameterizer) render(tpl string) string {
+	oldnew := make([]string, 0, len(p.exprs)*2)
+	for ph, expr := range p.exprs {
+		oldnew = append(oldnew, ph, expr)
+	}
+
+	return strings.NewReplacer(oldnew...).Replace(tpl)
+}
+
+// escapeHelmTemplate escapes the Go template delimiters contained in the
+// given text, so that they are rendered verbatim by Helm.
+func escapeHelmTemplate(s string) string {
+	return strings.ReplaceAll(s, "{{", `{{ "{{" }}`)
+}
+
+// pruneHelmTemplates removes the YAML files from the templates directory of
+// the chart located in dir which are not part of the given templates.
+func pruneHelmTemplates(dir string, templates map[string][]byte) error {
+	tplDir := filepath.Join(dir, helmTemplatesDir)
+
+	entries, err := ioutil.ReadDir(tplDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading templates directory: %w", err)
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".yaml" {
+			continue
+		}
+		if _, isCurrent := templates[path.Join(helmTemplatesDir, e.Name())]; isCurrent {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(tplDir, e.Name())); err != nil {
+			return fmt.Errorf("removing stale template: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encoding_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config"
+	"til/config/addr"
+	"til/core"
+
+	. "til/encoding"
+)
+
+func TestWriteHelmChart(t *testing.T) {
+	const testBrgID = "Test_Bridge"
+
+	s := NewSerializer(testBrgID)
+
+	dir := t.TempDir()
+
+	// template from a previous generation, which is expected to be pruned
+	writeTestFile(t, filepath.Join(dir, "templates", "source-old.yaml"))
+
+	src := newUnstructured("fake/v0", "FakeSource", "my-source")
+	_ = unstructured.SetNestedMap(src.Object, map[string]interface{}{
+		"name": "my-secret",
+		"key":  "token",
+	}, "spec", "token", "secretKeyRef")
+	_ = unstructured.SetNestedField(src.Object, "Hello {{ name }}", "spec", "template")
+
+	trg := newUnstructured("fake/v0", "FakeTarget", "my-target")
+	_ = unstructured.SetNestedSlice(trg.Object, []interface{}{
+		map[string]interface{}{
+			"image": "registry.example.com/my-image:v1",
+		},
+	}, "spec", "containers")
+
+	cmpsManifests := []*core.ComponentManifests{
+		{
+			Component: addr.MessagingComponent{
+				Category:   config.CategorySources,
+				Identifier: "my_source",
+			},
+			Manifests: []interface{}{src},
+		}, {
+			Component: addr.MessagingComponent{
+				Category:   config.CategoryTargets,
+				Identifier: "my_target",
+			},
+			Manifests: []interface{}{trg},
+		},
+	}
+
+	if err := s.WriteHelmChart(dir, cmpsManifests); err != nil {
+		t.Fatal("Returned an error:", err)
+	}
+
+	expectFiles := []string{
+		"Chart.yaml",
+		"templates/source-my-source.yaml",
+		"templates/target-my-target.yaml",
+		"values.yaml",
+	}
+	if diff := cmp.Diff(expectFiles, listFiles(t, dir)); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+
+	testCases := map[string]struct {
+		file   string
+		expect string
+	}{
+		"chart metadata": {
+			file: "Chart.yaml",
+			expect: "" +
+				"apiVersion: v2\n" +
+				"description: TriggerMesh Bridge \"Test_Bridge\"\n" +
+				"name: test-bridge\n" +
+				"type: application\n" +
+				"version: 0.1.0\n",
+		},
+		"values": {
+			file: "values.yaml",
+			expect: "" +
+				"images:\n" +
+				"  target.my_target: registry.example.com/my-image:v1\n" +
+				"secrets:\n" +
+				"  my-secret: my-secret\n",
+		},
+		"template with secret reference": {
+			file: "templates/source-my-source.yaml",
+			expect: "" +
+				"apiVersion: fake/v0\n" +
+				"kind: FakeSource\n" +
+				"metadata:\n" +
+				"  labels:\n" +
+				"    bridges.triggermesh.io/id: Test_Bridge\n" +
+				"  name: my-source\n" +
+				"spec:\n" +
+				"  template: Hello {{ \"{{\" }} name }}\n" +
+				"  token:\n" +
+				"    secretKeyRef:\n" +
+				"      key: token\n" +
+				"      name: {{ index .Values.secrets \"my-secret\" | quote }}\n",
+		},
+		"template with image": {
+			file: "templates/target-my-target.yaml",
+			expect: "" +
+				"apiVersion: fake/v0\n" +
+				"kind: FakeTarget\n" +
+				"metadata:\n" +
+				"  labels:\n" +
+				"    bridges.triggermesh.io/id: Test_Bridge\n" +
+				"  name: my-target\n" +
+				"spec:\n" +
+				"  containers:\n" +
+				"  - image: {{ index .Values.images \"target.my_target\" | quote }}\n",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := readFile(t, filepath.Join(dir, filepath.FromSlash(tc.file)))
+			if diff := cmp.Diff(tc.expect, got); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+		})
+	}
+}
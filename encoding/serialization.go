@@ -25,6 +25,8 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/translation"
 )
 
 // Serializer can serialize Kubernetes API objects into various formats.
@@ -152,15 +154,13 @@ func newBridge(brgID string, manifests []interface{}) *unstructured.Unstructured
 // injectBridgeLabels sets a standard set of labels in the metadata of the
 // given manifest.
 func injectBridgeLabels(manifest interface{}, brgID string) interface{} {
-	const lblBridgeIdentifier = "bridges.triggermesh.io/id"
-
 	m := manifest.(*unstructured.Unstructured)
 
 	lbls := m.GetLabels()
 	if lbls == nil {
 		lbls = make(map[string]string, 1)
 	}
-	lbls[lblBridgeIdentifier] = brgID
+	lbls[translation.LabelBridgeIdentifier] = brgID
 
 	m.SetLabels(lbls)
 
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"til/catalog"
+	"til/cli"
+	"til/config"
+)
+
+type ExplainCommand struct{}
+
+// Run implements cli.Command.
+func (c *ExplainCommand) Run(ctx context.Context, args []string) error {
+	flagSet := cli.FlagSetFromContext(ctx)
+	setUsageFn(flagSet, usageExplain)
+
+	pos, flags := splitArgs(countPositional(2, args), args)
+	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
+
+	if flagSet.NArg() > 0 {
+		return fmt.Errorf("unexpected number of positional arguments.\n\n%s", usageExplain(flagSet.Name()))
+	}
+
+	stdout := cli.UIFromContext(ctx).StdWriter
+
+	if len(pos) == 0 {
+		for i, cat := range catalog.Categories() {
+			if i > 0 {
+				fmt.Fprintln(stdout)
+			}
+			writeComponentTypes(stdout, cat)
+		}
+		return nil
+	}
+
+	cat := config.AsComponentCategory(pos[0])
+	if cat == config.CategoryUnknown {
+		return fmt.Errorf("unknown component category %q. Valid categories are: %s",
+			pos[0], strings.Join(categoryNames(), ", "))
+	}
+
+	if len(pos) == 1 {
+		writeComponentTypes(stdout, cat)
+		return nil
+	}
+
+	cmpType, ok := catalog.Lookup(cat, pos[1])
+	if !ok {
+		return fmt.Errorf("unknown %s type %q. Run %q to list all supported types",
+			cat, pos[1], cmdExplain+" "+cat.String())
+	}
+
+	catalog.Describe(stdout, cmpType)
+
+	return nil
+}
+
+// countPositional returns the number of positional arguments, up to max, that
+// appear at the beginning of the given arguments list.
+func countPositional(max int, args []string) int {
+	n := 0
+	for _, a := range args {
+		if n == max || (a != "" && a[0] == '-') {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// categoryNames returns the names of all component categories.
+func categoryNames() []string {
+	cats := catalog.Categories()
+
+	names := make([]string, len(cats))
+	for i, cat := range cats {
+		names[i] = cat.String()
+	}
+
+	return names
+}
+
+// writeComponentTypes writes the list of component types supported for the
+// given category to w.
+func writeComponentTypes(w io.Writer, cat config.ComponentCategory) {
+	fmt.Fprintf(w, "%s:\n", strings.ToUpper(cat.String()))
+	for _, t := range catalog.Types(cat) {
+		fmt.Fprintf(w, "    %s\n", t)
+	}
+}
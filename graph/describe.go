@@ -0,0 +1,34 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import "github.com/hashicorp/hcl/v2"
+
+// DescribableVertex can be implemented by a Vertex in order to provide
+// marshalers with a structured description of the Bridge component it
+// represents.
+type DescribableVertex interface {
+	Description() VertexDescription
+}
+
+// VertexDescription describes the Bridge component represented by a Vertex.
+type VertexDescription struct {
+	Category    string
+	Type        string
+	Identifier  string
+	SourceRange hcl.Range
+}
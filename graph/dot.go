@@ -16,6 +16,12 @@ limitations under the License.
 
 package graph
 
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
 // DOTableVertex can be implemented by a Vertex in order to give DOT marshalers
 // a hint about its expected representation in the DOT language (Graphviz).
 type DOTableVertex interface {
@@ -33,3 +39,75 @@ type DOTNodeStyle struct {
 	AccentColor     string
 	HeaderTextColor string
 }
+
+// NodeFor returns the DOTNode which represents the given Vertex.
+//
+// Vertices which do not implement DOTableVertex are represented by the name of
+// their Go type and their value.
+func NodeFor(v Vertex) DOTNode {
+	if dv, ok := v.(DOTableVertex); ok {
+		return dv.Node()
+	}
+
+	reflectType := reflect.TypeOf(v)
+	switch reflectType.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Map, reflect.Ptr, reflect.Slice:
+		reflectType = reflectType.Elem()
+	}
+
+	return DOTNode{
+		Header: reflectType.Name(),
+		Body:   fmt.Sprint(v),
+	}
+}
+
+// ID returns a string which uniquely identifies the node within a graph.
+func (n DOTNode) ID() string {
+	return n.Header + "." + n.Body
+}
+
+// Default colors of nodes which do not have a DOTNodeStyle.
+const (
+	defaultAccentColor    = "#dcdcdc"
+	defaultHeaderTxtColor = "#000000"
+)
+
+// Colors returns the accent and header text colors of the node, or default
+// colors if the node doesn't have a style.
+func (n DOTNode) Colors() (accent, headerTxt string) {
+	accent, headerTxt = defaultAccentColor, defaultHeaderTxtColor
+
+	if n.Style == nil {
+		return
+	}
+
+	if col := n.Style.AccentColor; col != "" {
+		accent = col
+	}
+	if col := n.Style.HeaderTextColor; col != "" {
+		headerTxt = col
+	}
+
+	return
+}
+
+// brewerSet26 is the "set26" Brewer color scheme supported by Graphviz.
+var brewerSet26 = []string{"#66c2a5", "#fc8d62", "#8da0cb", "#e78ac3", "#a6d854", "#ffd92f"}
+
+// WebColor returns the representation of a DOT color in formats which only
+// support web colors (hex values and color names). Colors which reference the
+// "set26" color scheme (e.g. "/set26/1") are converted to their hex value.
+func WebColor(c string) string {
+	const set26Prefix = "/set26/"
+
+	if !strings.HasPrefix(c, set26Prefix) {
+		return c
+	}
+
+	var i int
+	if _, err := fmt.Sscan(strings.TrimPrefix(c, set26Prefix), &i); err != nil || i < 1 || i > len(brewerSet26) {
+		return c
+	}
+
+	return brewerSet26[i-1]
+}
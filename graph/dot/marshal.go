@@ -20,20 +20,55 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
-	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 
 	"til/graph"
 )
 
 const (
-	defaultAccentColor    = "#dcdcdc"
-	defaultHeaderTxtColor = "#000000"
+	// Default direction of the graph layout.
+	defaultRankDir = "LR"
+	// Color of cluster borders.
+	defaultClusterColor = "#dcdcdc"
 )
 
+// MarshalOption customizes the DOT representation of a graph.
+type MarshalOption func(*marshalOptions)
+
+// marshalOptions contains the settings applied by MarshalOptions.
+type marshalOptions struct {
+	rankDir   string
+	clusterBy func(graph.Vertex) string
+}
+
+// RankDir sets the direction of the graph layout. Valid values are "LR",
+// "RL", "TB" and "BT".
+func RankDir(dir string) MarshalOption {
+	return func(o *marshalOptions) {
+		o.rankDir = dir
+	}
+}
+
+// ClusterBy groups nodes in clusters, based on the key returned by the given
+// function for each vertex. Vertices for which the returned key is empty are
+// not part of any cluster.
+func ClusterBy(key func(graph.Vertex) string) MarshalOption {
+	return func(o *marshalOptions) {
+		o.clusterBy = key
+	}
+}
+
 // Marshal serializes a graph to DOT.
-func Marshal(g *graph.DirectedGraph) ([]byte, error) {
+func Marshal(g *graph.DirectedGraph, opts ...MarshalOption) ([]byte, error) {
+	o := &marshalOptions{
+		rankDir: defaultRankDir,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	var b bytes.Buffer
 
 	// Static graph configuration attributes
@@ -41,7 +76,7 @@ func Marshal(g *graph.DirectedGraph) ([]byte, error) {
 	b.WriteString("strict digraph bridge {\n")
 	b.WriteByte('\n')
 	b.WriteString("graph [\n")
-	b.WriteString("    rankdir=LR\n")
+	b.WriteString("    rankdir=" + o.rankDir + "\n")
 	b.WriteString("]\n")
 	b.WriteByte('\n')
 	b.WriteString("node [\n")
@@ -49,6 +84,11 @@ func Marshal(g *graph.DirectedGraph) ([]byte, error) {
 	b.WriteString("    shape=plain\n")
 	b.WriteString("]\n")
 	b.WriteByte('\n')
+	b.WriteString("edge [\n")
+	b.WriteString("    fontname=\"Helvetica\"\n")
+	b.WriteString("    fontsize=10\n")
+	b.WriteString("]\n")
+	b.WriteByte('\n')
 
 	// Vertices
 
@@ -57,16 +97,31 @@ func Marshal(g *graph.DirectedGraph) ([]byte, error) {
 	// The keys used in the map are also the ones used in the graph.DirectedGraph
 	vertIndex := make(map[interface{}]*node)
 
-	sortedNodes := make(nodeList, 0, len(g.Vertices()))
+	// Nodes indexed by cluster key. The empty key represents nodes which
+	// do not belong to any cluster.
+	sortedNodes := make(map[string]nodeList)
 	for k, v := range g.Vertices() {
 		n := graphVertexToNode(v)
 		vertIndex[k] = n
 
-		sortedNodes = append(sortedNodes, n)
-		sort.Sort(sortedNodes)
+		var cluster string
+		if o.clusterBy != nil {
+			cluster = o.clusterBy(v)
+		}
+
+		sortedNodes[cluster] = append(sortedNodes[cluster], n)
+	}
+
+	clusters := make([]string, 0, len(sortedNodes))
+	for c, ns := range sortedNodes {
+		sort.Sort(ns)
+		if c != "" {
+			clusters = append(clusters, c)
+		}
 	}
+	sort.Strings(clusters)
 
-	for _, n := range sortedNodes {
+	for _, n := range sortedNodes[""] {
 		dotN, err := n.marshalDOT()
 		if err != nil {
 			return nil, fmt.Errorf("marshaling node to DOT: %w", err)
@@ -74,6 +129,14 @@ func Marshal(g *graph.DirectedGraph) ([]byte, error) {
 		b.Write(dotN)
 	}
 
+	for _, c := range clusters {
+		dotC, err := marshalCluster(c, sortedNodes[c])
+		if err != nil {
+			return nil, fmt.Errorf("marshaling cluster to DOT: %w", err)
+		}
+		b.Write(dotC)
+	}
+
 	b.WriteByte('\n')
 
 	// Edges
@@ -96,12 +159,33 @@ func Marshal(g *graph.DirectedGraph) ([]byte, error) {
 	sort.Sort(sortedDownEdges)
 
 	for _, e := range sortedDownEdges {
-		b.WriteString(e.tail.id() + " -> {")
+		// Edges without attributes are grouped into a single statement,
+		// other edges are written on their own line with their attributes.
+		var plainHeads nodeList
+		var typedEdges []string
+
 		for _, h := range e.heads {
-			b.WriteByte(' ')
-			b.WriteString(h.id())
+			attrs := edgeAttributes(g.EdgesBetween(e.tail.vertex, h.vertex))
+			if len(attrs) == 0 {
+				plainHeads = append(plainHeads, h)
+				continue
+			}
+
+			typedEdges = append(typedEdges, e.tail.id()+" -> "+h.id()+" ["+strings.Join(attrs, " ")+"]\n")
+		}
+
+		if len(plainHeads) > 0 {
+			b.WriteString(e.tail.id() + " -> {")
+			for _, h := range plainHeads {
+				b.WriteByte(' ')
+				b.WriteString(h.id())
+			}
+			b.WriteString(" }\n")
+		}
+
+		for _, te := range typedEdges {
+			b.WriteString(te)
 		}
-		b.WriteString(" }\n")
 	}
 
 	b.WriteByte('\n')
@@ -111,9 +195,42 @@ func Marshal(g *graph.DirectedGraph) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// marshalCluster serializes the given nodes to a "subgraph" DOT statement
+// representing a cluster.
+func marshalCluster(name string, ns nodeList) ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString("subgraph " + strconv.Quote("cluster_"+name) + " {\n")
+	b.WriteString("    label=" + strconv.Quote(name) + "\n")
+	b.WriteString("    fontname=\"Helvetica\"\n")
+	b.WriteString("    style=rounded\n")
+	b.WriteString("    color=\"" + defaultClusterColor + "\"\n")
+	b.WriteByte('\n')
+
+	for _, n := range ns {
+		dotN, err := n.marshalDOT()
+		if err != nil {
+			return nil, fmt.Errorf("marshaling node to DOT: %w", err)
+		}
+
+		for _, l := range bytes.SplitAfter(dotN, []byte{'\n'}) {
+			if len(l) == 0 {
+				continue
+			}
+			b.WriteString("    ")
+			b.Write(l)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	return b.Bytes(), nil
+}
+
 // node represents a "node" statement in a DOT graph.
 type node struct {
 	graph.DOTNode
+	vertex graph.Vertex
 }
 
 // marshalDOT serializes the node to a "node" DOT statement.
@@ -136,7 +253,7 @@ func (n *node) marshalDOT() ([]byte, error) {
 
 // id returns the DOT "node_id" of the node.
 func (n *node) id() string {
-	return strconv.Quote(n.Header + "." + n.Body)
+	return strconv.Quote(n.ID())
 }
 
 // htmlLabel returns the DOT "label" attribute of the node formatted as a
@@ -167,37 +284,75 @@ func (n *node) htmlLabel() (string, error) {
 
 // nodeColors returns colors for the accent and header of the given node.
 func nodeColors(n *node) (accent, headerTxt string) {
-	accent, headerTxt = defaultAccentColor, defaultHeaderTxtColor
+	return n.Colors()
+}
 
-	if n.Style == nil {
-		return
+// graphVertexToNode converts a graph.Vertex to a marshalable DOT node.
+func graphVertexToNode(v graph.Vertex) *node {
+	return &node{
+		DOTNode: graph.NodeFor(v),
+		vertex:  v,
 	}
+}
 
-	if col := n.Style.AccentColor; col != "" {
-		accent = col
+// edgeAttributes returns the DOT attributes of the given edges, based on their
+// kind and label.
+//
+// Edges of different kinds connecting the same vertices are represented by a
+// single DOT edge, because a strict graph can't contain parallel edges. That
+// edge is dashed if any of the given edges is a reply or dead-letter edge, and
+// carries all their labels. When the given edges are drawn with different
+// styles, each label is prefixed with the kind of its edge so that no kind
+// goes unnoticed.
+func edgeAttributes(es []*graph.Edge) []string {
+	if len(es) == 0 {
+		return nil
 	}
-	if col := n.Style.HeaderTextColor; col != "" {
-		headerTxt = col
+
+	var attrs []string
+
+	var dashed, solid bool
+	for _, e := range es {
+		if isDashedEdge(e) {
+			dashed = true
+		} else {
+			solid = true
+		}
 	}
+	mixed := dashed && solid
+
+	var labels []string
+	for _, e := range es {
+		lbl := e.Label
+		if mixed && e.Kind != graph.EdgeKindUnspecified {
+			if lbl != "" {
+				lbl = ": " + lbl
+			}
+			lbl = e.Kind.String() + lbl
+		}
 
-	return
-}
+		if lbl != "" {
+			labels = append(labels, lbl)
+		}
+	}
 
-// graphVertexToNode converts a graph.Vertex to a marshalable DOT node.
-func graphVertexToNode(v graph.Vertex) *node {
-	if dv, ok := v.(graph.DOTableVertex); ok {
-		return &node{DOTNode: dv.Node()}
+	if dashed {
+		attrs = append(attrs, "style=dashed")
 	}
 
-	reflectType := reflect.TypeOf(v)
-	switch reflectType.Kind() {
-	case reflect.Array, reflect.Chan, reflect.Map, reflect.Ptr, reflect.Slice:
-		reflectType = reflectType.Elem()
+	if len(labels) > 0 {
+		attrs = append(attrs, "label="+strconv.Quote(strings.Join(labels, "\n")))
 	}
 
-	n := &node{}
-	n.Header = reflectType.Name()
-	n.Body = fmt.Sprint(v)
+	return attrs
+}
 
-	return n
+// isDashedEdge returns whether the given edge is drawn with a dashed line.
+func isDashedEdge(e *graph.Edge) bool {
+	switch e.Kind {
+	case graph.EdgeKindReply, graph.EdgeKindDeadLetter:
+		return true
+	default:
+		return false
+	}
 }
@@ -50,6 +50,61 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMarshalEdgeKinds(t *testing.T) {
+	g := graph.NewDirectedGraph()
+
+	v1 := "some_node"
+	v2 := 42
+	v3 := fakeDOTableVertex{header: "some_header", body: "some_body"}
+
+	g.Add(v1)
+	g.Add(v2)
+	g.Add(v3)
+
+	g.ConnectWithKind(v1, v2, graph.EdgeKindDestination, "")
+	g.ConnectWithKind(v1, v3, graph.EdgeKindRoute, "some_filter")
+	g.ConnectWithKind(v1, v3, graph.EdgeKindDeadLetter, "")
+	g.ConnectWithKind(v2, v3, graph.EdgeKindReply, "some_label")
+	g.ConnectWithKind(v2, v3, graph.EdgeKindDeadLetter, "other_label")
+
+	b, err := Marshal(g)
+	if err != nil {
+		t.Fatal("Error marshaling graph:", err)
+	}
+
+	if string(b) != referenceEdgeKindsDOTGraph {
+		t.Error("DOT graph differs from reference:\n" + string(b))
+	}
+}
+
+func TestMarshalWithOptions(t *testing.T) {
+	g := graph.NewDirectedGraph()
+
+	v1 := "some_node"
+	v2 := fakeDOTableVertex{header: "some_header", body: "some_body"}
+
+	g.Add(v1)
+	g.Add(v2)
+
+	g.Connect(v1, v2)
+
+	clusterByHeader := func(v graph.Vertex) string {
+		if v, ok := v.(fakeDOTableVertex); ok {
+			return v.header
+		}
+		return ""
+	}
+
+	b, err := Marshal(g, RankDir("TB"), ClusterBy(clusterByHeader))
+	if err != nil {
+		t.Fatal("Error marshaling graph:", err)
+	}
+
+	if string(b) != referenceClusteredDOTGraph {
+		t.Error("DOT graph differs from reference:\n" + string(b))
+	}
+}
+
 const (
 	testAccentColor    = "goldenrod"
 	testHeaderTxtColor = "floralwhite"
@@ -86,6 +141,11 @@ node [
     shape=plain
 ]
 
+edge [
+    fontname="Helvetica"
+    fontsize=10
+]
+
 "int.42" [
     label=<
         <table border="0" color="#dcdcdc" cellborder="1" cellspacing="0" cellpadding="4"><tr><td bgcolor="#dcdcdc"><font color="#000000">int</font></td></tr><tr><td><font>42</font></td></tr></table>
@@ -107,3 +167,81 @@ node [
 
 }
 `
+
+const referenceEdgeKindsDOTGraph = `strict digraph bridge {
+
+graph [
+    rankdir=LR
+]
+
+node [
+    fontname="Helvetica"
+    shape=plain
+]
+
+edge [
+    fontname="Helvetica"
+    fontsize=10
+]
+
+"int.42" [
+    label=<
+        <table border="0" color="#dcdcdc" cellborder="1" cellspacing="0" cellpadding="4"><tr><td bgcolor="#dcdcdc"><font color="#000000">int</font></td></tr><tr><td><font>42</font></td></tr></table>
+    >
+]
+"some_header.some_body" [
+    label=<
+        <table border="0" color="goldenrod" cellborder="1" cellspacing="0" cellpadding="4"><tr><td bgcolor="goldenrod"><font color="floralwhite">some_header</font></td></tr><tr><td><font>some_body</font></td></tr></table>
+    >
+]
+"string.some_node" [
+    label=<
+        <table border="0" color="#dcdcdc" cellborder="1" cellspacing="0" cellpadding="4"><tr><td bgcolor="#dcdcdc"><font color="#000000">string</font></td></tr><tr><td><font>some_node</font></td></tr></table>
+    >
+]
+
+"int.42" -> "some_header.some_body" [style=dashed label="some_label\nother_label"]
+"string.some_node" -> { "int.42" }
+"string.some_node" -> "some_header.some_body" [style=dashed label="route: some_filter\ndead_letter"]
+
+}
+`
+
+const referenceClusteredDOTGraph = `strict digraph bridge {
+
+graph [
+    rankdir=TB
+]
+
+node [
+    fontname="Helvetica"
+    shape=plain
+]
+
+edge [
+    fontname="Helvetica"
+    fontsize=10
+]
+
+"string.some_node" [
+    label=<
+        <table border="0" color="#dcdcdc" cellborder="1" cellspacing="0" cellpadding="4"><tr><td bgcolor="#dcdcdc"><font color="#000000">string</font></td></tr><tr><td><font>some_node</font></td></tr></table>
+    >
+]
+subgraph "cluster_some_header" {
+    label="some_header"
+    fontname="Helvetica"
+    style=rounded
+    color="#dcdcdc"
+
+    "some_header.some_body" [
+        label=<
+            <table border="0" color="goldenrod" cellborder="1" cellspacing="0" cellpadding="4"><tr><td bgcolor="goldenrod"><font color="floralwhite">some_header</font></td></tr><tr><td><font>some_body</font></td></tr></table>
+        >
+    ]
+}
+
+"string.some_node" -> { "some_header.some_body" }
+
+}
+`
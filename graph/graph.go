@@ -53,13 +53,58 @@ type Vertex interface{}
 type Edge struct {
 	Tail Vertex
 	Head Vertex
+
+	// Kind qualifies the relationship between the tail and the head.
+	Kind EdgeKind
+	// Optional label describing the edge, e.g. an event filter.
+	Label string
+}
+
+// EdgeKind qualifies the relationship represented by an Edge.
+type EdgeKind uint8
+
+// Kinds of edges.
+const (
+	// The relationship between the tail and head is not qualified.
+	EdgeKindUnspecified EdgeKind = iota
+	// The head is the destination of events sent by the tail ("to").
+	EdgeKindDestination
+	// The head receives the replies of the tail ("reply_to").
+	EdgeKindReply
+	// The head receives events routed by the tail, possibly filtered.
+	EdgeKindRoute
+	// The head is a subscriber of the tail.
+	EdgeKindSubscription
+	// The head is the dead-letter sink of the tail.
+	EdgeKindDeadLetter
+)
+
+// String implements fmt.Stringer.
+func (k EdgeKind) String() string {
+	switch k {
+	case EdgeKindDestination:
+		return "destination"
+	case EdgeKindReply:
+		return "reply"
+	case EdgeKindRoute:
+		return "route"
+	case EdgeKindSubscription:
+		return "subscription"
+	case EdgeKindDeadLetter:
+		return "dead_letter"
+	default:
+		return ""
+	}
 }
 
 var _ Indexable = (*Edge)(nil)
 
 // Key implements Indexable.
+//
+// Two vertices can be connected by one Edge of each kind, so the kind is part
+// of the key.
 func (e *Edge) Key() interface{} {
-	return fmt.Sprintf("%p->%p", e.Tail, e.Head)
+	return fmt.Sprintf("%p->%p#%d", e.Tail, e.Head, e.Kind)
 }
 
 // Add adds a Vertex to the graph.
@@ -87,11 +132,63 @@ func (g *DirectedGraph) UpEdges() TailVerticesByHeadVertex {
 	return g.upEdges
 }
 
+// Edge returns the Edge of the given kind connecting the given vertices, or
+// nil if these vertices aren't connected by an Edge of that kind.
+func (g *DirectedGraph) Edge(tail, head Vertex, kind EdgeKind) *Edge {
+	return g.edges[indexKey(&Edge{Tail: tail, Head: head, Kind: kind})]
+}
+
+// EdgesBetween returns all the edges connecting the given vertices, ordered by
+// kind. The returned list is empty if these vertices aren't connected.
+func (g *DirectedGraph) EdgesBetween(tail, head Vertex) []*Edge {
+	var es []*Edge
+	for k := EdgeKindUnspecified; k <= EdgeKindDeadLetter; k++ {
+		if e := g.Edge(tail, head, k); e != nil {
+			es = append(es, e)
+		}
+	}
+	return es
+}
+
 // Connect connects two vertices by a directional Edge.
 func (g *DirectedGraph) Connect(tail, head Vertex) {
+	g.ConnectWithKind(tail, head, EdgeKindUnspecified, "")
+}
+
+// ConnectWithKind connects two vertices by a directional Edge of the given
+// kind, optionally labeled.
+//
+// Vertices are connected by at most one Edge of each kind. If the vertices are
+// already connected by an Edge of the given kind, the given label is appended
+// to the label of that Edge. An Edge of unspecified kind is only kept as long
+// as the vertices aren't connected by an Edge of a specified kind.
+func (g *DirectedGraph) ConnectWithKind(tail, head Vertex, kind EdgeKind, label string) {
+	if e := g.Edge(tail, head, kind); e != nil {
+		if label != "" && label != e.Label {
+			if e.Label != "" {
+				e.Label += "\n"
+			}
+			e.Label += label
+		}
+		return
+	}
+
+	if kind == EdgeKindUnspecified {
+		if len(g.EdgesBetween(tail, head)) > 0 {
+			return
+		}
+	} else if e := g.Edge(tail, head, EdgeKindUnspecified); e != nil {
+		delete(g.edges, indexKey(e))
+		if label == "" {
+			label = e.Label
+		}
+	}
+
 	e := &Edge{
-		Tail: tail,
-		Head: head,
+		Tail:  tail,
+		Head:  head,
+		Kind:  kind,
+		Label: label,
 	}
 
 	g.edges.Add(e)
@@ -19,6 +19,8 @@ package graph_test
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	. "til/graph"
 )
 
@@ -58,7 +60,7 @@ func TestDirectedGraph_Add_Connect(t *testing.T) {
 		t.Fatalf("Expected %d edges, got %d", expectNumEdges, numEdges)
 	}
 
-	for _, edge := range []*Edge{{v1, v2}, {v1, v3}} {
+	for _, edge := range []*Edge{{Tail: v1, Head: v2}, {Tail: v1, Head: v3}} {
 		if indexedEdge := edges[edge.Key()]; *indexedEdge != *edge {
 			t.Errorf("Expected indexed edge to equal %q, got %q", *edge, *indexedEdge)
 		}
@@ -106,6 +108,70 @@ func TestDirectedGraph_Add_Connect(t *testing.T) {
 	}
 }
 
+func TestDirectedGraph_ConnectWithKind(t *testing.T) {
+	v1 := fakeVertex("vert1")
+	v2 := fakeVertex("vert2")
+	v3 := fakeVertex("vert3")
+
+	g := NewDirectedGraph()
+
+	g.Add(v1)
+	g.Add(v2)
+	g.Add(v3)
+
+	g.ConnectWithKind(v1, v2, EdgeKindRoute, "type: a")
+	g.ConnectWithKind(v1, v2, EdgeKindRoute, "type: b")
+	g.ConnectWithKind(v1, v2, EdgeKindDeadLetter, "")
+
+	g.Connect(v1, v3)
+	g.ConnectWithKind(v1, v3, EdgeKindReply, "")
+
+	testCases := map[string]struct {
+		tail, head  Vertex
+		expectKinds []EdgeKind
+		expectLabel string
+	}{
+		"Labels of edges of the same kind are merged": {
+			tail:        v1,
+			head:        v2,
+			expectKinds: []EdgeKind{EdgeKindRoute, EdgeKindDeadLetter},
+			expectLabel: "type: a\ntype: b",
+		},
+		"Unspecified kind is replaced": {
+			tail:        v1,
+			head:        v3,
+			expectKinds: []EdgeKind{EdgeKindReply},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			es := g.EdgesBetween(tc.tail, tc.head)
+
+			var kinds []EdgeKind
+			for _, e := range es {
+				kinds = append(kinds, e.Kind)
+			}
+			if diff := cmp.Diff(tc.expectKinds, kinds); diff != "" {
+				t.Fatal("Unexpected diff: (-:expect, +:got)", diff)
+			}
+
+			if e := es[0]; e.Label != tc.expectLabel {
+				t.Errorf("Expected edge label %q, got %q", tc.expectLabel, e.Label)
+			}
+		})
+	}
+
+	g.Connect(v1, v2)
+	if n := len(g.EdgesBetween(v1, v2)); n != 2 {
+		t.Errorf("Expected edge of unspecified kind to be ignored, got %d edges", n)
+	}
+
+	if es := g.EdgesBetween(v2, v1); len(es) != 0 {
+		t.Errorf("Expected no edge between %q and %q, got %v", v2, v1, es)
+	}
+}
+
 // fakeVertex is used as a Vertex type in tests. It is backed by a string so
 // that each instance can be easily printed in error messages.
 type fakeVertex string
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AlphanumericIDs returns, for each of the given vertices, an identifier which
+// contains only alphanumeric characters and underscores. Identifiers are
+// derived from the ID of the vertices' DOTNode representation, and are unique
+// within the returned list.
+//
+// It is meant to be used by marshalers of formats which restrict the set of
+// characters allowed in node identifiers.
+func AlphanumericIDs(vs []Vertex) []string {
+	ids := make([]string, len(vs))
+	seen := make(map[string]struct{}, len(vs))
+
+	for i, v := range vs {
+		id := strings.Map(func(r rune) rune {
+			if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+				return r
+			}
+			return '_'
+		}, NodeFor(v).ID())
+
+		unique := id
+		for n := 2; ; n++ {
+			if _, ok := seen[unique]; !ok {
+				break
+			}
+			unique = id + "_" + strconv.Itoa(n)
+		}
+
+		seen[unique] = struct{}{}
+		ids[i] = unique
+	}
+
+	return ids
+}
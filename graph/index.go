@@ -57,6 +57,12 @@ func (i IndexedVertices) Add(v Vertex) {
 	i[indexKey(v)] = v
 }
 
+// Has returns whether the given Vertex is indexed.
+func (i IndexedVertices) Has(v Vertex) bool {
+	_, ok := i[indexKey(v)]
+	return ok
+}
+
 // Add indexes an Edge.
 func (i IndexedEdges) Add(e *Edge) {
 	i[indexKey(e)] = e
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsongraph contains helpers for encoding graphs to JSON.
+//
+// The JSON representation of a graph has a stable schema, which is meant to be
+// consumed by third-party tools:
+//
+//   {
+//     "vertices": [
+//       {
+//         "id":         "router.my_router",  // unique ID of the vertex
+//         "category":   "router",            // category of Bridge component
+//         "type":       "content_based",     // type of Bridge component
+//         "identifier": "my_router",         // identifier of Bridge component
+//         "source_range": {                  // location in the Bridge description
+//           "filename": "my_bridge.brg.hcl",
+//           "start": { "line": 12, "column": 1, "byte": 210 },
+//           "end":   { "line": 12, "column": 34, "byte": 243 }
+//         }
+//       }
+//     ],
+//     "edges": [
+//       {
+//         "tail": "source.my_source",  // ID of the vertex the edge starts from
+//         "head": "router.my_router",  // ID of the vertex the edge points to
+//         "kind": "destination",       // kind of relationship between the vertices
+//         "label": "type: my.type"     // description of the edge, e.g. an event filter
+//       }
+//     ]
+//   }
+//
+// The "type" and "source_range" attributes are omitted for vertices which do
+// not represent a Bridge component. Vertices and edges are sorted by ID.
+//
+// The "kind" of an edge is one of "destination", "reply", "route",
+// "subscription" or "dead_letter", and is omitted when unspecified. The "label"
+// of an edge is omitted when empty.
+package jsongraph
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsongraph
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"til/graph"
+)
+
+// Graph is the JSON representation of a graph.
+type Graph struct {
+	Vertices []Vertex `json:"vertices"`
+	Edges    []Edge   `json:"edges"`
+}
+
+// Vertex is the JSON representation of a graph vertex.
+type Vertex struct {
+	ID          string       `json:"id"`
+	Category    string       `json:"category"`
+	Type        string       `json:"type,omitempty"`
+	Identifier  string       `json:"identifier"`
+	SourceRange *SourceRange `json:"source_range,omitempty"`
+}
+
+// Edge is the JSON representation of a graph edge.
+type Edge struct {
+	Tail  string `json:"tail"`
+	Head  string `json:"head"`
+	Kind  string `json:"kind,omitempty"`
+	Label string `json:"label,omitempty"`
+}
+
+// SourceRange is the JSON representation of a range in a source file.
+type SourceRange struct {
+	Filename string `json:"filename"`
+	Start    Pos    `json:"start"`
+	End      Pos    `json:"end"`
+}
+
+// Pos is the JSON representation of a position in a source file.
+type Pos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// Marshal serializes a graph to JSON.
+func Marshal(g *graph.DirectedGraph) ([]byte, error) {
+	vs := g.SortedVertices()
+	es := g.SortedEdges()
+
+	jg := Graph{
+		Vertices: make([]Vertex, 0, len(vs)),
+		Edges:    make([]Edge, 0, len(es)),
+	}
+
+	for _, v := range vs {
+		jg.Vertices = append(jg.Vertices, vertex(v))
+	}
+
+	for _, e := range es {
+		jg.Edges = append(jg.Edges, Edge{
+			Tail:  graph.NodeFor(e.Tail).ID(),
+			Head:  graph.NodeFor(e.Head).ID(),
+			Kind:  e.Kind.String(),
+			Label: e.Label,
+		})
+	}
+
+	b, err := json.MarshalIndent(jg, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+// vertex returns the JSON representation of a graph.Vertex.
+func vertex(v graph.Vertex) Vertex {
+	n := graph.NodeFor(v)
+
+	jv := Vertex{
+		ID:         n.ID(),
+		Category:   n.Header,
+		Identifier: n.Body,
+	}
+
+	if dv, ok := v.(graph.DescribableVertex); ok {
+		desc := dv.Description()
+
+		jv.Category = desc.Category
+		jv.Type = desc.Type
+		jv.Identifier = desc.Identifier
+		jv.SourceRange = sourceRange(desc.SourceRange)
+	}
+
+	return jv
+}
+
+// sourceRange returns the JSON representation of a hcl.Range, or nil if the
+// range is empty.
+func sourceRange(rng hcl.Range) *SourceRange {
+	if rng.Filename == "" {
+		return nil
+	}
+
+	return &SourceRange{
+		Filename: rng.Filename,
+		Start:    Pos{Line: rng.Start.Line, Column: rng.Start.Column, Byte: rng.Start.Byte},
+		End:      Pos{Line: rng.End.Line, Column: rng.End.Column, Byte: rng.End.Byte},
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsongraph_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"til/graph"
+	. "til/graph/jsongraph"
+)
+
+func TestMarshal(t *testing.T) {
+	g := graph.NewDirectedGraph()
+
+	// graph vertices;
+	// any Go type is valid from the "graph" package's perspective
+	v1 := "some_node"
+	v2 := fakeDescribableVertex{category: "source", typ: "some_type", identifier: "some_source"}
+
+	g.Add(v1)
+	g.Add(v2)
+
+	g.Connect(v2, v1)
+
+	b, err := Marshal(g)
+	if err != nil {
+		t.Fatal("Error marshaling graph:", err)
+	}
+
+	if string(b) != referenceJSONGraph {
+		t.Error("JSON graph differs from reference:\n" + string(b))
+	}
+}
+
+// fakeDescribableVertex is meant to be used as a graph.Vertex in tests.
+type fakeDescribableVertex struct {
+	category   string
+	typ        string
+	identifier string
+}
+
+var (
+	_ graph.DOTableVertex     = (*fakeDescribableVertex)(nil)
+	_ graph.DescribableVertex = (*fakeDescribableVertex)(nil)
+)
+
+// Node implements graph.DOTableVertex.
+func (v fakeDescribableVertex) Node() graph.DOTNode {
+	return graph.DOTNode{
+		Header: v.category,
+		Body:   v.identifier,
+	}
+}
+
+// Description implements graph.DescribableVertex.
+func (v fakeDescribableVertex) Description() graph.VertexDescription {
+	return graph.VertexDescription{
+		Category:   v.category,
+		Type:       v.typ,
+		Identifier: v.identifier,
+		SourceRange: hcl.Range{
+			Filename: "test.brg.hcl",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 1, Column: 31, Byte: 30},
+		},
+	}
+}
+
+const referenceJSONGraph = `{
+  "vertices": [
+    {
+      "id": "source.some_source",
+      "category": "source",
+      "type": "some_type",
+      "identifier": "some_source",
+      "source_range": {
+        "filename": "test.brg.hcl",
+        "start": {
+          "line": 1,
+          "column": 1,
+          "byte": 0
+        },
+        "end": {
+          "line": 1,
+          "column": 31,
+          "byte": 30
+        }
+      }
+    },
+    {
+      "id": "string.some_node",
+      "category": "string",
+      "identifier": "some_node"
+    }
+  ],
+  "edges": [
+    {
+      "tail": "source.some_source",
+      "head": "string.some_node"
+    }
+  ]
+}
+`
@@ -0,0 +1,97 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mermaid
+
+import (
+	"bytes"
+	"strings"
+
+	"til/graph"
+)
+
+// Marshal serializes a graph to a Mermaid flowchart.
+func Marshal(g *graph.DirectedGraph) ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString("flowchart LR\n")
+
+	vs := g.SortedVertices()
+	ids := graph.AlphanumericIDs(vs)
+
+	// IDs of vertices indexed by the ID of their DOTNode representation
+	vertIDs := make(map[string]string, len(vs))
+
+	// Vertices
+
+	for i, v := range vs {
+		n := graph.NodeFor(v)
+		vertIDs[n.ID()] = ids[i]
+
+		b.WriteString("    " + ids[i] + `["` + escape(n.Header) + "<br/><b>" + escape(n.Body) + `</b>"]` + "\n")
+	}
+
+	b.WriteByte('\n')
+
+	// Edges
+
+	for _, e := range g.SortedEdges() {
+		b.WriteString("    " + vertIDs[graph.NodeFor(e.Tail).ID()] + " " + arrow(e) + " " + vertIDs[graph.NodeFor(e.Head).ID()] + "\n")
+	}
+
+	b.WriteByte('\n')
+
+	// Styles
+
+	for i, v := range vs {
+		accent, headerTxt := graph.NodeFor(v).Colors()
+		accent, headerTxt = graph.WebColor(accent), graph.WebColor(headerTxt)
+
+		b.WriteString("    style " + ids[i] + " fill:" + accent + ",stroke:" + accent + ",color:" + headerTxt + "\n")
+	}
+
+	return b.Bytes(), nil
+}
+
+// arrow returns the Mermaid link representing the given edge. Replies and
+// dead-lettered events are represented by dotted links.
+func arrow(e *graph.Edge) string {
+	dashed := e.Kind == graph.EdgeKindReply || e.Kind == graph.EdgeKindDeadLetter
+
+	switch {
+	case dashed && e.Label != "":
+		return `-. "` + escape(e.Label) + `" .->`
+	case dashed:
+		return "-.->"
+	case e.Label != "":
+		return `-- "` + escape(e.Label) + `" -->`
+	}
+	return "-->"
+}
+
+// labelEscaper escapes characters which have a special meaning in Mermaid
+// labels.
+var labelEscaper = strings.NewReplacer(
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+	"\n", "<br/>",
+)
+
+// escape escapes the given label text.
+func escape(s string) string {
+	return labelEscaper.Replace(s)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mermaid_test
+
+import (
+	"testing"
+
+	"til/graph"
+	. "til/graph/mermaid"
+)
+
+func TestMarshal(t *testing.T) {
+	g := graph.NewDirectedGraph()
+
+	// graph vertices;
+	// any Go type is valid from the "graph" package's perspective
+	v1 := "some_node"
+	v2 := 42
+	v3 := fakeDOTableVertex{header: "some_header", body: "some-body"}
+
+	g.Add(v1)
+	g.Add(v2)
+	g.Add(v3)
+
+	g.Connect(v1, v2)
+	g.Connect(v1, v3)
+	g.Connect(v2, v3)
+
+	b, err := Marshal(g)
+	if err != nil {
+		t.Fatal("Error marshaling graph:", err)
+	}
+
+	if string(b) != referenceGraph {
+		t.Error("Graph differs from reference:\n" + string(b))
+	}
+}
+
+const (
+	testAccentColor    = "/set26/1"
+	testHeaderTxtColor = "floralwhite"
+)
+
+// fakeDOTableVertex is meant to be used as a graph.Vertex in tests.
+type fakeDOTableVertex struct {
+	header string
+	body   string
+}
+
+var _ graph.DOTableVertex = (*fakeDOTableVertex)(nil)
+
+// Node implements graph.DOTableVertex.
+func (v fakeDOTableVertex) Node() graph.DOTNode {
+	return graph.DOTNode{
+		Header: v.header,
+		Body:   v.body,
+		Style: &graph.DOTNodeStyle{
+			AccentColor:     testAccentColor,
+			HeaderTextColor: testHeaderTxtColor,
+		},
+	}
+}
+
+const referenceGraph = `flowchart LR
+    int_42["int<br/><b>42</b>"]
+    some_header_some_body["some_header<br/><b>some-body</b>"]
+    string_some_node["string<br/><b>some_node</b>"]
+
+    int_42 --> some_header_some_body
+    string_some_node --> int_42
+    string_some_node --> some_header_some_body
+
+    style int_42 fill:#dcdcdc,stroke:#dcdcdc,color:#000000
+    style some_header_some_body fill:#66c2a5,stroke:#66c2a5,color:floralwhite
+    style string_some_node fill:#dcdcdc,stroke:#dcdcdc,color:#000000
+`
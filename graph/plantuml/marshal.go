@@ -0,0 +1,103 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plantuml
+
+import (
+	"bytes"
+	"strings"
+
+	"til/graph"
+)
+
+// Marshal serializes a graph to a PlantUML diagram.
+func Marshal(g *graph.DirectedGraph) ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteString("@startuml\n")
+	b.WriteByte('\n')
+	b.WriteString("left to right direction\n")
+	b.WriteString("skinparam defaultFontName Helvetica\n")
+	b.WriteByte('\n')
+
+	vs := g.SortedVertices()
+	ids := graph.AlphanumericIDs(vs)
+
+	// IDs of vertices indexed by the ID of their DOTNode representation
+	vertIDs := make(map[string]string, len(vs))
+
+	// Vertices
+
+	for i, v := range vs {
+		n := graph.NodeFor(v)
+		vertIDs[n.ID()] = ids[i]
+
+		accent, headerTxt := n.Colors()
+		accent, headerTxt = graph.WebColor(accent), graph.WebColor(headerTxt)
+
+		b.WriteString(`rectangle "<color:` + headerTxt + `>**` + escape(n.Header) + `**</color>\n` +
+			escape(n.Body) + `" as ` + ids[i] + " " + color(accent) + "\n")
+	}
+
+	b.WriteByte('\n')
+
+	// Edges
+
+	for _, e := range g.SortedEdges() {
+		b.WriteString(vertIDs[graph.NodeFor(e.Tail).ID()] + " " + arrow(e) + " " + vertIDs[graph.NodeFor(e.Head).ID()])
+		if e.Label != "" {
+			b.WriteString(" : " + escape(e.Label))
+		}
+		b.WriteByte('\n')
+	}
+
+	b.WriteByte('\n')
+
+	b.WriteString("@enduml\n")
+
+	return b.Bytes(), nil
+}
+
+// arrow returns the PlantUML arrow representing the given edge. Replies and
+// dead-lettered events are represented by dashed arrows.
+func arrow(e *graph.Edge) string {
+	switch e.Kind {
+	case graph.EdgeKindReply, graph.EdgeKindDeadLetter:
+		return "..>"
+	}
+	return "-->"
+}
+
+// color returns the PlantUML representation of a web color.
+func color(c string) string {
+	if strings.HasPrefix(c, "#") {
+		return c
+	}
+	return "#" + c
+}
+
+// labelEscaper replaces characters which can not be represented as is in
+// PlantUML labels.
+var labelEscaper = strings.NewReplacer(
+	`"`, "'",
+	"\n", `\n`,
+)
+
+// escape replaces characters which can not be represented as is in PlantUML
+// labels.
+func escape(s string) string {
+	return labelEscaper.Replace(s)
+}
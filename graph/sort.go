@@ -0,0 +1,74 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import "sort"
+
+// SortedVertices returns the vertices of the graph sorted by the ID of their
+// DOTNode representation. It allows marshalers to produce a deterministic
+// output.
+func (g *DirectedGraph) SortedVertices() []Vertex {
+	vs := make([]Vertex, 0, len(g.vertices))
+	ids := make(map[interface{}]string, len(g.vertices))
+
+	for k, v := range g.vertices {
+		vs = append(vs, v)
+		ids[k] = NodeFor(v).ID()
+	}
+
+	sort.Slice(vs, func(i, j int) bool {
+		return ids[indexKey(vs[i])] < ids[indexKey(vs[j])]
+	})
+
+	return vs
+}
+
+// SortedEdges returns the edges of the graph sorted by the IDs of the DOTNode
+// representations of their tail and head vertices, then by kind. It allows
+// marshalers to produce a deterministic output.
+func (g *DirectedGraph) SortedEdges() []*Edge {
+	type sortableEdge struct {
+		*Edge
+		tailID, headID string
+	}
+
+	ses := make([]sortableEdge, 0, len(g.edges))
+	for _, e := range g.edges {
+		ses = append(ses, sortableEdge{
+			Edge:   e,
+			tailID: NodeFor(e.Tail).ID(),
+			headID: NodeFor(e.Head).ID(),
+		})
+	}
+
+	sort.Slice(ses, func(i, j int) bool {
+		if ses[i].tailID != ses[j].tailID {
+			return ses[i].tailID < ses[j].tailID
+		}
+		if ses[i].headID != ses[j].headID {
+			return ses[i].headID < ses[j].headID
+		}
+		return ses[i].Kind < ses[j].Kind
+	})
+
+	es := make([]*Edge, 0, len(ses))
+	for _, se := range ses {
+		es = append(es, se.Edge)
+	}
+
+	return es
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+// Subgraph returns a new graph composed of the vertices of g for which
+// keepVertex returns true, and of the edges of g which connect these vertices
+// and for which keepEdge returns true. Kinds and labels of edges are
+// preserved.
+//
+// A nil function is equivalent to a function which always returns true.
+func (g *DirectedGraph) Subgraph(keepVertex func(Vertex) bool, keepEdge func(*Edge) bool) *DirectedGraph {
+	sg := NewDirectedGraph()
+
+	for _, v := range g.vertices {
+		if keepVertex == nil || keepVertex(v) {
+			sg.Add(v)
+		}
+	}
+
+	for _, e := range g.edges {
+		_, hasTail := sg.vertices[indexKey(e.Tail)]
+		_, hasHead := sg.vertices[indexKey(e.Head)]
+		if !hasTail || !hasHead {
+			continue
+		}
+
+		if keepEdge == nil || keepEdge(e) {
+			sg.ConnectWithKind(e.Tail, e.Head, e.Kind, e.Label)
+		}
+	}
+
+	return sg
+}
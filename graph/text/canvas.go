@@ -0,0 +1,208 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package text
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Directions in which a line drawn on a cell of the canvas extends.
+const (
+	up uint8 = 1 << iota
+	down
+	left
+	right
+)
+
+// Runes used to draw lines, indexed by the directions in which a line
+// extends.
+var lineRunes = map[uint8]rune{
+	up:                       '│',
+	down:                     '│',
+	up | down:                '│',
+	left:                     '─',
+	right:                    '─',
+	left | right:             '─',
+	down | right:             '┌',
+	down | left:              '┐',
+	up | right:               '└',
+	up | left:                '┘',
+	up | down | right:        '├',
+	up | down | left:         '┤',
+	left | right | down:      '┬',
+	left | right | up:        '┴',
+	up | down | left | right: '┼',
+}
+
+// Runes used to draw dashed lines.
+const (
+	dashedHorizontal = '╌'
+	dashedVertical   = '╎'
+)
+
+// Rune used to draw arrow heads.
+const arrowHead = '►'
+
+// cell is a character cell of a canvas.
+type cell struct {
+	// directions of the lines drawn on the cell
+	lines uint8
+	// whether at least one solid or dashed line was drawn on the cell
+	solid, dashed bool
+	// rune which takes precedence over lines, if set
+	r rune
+}
+
+// canvas is a two-dimensional grid of character cells on which lines and text
+// can be drawn.
+type canvas struct {
+	cells [][]cell
+}
+
+// at returns the cell at the given coordinates, growing the canvas if
+// necessary.
+func (c *canvas) at(x, y int) *cell {
+	for len(c.cells) <= y {
+		c.cells = append(c.cells, nil)
+	}
+	for len(c.cells[y]) <= x {
+		c.cells[y] = append(c.cells[y], cell{})
+	}
+	return &c.cells[y][x]
+}
+
+// line draws a horizontal or vertical line between two points.
+func (c *canvas) line(x1, y1, x2, y2 int, dashed bool) {
+	if x1 == x2 && y1 == y2 {
+		return
+	}
+
+	// from/to are the directions of the line on the first and last cells
+	var from, to uint8
+	dx, dy := 0, 0
+
+	switch {
+	case x1 < x2:
+		from, to, dx = right, left, 1
+	case x1 > x2:
+		from, to, dx = left, right, -1
+	case y1 < y2:
+		from, to, dy = down, up, 1
+	default:
+		from, to, dy = up, down, -1
+	}
+
+	for x, y := x1, y1; ; x, y = x+dx, y+dy {
+		dirs := from | to
+		switch {
+		case x == x1 && y == y1:
+			dirs = from
+		case x == x2 && y == y2:
+			dirs = to
+		}
+
+		cl := c.at(x, y)
+		cl.lines |= dirs
+		if dashed {
+			cl.dashed = true
+		} else {
+			cl.solid = true
+		}
+
+		if x == x2 && y == y2 {
+			break
+		}
+	}
+}
+
+// path draws a line which goes through all the given points.
+func (c *canvas) path(dashed bool, points ...[2]int) {
+	for i := 1; i < len(points); i++ {
+		c.line(points[i-1][0], points[i-1][1], points[i][0], points[i][1], dashed)
+	}
+}
+
+// box draws a rectangle with the given top-left corner and size.
+func (c *canvas) box(x, y, w, h int) {
+	c.path(false,
+		[2]int{x, y},
+		[2]int{x + w - 1, y},
+		[2]int{x + w - 1, y + h - 1},
+		[2]int{x, y + h - 1},
+		[2]int{x, y},
+	)
+}
+
+// text writes the given text horizontally, starting at the given
+// coordinates.
+func (c *canvas) text(x, y int, s string) {
+	for i, r := range []rune(s) {
+		c.at(x+i, y).r = r
+	}
+}
+
+// set sets the rune of the cell at the given coordinates.
+func (c *canvas) set(x, y int, r rune) {
+	c.at(x, y).r = r
+}
+
+// bytes returns the content of the canvas, with trailing whitespaces removed.
+func (c *canvas) bytes() []byte {
+	var b bytes.Buffer
+
+	var lines []string
+	for _, row := range c.cells {
+		var l strings.Builder
+		for _, cl := range row {
+			l.WriteRune(cl.render())
+		}
+		lines = append(lines, strings.TrimRight(l.String(), " "))
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes()
+}
+
+// render returns the rune which represents the cell.
+func (cl cell) render() rune {
+	if cl.r != 0 {
+		return cl.r
+	}
+	if cl.lines == 0 {
+		return ' '
+	}
+
+	if cl.dashed && !cl.solid {
+		switch cl.lines {
+		case left, right, left | right:
+			return dashedHorizontal
+		case up, down, up | down:
+			return dashedVertical
+		}
+	}
+
+	return lineRunes[cl.lines]
+}
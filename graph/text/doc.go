@@ -0,0 +1,23 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package text contains helpers for rendering graphs as text diagrams made of
+// Unicode box-drawing characters, which can be displayed in a terminal.
+//
+// Vertices are laid out from left to right in layers, so that edges point
+// rightwards whenever possible. Edges which point backwards, such as replies
+// sent to upstream components, are routed below all vertices.
+package text
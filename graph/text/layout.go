@@ -0,0 +1,294 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package text
+
+import (
+	"sort"
+
+	"til/graph"
+)
+
+// node is a vertex positioned in the layout. Nodes which don't represent a
+// graph vertex are "dummy" nodes, inserted on edges which span several layers
+// so that these edges can be routed between the vertices of these layers.
+type node struct {
+	header string
+	body   string
+	dummy  bool
+
+	// index of the layer ("column") the node belongs to
+	layer int
+	// position of the node within its layer
+	slot int
+}
+
+// hop is a segment of an edge which connects nodes of two adjacent layers.
+type hop struct {
+	tail, head *node
+	dashed     bool
+}
+
+// backEdge is an edge which points to a node of a previous layer, or to its
+// own tail.
+type backEdge struct {
+	tail, head *node
+	dashed     bool
+}
+
+// layout is the result of the placement of the vertices of a graph.
+type layout struct {
+	layers    [][]*node
+	hops      []hop
+	backEdges []backEdge
+}
+
+// newLayout places the vertices of the given graph into layers, so that the
+// majority of edges connect a node to a node of a subsequent layer.
+//
+// The algorithm is a simplified version of the layered graph drawing method
+// by Sugiyama et al.[1]:
+//  1. cycles are broken by reversing edges which close a cycle,
+//  2. vertices are assigned to layers based on the longest path leading to them,
+//  3. dummy nodes are inserted on edges which span multiple layers,
+//  4. nodes are ordered within layers using the barycenter heuristic.
+//
+// [1] https://en.wikipedia.org/wiki/Layered_graph_drawing
+func newLayout(g *graph.DirectedGraph) *layout {
+	vs := g.SortedVertices()
+	es := g.SortedEdges()
+
+	nodes := make(map[interface{}]*node, len(vs))
+	sortedNodes := make([]*node, 0, len(vs))
+	for _, v := range vs {
+		dn := graph.NodeFor(v)
+		n := &node{
+			header: dn.Header,
+			body:   dn.Body,
+		}
+		nodes[vertexKey(v)] = n
+		sortedNodes = append(sortedNodes, n)
+	}
+
+	// edges indexed by tail node, in deterministic order
+	outEdges := make(map[*node][]*graph.Edge, len(vs))
+	hasInEdges := make(map[*node]bool, len(vs))
+	for _, e := range es {
+		tail, head := nodes[vertexKey(e.Tail)], nodes[vertexKey(e.Head)]
+		outEdges[tail] = append(outEdges[tail], e)
+		if tail != head {
+			hasInEdges[head] = true
+		}
+	}
+
+	// 1. Find edges closing a cycle with a depth-first search, starting
+	// from the vertices which have no incoming edge. The post-order of the
+	// search is a reverse topological order of the graph without these edges.
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[*node]int, len(vs))
+	isBackEdge := make(map[*graph.Edge]bool)
+	postOrder := make([]*node, 0, len(vs))
+
+	var visit func(n *node)
+	visit = func(n *node) {
+		state[n] = visiting
+		for _, e := range outEdges[n] {
+			head := nodes[vertexKey(e.Head)]
+			switch state[head] {
+			case visiting:
+				isBackEdge[e] = true
+			case unvisited:
+				visit(head)
+			}
+		}
+		state[n] = visited
+		postOrder = append(postOrder, n)
+	}
+
+	for _, n := range sortedNodes {
+		if !hasInEdges[n] && state[n] == unvisited {
+			visit(n)
+		}
+	}
+	for _, n := range sortedNodes {
+		if state[n] == unvisited {
+			visit(n)
+		}
+	}
+
+	// 2. Assign layers in topological order.
+
+	for i := len(postOrder) - 1; i >= 0; i-- {
+		tail := postOrder[i]
+		for _, e := range outEdges[tail] {
+			if isBackEdge[e] {
+				continue
+			}
+			if head := nodes[vertexKey(e.Head)]; head.layer < tail.layer+1 {
+				head.layer = tail.layer + 1
+			}
+		}
+	}
+
+	l := &layout{}
+
+	addToLayer := func(n *node) {
+		for len(l.layers) <= n.layer {
+			l.layers = append(l.layers, nil)
+		}
+		l.layers[n.layer] = append(l.layers[n.layer], n)
+	}
+
+	for _, n := range sortedNodes {
+		addToLayer(n)
+	}
+
+	// 3. Split edges into hops between adjacent layers.
+
+	for _, e := range es {
+		tail, head := nodes[vertexKey(e.Tail)], nodes[vertexKey(e.Head)]
+		dashed := isDashed(e)
+
+		if isBackEdge[e] {
+			l.backEdges = append(l.backEdges, backEdge{tail: tail, head: head, dashed: dashed})
+			continue
+		}
+
+		prev := tail
+		for layer := tail.layer + 1; layer < head.layer; layer++ {
+			d := &node{dummy: true, layer: layer}
+			addToLayer(d)
+			l.hops = append(l.hops, hop{tail: prev, head: d, dashed: dashed})
+			prev = d
+		}
+		l.hops = append(l.hops, hop{tail: prev, head: head, dashed: dashed})
+	}
+
+	// 4. Order nodes within layers, then assign them a slot.
+
+	l.orderNodes()
+	l.assignSlots()
+
+	return l
+}
+
+// orderNodes orders the nodes of each layer by the average position of their
+// neighbours in the adjacent layer, alternately sweeping the layers downwards
+// and upwards to reduce the number of crossing edges.
+func (l *layout) orderNodes() {
+	preds := make(map[*node][]*node)
+	succs := make(map[*node][]*node)
+	for _, h := range l.hops {
+		preds[h.head] = append(preds[h.head], h.tail)
+		succs[h.tail] = append(succs[h.tail], h.head)
+	}
+
+	position := func(layer []*node) map[*node]int {
+		pos := make(map[*node]int, len(layer))
+		for i, n := range layer {
+			pos[n] = i
+		}
+		return pos
+	}
+
+	sortByBarycenter := func(layer []*node, neighbours map[*node][]*node, adjPos map[*node]int) {
+		curPos := position(layer)
+
+		barycenter := make(map[*node]float64, len(layer))
+		for _, n := range layer {
+			ns := neighbours[n]
+			if len(ns) == 0 {
+				barycenter[n] = float64(curPos[n])
+				continue
+			}
+
+			var sum int
+			for _, adj := range ns {
+				sum += adjPos[adj]
+			}
+			barycenter[n] = float64(sum) / float64(len(ns))
+		}
+
+		sort.SliceStable(layer, func(i, j int) bool {
+			return barycenter[layer[i]] < barycenter[layer[j]]
+		})
+	}
+
+	const sweeps = 2
+
+	for s := 0; s < sweeps; s++ {
+		for i := 1; i < len(l.layers); i++ {
+			sortByBarycenter(l.layers[i], preds, position(l.layers[i-1]))
+		}
+		for i := len(l.layers) - 2; i >= 0; i-- {
+			sortByBarycenter(l.layers[i], succs, position(l.layers[i+1]))
+		}
+	}
+	for i := 1; i < len(l.layers); i++ {
+		sortByBarycenter(l.layers[i], preds, position(l.layers[i-1]))
+	}
+}
+
+// assignSlots assigns each node a slot within its layer. Nodes are aligned
+// with their predecessors whenever possible so that edges are straight.
+func (l *layout) assignSlots() {
+	preds := make(map[*node][]*node)
+	for _, h := range l.hops {
+		preds[h.head] = append(preds[h.head], h.tail)
+	}
+
+	for _, layer := range l.layers {
+		next := 0
+
+		for _, n := range layer {
+			slot := next
+
+			if ps := preds[n]; len(ps) > 0 {
+				var sum int
+				for _, p := range ps {
+					sum += p.slot
+				}
+				if avg := sum / len(ps); avg > slot {
+					slot = avg
+				}
+			}
+
+			n.slot = slot
+			next = slot + 1
+		}
+	}
+}
+
+// isDashed returns whether the given edge should be represented by a dashed
+// line.
+func isDashed(e *graph.Edge) bool {
+	return e.Kind == graph.EdgeKindReply || e.Kind == graph.EdgeKindDeadLetter
+}
+
+// vertexKey returns a key which uniquely identifies the given vertex within a
+// graph.
+func vertexKey(v graph.Vertex) interface{} {
+	if i, ok := v.(graph.Indexable); ok {
+		return i.Key()
+	}
+	return v
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package text
+
+import (
+	"sort"
+
+	"til/graph"
+)
+
+// Dimensions of the elements of the diagram, in number of character cells.
+const (
+	// height of the area occupied by a node: caption, box, spacing
+	slotHeight = 5
+	// height of a node's box
+	boxHeight = 3
+	// horizontal space between the border of a node's box and its label
+	boxPadding = 1
+	// space on the left of the first layer when no edge needs to be
+	// routed there
+	leftMargin = 2
+)
+
+// Marshal renders a graph as a text diagram.
+func Marshal(g *graph.DirectedGraph) ([]byte, error) {
+	l := newLayout(g)
+
+	nLayers := len(l.layers)
+
+	// Dimensions of layers
+
+	boxWidths := make([]int, nLayers)
+	colWidths := make([]int, nLayers)
+	maxSlot := 0
+
+	for i, layer := range l.layers {
+		for _, n := range layer {
+			if n.slot > maxSlot {
+				maxSlot = n.slot
+			}
+			if n.dummy {
+				continue
+			}
+
+			if w := runeLen(n.body) + 2*boxPadding + 2; w > boxWidths[i] {
+				boxWidths[i] = w
+			}
+			if w := runeLen(n.header) + 1; w > colWidths[i] {
+				colWidths[i] = w
+			}
+		}
+
+		if boxWidths[i] > colWidths[i] {
+			colWidths[i] = boxWidths[i]
+		}
+	}
+
+	// Vertical channels in which edges are routed between layers.
+	// Gap i is located before layer i, the last gap after the last layer.
+
+	gaps := make([]channels, nLayers+1)
+	for _, be := range l.backEdges {
+		gaps[be.tail.layer+1].backOut = addOnce(gaps[be.tail.layer+1].backOut, be.tail)
+		gaps[be.head.layer].backIn = addOnce(gaps[be.head.layer].backIn, be.head)
+	}
+	for _, h := range l.hops {
+		gaps[h.tail.layer+1].forward = addOnce(gaps[h.tail.layer+1].forward, h.tail)
+	}
+
+	colXs := make([]int, nLayers)
+	x := 0
+	for i := range gaps {
+		gaps[i].sort()
+		gaps[i].x = x
+
+		w := gaps[i].width()
+		switch {
+		case w == 0 && i == 0:
+			w = leftMargin
+		case w == 0 && i < nLayers:
+			w = 3
+		}
+		x += w
+
+		if i < nLayers {
+			colXs[i] = x
+			x += colWidths[i]
+		}
+	}
+
+	inPoint := func(n *node) (int, int) {
+		return colXs[n.layer], n.slot*slotHeight + 2
+	}
+	outPoint := func(n *node) (int, int) {
+		if n.dummy {
+			return colXs[n.layer] + colWidths[n.layer] - 1, n.slot*slotHeight + 2
+		}
+		return colXs[n.layer] + boxWidths[n.layer] - 1, n.slot*slotHeight + 2
+	}
+
+	var c canvas
+
+	// Nodes
+
+	for i, layer := range l.layers {
+		for _, n := range layer {
+			if n.dummy {
+				continue
+			}
+
+			y := n.slot * slotHeight
+			c.text(colXs[i]+1, y, n.header)
+			c.box(colXs[i], y+1, boxWidths[i], boxHeight)
+			c.text(colXs[i]+1+boxPadding, y+2, n.body)
+		}
+	}
+
+	// Edges
+
+	for _, h := range l.hops {
+		ox, oy := outPoint(h.tail)
+		ix, iy := inPoint(h.head)
+		cx := gaps[h.tail.layer+1].forwardX(h.tail)
+
+		c.path(h.dashed, [2]int{ox, oy}, [2]int{cx, oy}, [2]int{cx, iy}, [2]int{ix, iy})
+
+		if h.head.dummy {
+			dx, dy := outPoint(h.head)
+			c.line(ix, iy, dx, dy, h.dashed)
+		} else {
+			c.set(ix, iy, arrowHead)
+		}
+	}
+
+	lanesY := (maxSlot+1)*slotHeight - 1
+
+	for i, be := range l.backEdges {
+		ox, oy := outPoint(be.tail)
+		ix, iy := inPoint(be.head)
+		cox := gaps[be.tail.layer+1].backOutX(be.tail)
+		cix := gaps[be.head.layer].backInX(be.head)
+		laneY := lanesY + i
+
+		c.path(be.dashed,
+			[2]int{ox, oy},
+			[2]int{cox, oy},
+			[2]int{cox, laneY},
+			[2]int{cix, laneY},
+			[2]int{cix, iy},
+			[2]int{ix, iy},
+		)
+
+		c.set(ix, iy, arrowHead)
+	}
+
+	return c.bytes(), nil
+}
+
+// channels represents the vertical channels of a gap between two layers. Each
+// channel is dedicated to the edges of a given node.
+type channels struct {
+	// horizontal position of the gap
+	x int
+
+	// nodes which have edges pointing backwards
+	backOut []*node
+	// nodes which have edges pointing to the next layer
+	forward []*node
+	// nodes which are pointed to by edges pointing backwards
+	backIn []*node
+}
+
+// width returns the width of the gap.
+func (ch *channels) width() int {
+	n := len(ch.backOut) + len(ch.forward) + len(ch.backIn)
+	if n == 0 {
+		return 0
+	}
+	return 2*n + 1
+}
+
+// sort orders channels by position of their node within its layer.
+func (ch *channels) sort() {
+	for _, ns := range [][]*node{ch.backOut, ch.forward, ch.backIn} {
+		sort.SliceStable(ns, func(i, j int) bool {
+			return ns[i].slot < ns[j].slot
+		})
+	}
+}
+
+// backOutX returns the horizontal position of the channel of the given node's
+// backward edges.
+func (ch *channels) backOutX(n *node) int {
+	return ch.channelX(indexOf(ch.backOut, n))
+}
+
+// forwardX returns the horizontal position of the channel of the given node's
+// forward edges.
+func (ch *channels) forwardX(n *node) int {
+	return ch.channelX(len(ch.backOut) + indexOf(ch.forward, n))
+}
+
+// backInX returns the horizontal position of the channel of the backward
+// edges pointing to the given node.
+func (ch *channels) backInX(n *node) int {
+	return ch.channelX(len(ch.backOut) + len(ch.forward) + indexOf(ch.backIn, n))
+}
+
+// channelX returns the horizontal position of the channel with the given
+// index.
+func (ch *channels) channelX(i int) int {
+	return ch.x + 1 + 2*i
+}
+
+// addOnce appends n to ns unless ns already contains n.
+func addOnce(ns []*node, n *node) []*node {
+	if indexOf(ns, n) != -1 {
+		return ns
+	}
+	return append(ns, n)
+}
+
+// indexOf returns the index of n in ns, or -1 if ns doesn't contain n.
+func indexOf(ns []*node, n *node) int {
+	for i := range ns {
+		if ns[i] == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// runeLen returns the number of runes in s.
+func runeLen(s string) int {
+	return len([]rune(s))
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package text_test
+
+import (
+	"testing"
+
+	"til/graph"
+	. "til/graph/text"
+)
+
+func TestMarshal(t *testing.T) {
+	g := graph.NewDirectedGraph()
+
+	v1 := fakeDOTableVertex{header: "source", body: "some_source"}
+	v2 := fakeDOTableVertex{header: "router", body: "some_router"}
+	v3 := fakeDOTableVertex{header: "target", body: "some_target"}
+	v4 := fakeDOTableVertex{header: "target", body: "other_target"}
+
+	g.Add(v1)
+	g.Add(v2)
+	g.Add(v3)
+	g.Add(v4)
+
+	g.ConnectWithKind(v1, v2, graph.EdgeKindDestination, "")
+	g.ConnectWithKind(v2, v3, graph.EdgeKindRoute, "")
+	g.ConnectWithKind(v1, v4, graph.EdgeKindDestination, "")
+	g.ConnectWithKind(v3, v2, graph.EdgeKindReply, "")
+
+	b, err := Marshal(g)
+	if err != nil {
+		t.Fatal("Error marshaling graph:", err)
+	}
+
+	if string(b) != referenceTextGraph {
+		t.Error("Text graph differs from reference:\n" + string(b))
+	}
+}
+
+// fakeDOTableVertex is meant to be used as a graph.Vertex in tests.
+type fakeDOTableVertex struct {
+	header string
+	body   string
+}
+
+var _ graph.DOTableVertex = (*fakeDOTableVertex)(nil)
+
+// Node implements graph.DOTableVertex.
+func (v fakeDOTableVertex) Node() graph.DOTNode {
+	return graph.DOTNode{
+		Header: v.header,
+		Body:   v.body,
+	}
+}
+
+const referenceTextGraph = `   source              router             target
+  ┌─────────────┐     ┌──────────────┐   ┌─────────────┐
+  │ some_source ├─┬─┬─► some_router  ├───► some_target ├╌┐
+  └─────────────┘ │ ╎ └──────────────┘   └─────────────┘ ╎
+                  │ ╎                                    ╎
+                  │ ╎  target                            ╎
+                  │ ╎ ┌──────────────┐                   ╎
+                  └─┼─► other_target │                   ╎
+                    ╎ └──────────────┘                   ╎
+                    └╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌╌┘
+`
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+// Direction represents the direction in which the edges of a directed graph
+// are followed during a traversal.
+type Direction uint8
+
+// Directions of traversals.
+const (
+	// Follow edges from their tail to their head.
+	Downstream Direction = 1 << iota
+	// Follow edges from their head to their tail.
+	Upstream
+
+	// Follow edges in both directions, but never change direction along a
+	// path.
+	UpAndDownstream = Downstream | Upstream
+)
+
+// Reachable returns all vertices that are reachable from the given vertex by
+// following edges in the given direction, within a maximum distance of
+// maxDepth edges. A negative maxDepth means that the distance is unlimited.
+//
+// The returned vertices include the given vertex, which must belong to the
+// graph.
+func (g *DirectedGraph) Reachable(v Vertex, dir Direction, maxDepth int) IndexedVertices {
+	reached := make(IndexedVertices)
+	reached.Add(v)
+
+	if dir&Downstream != 0 {
+		g.walk(v, g.downEdges, maxDepth, reached)
+	}
+	if dir&Upstream != 0 {
+		g.walk(v, g.upEdges, maxDepth, reached)
+	}
+
+	return reached
+}
+
+// walk performs a breadth-first traversal of the graph from the given vertex,
+// following the given adjacency lists, and adds all visited vertices to
+// visited.
+func (g *DirectedGraph) walk(from Vertex, adj map[interface{}]IndexedVertices, maxDepth int, visited IndexedVertices) {
+	seen := map[interface{}]struct{}{
+		indexKey(from): {},
+	}
+
+	frontier := []interface{}{indexKey(from)}
+
+	for depth := 0; len(frontier) > 0 && (maxDepth < 0 || depth < maxDepth); depth++ {
+		var next []interface{}
+
+		for _, vIdx := range frontier {
+			for adjIdx, adjV := range adj[vIdx] {
+				if _, ok := seen[adjIdx]; ok {
+					continue
+				}
+				seen[adjIdx] = struct{}{}
+
+				visited.Add(adjV)
+				next = append(next, adjIdx)
+			}
+		}
+
+		frontier = next
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReachable(t *testing.T) {
+	g := NewDirectedGraph()
+
+	// 7 ─> 1 ─> 2 ─> 3 ─> 4
+	//      5 ─┘    └─> 6 ─> 8
+
+	for v := 1; v <= 8; v++ {
+		g.Add(v)
+	}
+	g.Connect(7, 1)
+	g.Connect(1, 2)
+	g.Connect(5, 2)
+	g.Connect(2, 3)
+	g.Connect(3, 4)
+	g.Connect(3, 6)
+	g.Connect(6, 8)
+
+	testCases := map[string]struct {
+		from     int
+		dir      Direction
+		maxDepth int
+		expectVs []int
+	}{
+		"Downstream, unlimited depth": {
+			from:     2,
+			dir:      Downstream,
+			maxDepth: -1,
+			expectVs: []int{2, 3, 4, 6, 8},
+		},
+		"Downstream, limited depth": {
+			from:     2,
+			dir:      Downstream,
+			maxDepth: 1,
+			expectVs: []int{2, 3},
+		},
+		"Upstream, unlimited depth": {
+			from:     3,
+			dir:      Upstream,
+			maxDepth: -1,
+			expectVs: []int{1, 2, 3, 5, 7},
+		},
+		"Both directions, limited depth": {
+			from:     3,
+			dir:      UpAndDownstream,
+			maxDepth: 1,
+			expectVs: []int{2, 3, 4, 6},
+		},
+		"Zero depth": {
+			from:     3,
+			dir:      UpAndDownstream,
+			maxDepth: 0,
+			expectVs: []int{3},
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			reached := g.Reachable(tc.from, tc.dir, tc.maxDepth)
+
+			vs := make([]int, 0, len(reached))
+			for _, v := range reached {
+				vs = append(vs, v.(int))
+			}
+			sort.Ints(vs)
+
+			if diff := cmp.Diff(tc.expectVs, vs); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+		})
+	}
+}
+
+func TestSubgraph(t *testing.T) {
+	g := NewDirectedGraph()
+
+	for v := 1; v <= 4; v++ {
+		g.Add(v)
+	}
+	g.ConnectWithKind(1, 2, EdgeKindRoute, "some_label")
+	g.ConnectWithKind(2, 3, EdgeKindDestination, "")
+	g.ConnectWithKind(2, 4, EdgeKindDeadLetter, "")
+	g.ConnectWithKind(3, 4, EdgeKindDeadLetter, "")
+
+	sg := g.Subgraph(
+		func(v Vertex) bool { return v != 3 },
+		func(e *Edge) bool { return e.Kind != EdgeKindDeadLetter },
+	)
+
+	if numVerts := len(sg.Vertices()); numVerts != 3 {
+		t.Errorf("Expected 3 vertices, got %d", numVerts)
+	}
+	if sg.Vertices().Has(3) {
+		t.Error("Expected vertex 3 to be excluded")
+	}
+
+	if numEdges := len(sg.Edges()); numEdges != 1 {
+		t.Fatalf("Expected 1 edge, got %d", numEdges)
+	}
+
+	e := sg.Edge(1, 2, EdgeKindRoute)
+	if e == nil {
+		t.Fatal("Expected vertices 1 and 2 to be connected")
+	}
+	if e.Kind != EdgeKindRoute || e.Label != "some_label" {
+		t.Errorf("Expected kind and label of edge to be preserved, got %q %q", e.Kind, e.Label)
+	}
+
+	if len(sg.DownEdges()[2]) != 0 {
+		t.Error("Expected vertex 2 to have no down edge")
+	}
+}
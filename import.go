@@ -0,0 +1,78 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"til/cli"
+	"til/importer"
+)
+
+type ImportCommand struct {
+	// flags
+	diagnosticsOptions
+}
+
+// Run implements cli.Command.
+func (c *ImportCommand) Run(ctx context.Context, args []string) error {
+	flagSet := cli.FlagSetFromContext(ctx)
+	setUsageFn(flagSet, usageImport)
+
+	c.diagnosticsOptions.addFlags(flagSet)
+
+	pos, flags := splitArgs(countPositional(1, args), args)
+	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
+
+	if flagSet.NArg() > 0 {
+		return fmt.Errorf("unexpected number of positional arguments.\n\n%s", usageImport(flagSet.Name()))
+	}
+
+	ui := cli.UIFromContext(ctx)
+
+	var in io.Reader = ui.StdReader
+
+	if len(pos) == 1 && pos[0] != "-" {
+		f, err := os.Open(pos[0])
+		if err != nil {
+			return fmt.Errorf("opening manifests file: %w", err)
+		}
+		defer f.Close()
+
+		in = f
+	}
+
+	brg, diags := importer.Import(in)
+
+	dw := c.diagnosticWriter(ui, nil)
+	if len(diags) > 0 {
+		_ = dw.WriteDiagnostics(diags)
+	}
+	if diags.HasErrors() {
+		return errors.New("failed to import manifests. See error diagnostics")
+	}
+
+	if _, err := brg.WriteTo(ui.StdWriter); err != nil {
+		return fmt.Errorf("writing imported Bridge description: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,590 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/catalog"
+	"til/config"
+)
+
+// decodeFunc populates the configuration body of a component, for component
+// types which configuration can not be inferred generically from the spec of
+// their main object.
+type decodeFunc func(imp *importer, st *decodeState)
+
+// decoders are the specific decodeFuncs of component types, indexed by
+// "<category>.<type>".
+var decoders = map[string]decodeFunc{
+	"router.content_based":   decodeContentBased,
+	"channel.point_to_point": decodePointToPoint,
+	"channel.pubsub":         decodePubSub,
+	"transformer.function":   decodeFunction,
+	"target.function":        decodeFunction,
+	"target.container":       decodeContainer,
+	"target.event_display":   decodeService,
+	"target.sockeye":         decodeService,
+}
+
+// decodeState tracks the decoding of the configuration of a component.
+type decodeState struct {
+	c    *component
+	body *hclwrite.Body
+
+	// spec of the component's main object
+	spec map[string]interface{}
+
+	// names of the attributes and blocks which have been written
+	set map[string]bool
+	// names of the spec fields which have been mapped to the configuration
+	mapped map[string]bool
+
+	// blocks written after the generically inferred attributes, for
+	// readability
+	deferredBlocks []func()
+}
+
+// decode writes the configuration of the given component to body.
+func (imp *importer) decode(c *component, body *hclwrite.Body) {
+	spec, _, _ := unstructured.NestedMap(c.obj.Object, "spec")
+
+	st := &decodeState{
+		c:      c,
+		body:   body,
+		spec:   spec,
+		set:    make(map[string]bool),
+		mapped: make(map[string]bool),
+	}
+
+	if dec, ok := decoders[c.category.String()+"."+c.typ]; ok {
+		dec(imp, st)
+	}
+
+	cmpType, _ := catalog.Lookup(c.category, c.typ)
+	if cmpType.Body != nil {
+		imp.decodeBody(st, cmpType.Body, spec, body)
+	}
+
+	for _, writeBlock := range st.deferredBlocks {
+		writeBlock()
+	}
+
+	imp.decodeDestinations(st)
+	imp.reportUnmapped(st)
+}
+
+// decodeBody infers the attributes and blocks described by the given schema
+// from the given spec, and writes them to body.
+func (imp *importer) decodeBody(st *decodeState, schema *catalog.Body, spec map[string]interface{}, body *hclwrite.Body) {
+	isRoot := body == st.body
+
+	// only one object reference can be inferred from secrets referenced
+	// anywhere in the spec, otherwise those would be ambiguous
+	var objRefAttrs int
+	for _, a := range schema.Attributes {
+		if n, _ := catalog.NamedType(a.Type); n == catalog.TypeObjectReference {
+			objRefAttrs++
+		}
+	}
+
+	for _, a := range schema.Attributes {
+		if isRoot && st.set[a.Name] {
+			continue
+		}
+
+		field := specField(spec, a.Name)
+		v, found := spec[field]
+
+		var toks hclwrite.Tokens
+
+		switch n, _ := catalog.NamedType(a.Type); {
+		case n == catalog.TypeObjectReference:
+			names := secretNames(v)
+			if !found && objRefAttrs == 1 {
+				names = secretNames(spec)
+			}
+			if len(names) == 1 {
+				toks = funcCallTokens("secret_name", names[0])
+				if !found && isRoot {
+					for f, fv := range spec {
+						if len(secretNames(fv)) > 0 {
+							st.mapped[f] = true
+						}
+					}
+				}
+			}
+
+		case n == catalog.TypeSecretKeySelector:
+			if name, key, ok := secretKeySelector(v); ok {
+				toks = funcCallTokens("secret_ref", name, key)
+			}
+
+		case n == catalog.TypeDestination:
+			if found {
+				if trv, ok := imp.resolve(st.c.obj, nestedRef(asMap(v), "ref")); ok {
+					toks = hclwrite.TokensForTraversal(trv)
+				}
+			}
+
+		case found:
+			if val, err := ctyValue(v, a.Type); err == nil {
+				toks = valueTokens(val)
+			}
+		}
+
+		if toks == nil {
+			if a.Required {
+				imp.reportIncomplete(st, a.Name)
+			}
+			continue
+		}
+
+		body.SetAttributeRaw(a.Name, toks)
+		if isRoot {
+			st.set[a.Name] = true
+			st.mapped[field] = true
+		}
+	}
+
+	for _, blk := range schema.Blocks {
+		if isRoot && st.set[blk.TypeName] {
+			continue
+		}
+
+		field := specField(spec, blk.TypeName)
+		v, found := spec[field].(map[string]interface{})
+
+		// only non-repeatable blocks without labels have an obvious
+		// equivalent in object specs
+		if !found || blk.Nesting.Repeatable() || len(blk.Labels) > 0 {
+			if blk.Required {
+				imp.reportIncomplete(st, blk.TypeName)
+			}
+			continue
+		}
+
+		nested := body.AppendNewBlock(blk.TypeName, nil)
+		imp.decodeBody(st, blk.Body, v, nested.Body())
+
+		if isRoot {
+			st.set[blk.TypeName] = true
+			st.mapped[field] = true
+		}
+	}
+}
+
+// decodeDestinations writes the destination attributes which are common to
+// all component types of the component's category.
+func (imp *importer) decodeDestinations(st *decodeState) {
+	for _, a := range catalog.CategoryAttributes(st.c.category) {
+		if st.set[a.Name] {
+			continue
+		}
+
+		var ref map[string]interface{}
+
+		switch a.Name {
+		case config.AttrTo:
+			ref = nestedRef(st.spec, "sink", "ref")
+			st.mapped["sink"] = true
+
+			// components which don't have a sink send their
+			// responses as replies (e.g. functions)
+			if ref == nil {
+				ref = imp.replies[keyOf(st.c.obj)]
+			}
+		case config.AttrReplyTo:
+			ref = imp.replies[keyOf(st.c.obj)]
+		}
+
+		if ref == nil {
+			if a.Required {
+				imp.reportIncomplete(st, a.Name)
+			}
+			continue
+		}
+
+		trv, ok := imp.resolve(st.c.obj, ref)
+		if !ok {
+			continue
+		}
+
+		st.body.AppendNewline()
+		st.body.SetAttributeTraversal(a.Name, trv)
+		st.set[a.Name] = true
+	}
+}
+
+// reportIncomplete reports that the value of the given required attribute or
+// block could not be inferred.
+//
+// This is an error because the imported Bridge description would fail to
+// validate.
+func (imp *importer) reportIncomplete(st *decodeState, name string) {
+	imp.diags = imp.diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Incomplete component",
+		Detail: fmt.Sprintf("The value of %q could not be inferred from the object %s. The "+
+			"configuration of %s would be invalid without it.", name, keyOf(st.c.obj), st.c),
+	})
+}
+
+// reportUnmapped reports the spec fields which could not be mapped to the
+// configuration of the component.
+func (imp *importer) reportUnmapped(st *decodeState) {
+	var unmapped []string
+	for f := range st.spec {
+		if !st.mapped[f] {
+			unmapped = append(unmapped, f)
+		}
+	}
+	sort.Strings(unmapped)
+
+	for _, f := range unmapped {
+		imp.diags = imp.diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Unmapped field",
+			Detail: fmt.Sprintf("The field \"spec.%s\" of the object %s has no equivalent in the "+
+				"configuration of %s, and was ignored.", f, keyOf(st.c.obj), st.c),
+		})
+	}
+}
+
+// decodeContentBased writes a "route" block for each Trigger of a Broker.
+func decodeContentBased(imp *importer, st *decodeState) {
+	for _, t := range st.c.related {
+		route := st.body.AppendNewBlock("route", nil).Body()
+
+		attrs, _, _ := unstructured.NestedStringMap(t.Object, "spec", "filter", "attributes")
+		if len(attrs) > 0 {
+			vals := make(map[string]cty.Value, len(attrs))
+			for k, v := range attrs {
+				vals[k] = cty.StringVal(v)
+			}
+			route.SetAttributeValue("attributes", cty.MapVal(vals))
+		}
+
+		subscriber := nestedRef(t.Object, "spec", "subscriber", "ref")
+		from := t
+
+		if k, ok := refKey(subscriber); ok {
+			if f, isFilter := imp.routeFilters[k]; isFilter {
+				expr, _, _ := unstructured.NestedString(f.Object, "spec", "expression")
+				route.SetAttributeValue("condition", cty.StringVal(expr))
+
+				subscriber = nestedRef(f.Object, "spec", "sink", "ref")
+				from = f
+			}
+		}
+
+		if trv, ok := imp.resolve(from, subscriber); ok {
+			route.SetAttributeTraversal(config.AttrTo, trv)
+		}
+	}
+
+	st.set["route"] = true
+}
+
+// decodePointToPoint writes the destination of a point-to-point channel.
+func decodePointToPoint(imp *importer, st *decodeState) {
+	sub := st.c.related[0]
+
+	if trv, ok := imp.resolve(sub, nestedRef(sub.Object, "spec", "subscriber", "ref")); ok {
+		st.body.SetAttributeTraversal(config.AttrTo, trv)
+		st.set[config.AttrTo] = true
+	}
+}
+
+// decodePubSub writes the subscribers of a publish-subscribe channel.
+func decodePubSub(imp *importer, st *decodeState) {
+	const attrSubscribers = "subscribers"
+
+	var trvs []hcl.Traversal
+	for _, sub := range st.c.related {
+		if trv, ok := imp.resolve(sub, nestedRef(sub.Object, "spec", "subscriber", "ref")); ok {
+			trvs = append(trvs, trv)
+		}
+	}
+
+	if len(trvs) == 0 {
+		return
+	}
+
+	toks := hclwrite.Tokens{{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")}}
+	for i, trv := range trvs {
+		if i > 0 {
+			toks = append(toks, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		}
+		toks = append(toks, hclwrite.TokensForTraversal(trv)...)
+	}
+	toks = append(toks, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+
+	st.body.SetAttributeRaw(attrSubscribers, toks)
+	st.set[attrSubscribers] = true
+}
+
+// decodeFunction writes the configuration of a function, which was generated
+// either as an InfraTarget or as a Function.
+func decodeFunction(_ *importer, st *decodeState) {
+	if st.c.obj.GetKind() == "InfraTarget" {
+		// the "js-otto" runtime is the only one implemented with
+		// InfraTargets
+		st.body.SetAttributeValue("runtime", cty.StringVal("js-otto"))
+		st.set["runtime"] = true
+
+		if code, found, _ := unstructured.NestedString(st.spec, "script", "code"); found {
+			st.body.SetAttributeRaw("code", valueTokens(cty.StringVal(code)))
+			st.set["code"] = true
+			st.mapped["script"] = true
+		}
+		return
+	}
+
+	ceCtx, found, _ := unstructured.NestedStringMap(st.spec, "ceOverrides", "extensions")
+	if !found {
+		return
+	}
+
+	keys := make([]string, 0, len(ceCtx))
+	for k := range ceCtx {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	st.deferredBlocks = append(st.deferredBlocks, func() {
+		blk := st.body.AppendNewBlock("ce_context", nil).Body()
+		for _, k := range keys {
+			blk.SetAttributeValue(k, cty.StringVal(ceCtx[k]))
+		}
+	})
+
+	st.set["ce_context"] = true
+	st.mapped["ceOverrides"] = true
+}
+
+// decodeContainer writes the configuration of a container target.
+func decodeContainer(_ *importer, st *decodeState) {
+	st.body.SetAttributeValue("image", cty.StringVal(serviceImage(st.c.obj)))
+	st.set["image"] = true
+
+	if isPublicService(st.c.obj) {
+		st.body.SetAttributeValue("public", cty.True)
+	}
+	st.set["public"] = true
+
+	containers, _, _ := unstructured.NestedSlice(st.spec, "template", "spec", "containers")
+	if len(containers) > 0 {
+		env, _, _ := unstructured.NestedSlice(asMap(containers[0]), "env")
+
+		for _, e := range env {
+			e := asMap(e)
+			name, _ := e["name"].(string)
+
+			var toks hclwrite.Tokens
+			if v, ok := e["value"].(string); ok {
+				toks = hclwrite.TokensForValue(cty.StringVal(v))
+			} else if sname, key, ok := secretKeySelector(e["valueFrom"]); ok {
+				toks = funcCallTokens("secret_ref", sname, key)
+			} else {
+				continue
+			}
+
+			blk := st.body.AppendNewBlock("env_var", []string{name})
+			blk.Body().SetAttributeRaw("value", toks)
+		}
+	}
+
+	st.set["env_var"] = true
+	st.set["env_vars"] = true
+
+	decodeService(nil, st)
+}
+
+// decodeService marks the spec of a Knative Service as mapped. The spec of
+// targets which run a predefined image doesn't carry any configuration.
+func decodeService(_ *importer, st *decodeState) {
+	st.mapped["template"] = true
+}
+
+// isPublicService returns whether the given Knative Service is exposed
+// outside of the cluster.
+func isPublicService(o *unstructured.Unstructured) bool {
+	return o.GetLabels()["networking.knative.dev/visibility"] != "cluster-local"
+}
+
+// secretNames returns the sorted names of all Secrets referenced inside the
+// given value.
+func secretNames(v interface{}) []string {
+	names := make(map[string]struct{})
+	collectSecretNames(v, names)
+
+	sorted := make([]string, 0, len(names))
+	for n := range names {
+		sorted = append(sorted, n)
+	}
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// collectSecretNames walks the given value recursively and collects the names
+// of referenced Secrets.
+func collectSecretNames(v interface{}, names map[string]struct{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if name, _, ok := secretKeySelector(v); ok {
+			names[name] = struct{}{}
+			return
+		}
+		for _, e := range v {
+			collectSecretNames(e, names)
+		}
+	case []interface{}:
+		for _, e := range v {
+			collectSecretNames(e, names)
+		}
+	}
+}
+
+// secretKeySelector returns the Secret name and key referenced by the given
+// value, if it contains a reference to a Secret key in one of the formats used
+// by TriggerMesh and Kubernetes APIs.
+func secretKeySelector(v interface{}) (name, key string, ok bool) {
+	m := asMap(v)
+
+	for _, field := range []string{"secretKeyRef", "valueFromSecret"} {
+		sel := asMap(m[field])
+		name, _ = sel["name"].(string)
+		key, _ = sel["key"].(string)
+		if name != "" {
+			return name, key, true
+		}
+	}
+
+	return "", "", false
+}
+
+// funcCallTokens returns the tokens of a call to the given function with the
+// given string arguments.
+func funcCallTokens(fn string, args ...string) hclwrite.Tokens {
+	toks := hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fn)},
+		{Type: hclsyntax.TokenOParen, Bytes: []byte("(")},
+	}
+
+	for i, a := range args {
+		if i > 0 {
+			toks = append(toks, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		}
+		toks = append(toks, hclwrite.TokensForValue(cty.StringVal(a))...)
+	}
+
+	return append(toks, &hclwrite.Token{Type: hclsyntax.TokenCParen, Bytes: []byte(")")})
+}
+
+// ctyValue converts the given JSON-compatible value to a cty.Value of the
+// given type.
+func ctyValue(v interface{}, t cty.Type) (cty.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	implied, err := ctyjson.ImpliedType(b)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	val, err := ctyjson.Unmarshal(b, implied)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	if t == cty.DynamicPseudoType {
+		return val, nil
+	}
+	return convert.Convert(val, t)
+}
+
+// valueTokens returns the tokens of the given value. Multi-line strings are
+// written as heredocs for readability.
+func valueTokens(val cty.Value) hclwrite.Tokens {
+	const heredocDelim = "EOF"
+
+	if val.Type() != cty.String || !val.IsKnown() || val.IsNull() {
+		return hclwrite.TokensForValue(val)
+	}
+
+	s := val.AsString()
+
+	canHeredoc := strings.HasSuffix(s, "\n") &&
+		!strings.Contains(s, "${") && !strings.Contains(s, "%{") &&
+		!strings.Contains("\n"+s, "\n"+heredocDelim+"\n")
+
+	if !canHeredoc {
+		return hclwrite.TokensForValue(val)
+	}
+
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenOHeredoc, Bytes: []byte("<<" + heredocDelim + "\n")},
+		{Type: hclsyntax.TokenStringLit, Bytes: []byte(s)},
+		{Type: hclsyntax.TokenCHeredoc, Bytes: []byte(heredocDelim)},
+	}
+}
+
+// specField returns the name of the field of the given object spec which
+// corresponds to the given snake_case HCL name. Kubernetes API fields use the
+// lowerCamelCase naming convention, with initialisms that are sometimes fully
+// capitalized (e.g. "appID").
+func specField(spec map[string]interface{}, name string) string {
+	field := lowerCamelCase(name)
+
+	if _, found := spec[field]; found {
+		return field
+	}
+	for f := range spec {
+		if strings.EqualFold(f, field) {
+			return f
+		}
+	}
+
+	return field
+}
+
+// lowerCamelCase converts the given snake_case HCL name to the lowerCamelCase
+// naming convention of Kubernetes API fields.
+func lowerCamelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
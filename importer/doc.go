@@ -0,0 +1,27 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package importer reconstructs Bridge descriptions from existing Kubernetes
+// manifests.
+//
+// Kubernetes objects are mapped back to the Bridge components they would have
+// been generated from, references between components are rebuilt from the
+// destinations of those objects (e.g. "spec.sink.ref"), and the result is
+// written as HCL.
+//
+// The mapping is best effort. Objects and settings which can not be mapped are
+// reported as diagnostics instead of being silently discarded.
+package importer
@@ -0,0 +1,582 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"til/catalog"
+	"til/config"
+	"til/translation"
+)
+
+// Import reads a stream of Kubernetes manifests in YAML or JSON format from r,
+// and reconstructs the Bridge description these manifests correspond to.
+//
+// Objects which can not be mapped to a Bridge component, as well as settings
+// which can not be mapped to a component's configuration, are reported as
+// warning diagnostics. Error diagnostics are returned if the manifests can not
+// be read, or if a required setting of a component can not be inferred, since
+// the resulting Bridge description would be invalid.
+func Import(r io.Reader) (*hclwrite.File, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	m, err := readManifests(r)
+	if err != nil {
+		return nil, diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid manifests",
+			Detail:   err.Error(),
+		})
+	}
+
+	imp := newImporter(m.objects)
+	imp.mapComponents()
+
+	f := hclwrite.NewEmptyFile()
+	imp.write(f.Body(), m.bridgeName)
+
+	return f, imp.diags
+}
+
+// objKey uniquely identifies a Kubernetes object within a namespace.
+type objKey struct {
+	group string
+	kind  string
+	name  string
+}
+
+// String implements fmt.Stringer.
+func (k objKey) String() string {
+	return fmt.Sprintf("%s %q", k.kind, k.name)
+}
+
+// keyOf returns the objKey of the given object.
+func keyOf(u *unstructured.Unstructured) objKey {
+	return objKey{
+		group: u.GroupVersionKind().Group,
+		kind:  u.GetKind(),
+		name:  u.GetName(),
+	}
+}
+
+// refKey returns the objKey of the object referenced by the given object
+// reference (e.g. "spec.sink.ref"), if it is valid.
+func refKey(ref map[string]interface{}) (objKey, bool) {
+	apiVersion, _ := ref["apiVersion"].(string)
+	kind, _ := ref["kind"].(string)
+	name, _ := ref["name"].(string)
+
+	if kind == "" || name == "" {
+		return objKey{}, false
+	}
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return objKey{}, false
+	}
+
+	return objKey{
+		group: gv.Group,
+		kind:  kind,
+		name:  name,
+	}, true
+}
+
+// nestedRef returns the object reference located at the given path inside
+// the given object.
+func nestedRef(obj map[string]interface{}, fields ...string) map[string]interface{} {
+	ref, _, _ := unstructured.NestedMap(obj, fields...)
+	return ref
+}
+
+// component is a Bridge component reconstructed from Kubernetes objects.
+type component struct {
+	category   config.ComponentCategory
+	typ        string
+	identifier string
+
+	// Main object the component was reconstructed from.
+	obj *unstructured.Unstructured
+	// Other objects which are part of the component, such as the Triggers
+	// of a Broker or the Subscriptions of a Channel.
+	related []*unstructured.Unstructured
+}
+
+// String implements fmt.Stringer.
+func (c *component) String() string {
+	return fmt.Sprintf("%s %q %q", c.category, c.typ, c.identifier)
+}
+
+// traversal returns the expression which references the component.
+func (c *component) traversal() hcl.Traversal {
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: c.category.String()},
+		hcl.TraverseAttr{Name: c.identifier},
+	}
+}
+
+// importer maps Kubernetes objects to Bridge components.
+type importer struct {
+	objects map[objKey]*unstructured.Unstructured
+	// sorted keys of objects, for a deterministic processing
+	keys []objKey
+
+	// objects which have been mapped to a component
+	consumed map[objKey]bool
+
+	components []*component
+
+	// components indexed by the key of the object which receives their
+	// events
+	addrs map[objKey]*component
+	// objects which forward events to another object, such as Channels
+	// interposed by delivery settings
+	aliases map[objKey]objKey
+	// destinations of the replies of objects, indexed by object key
+	replies map[objKey]map[string]interface{}
+	// Filters interposed between Triggers and their subscriber, indexed
+	// by key
+	routeFilters map[objKey]*unstructured.Unstructured
+
+	diags hcl.Diagnostics
+}
+
+// newImporter returns an importer for the given objects.
+func newImporter(objs []*unstructured.Unstructured) *importer {
+	imp := &importer{
+		objects:      make(map[objKey]*unstructured.Unstructured, len(objs)),
+		consumed:     make(map[objKey]bool),
+		addrs:        make(map[objKey]*component),
+		aliases:      make(map[objKey]objKey),
+		replies:      make(map[objKey]map[string]interface{}),
+		routeFilters: make(map[objKey]*unstructured.Unstructured),
+	}
+
+	for _, o := range objs {
+		k := keyOf(o)
+		if _, dup := imp.objects[k]; dup {
+			imp.diags = imp.diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Duplicate object",
+				Detail:   fmt.Sprintf("The object %s is defined more than once. Only its first definition is imported.", k),
+			})
+			continue
+		}
+
+		imp.objects[k] = o
+		imp.keys = append(imp.keys, k)
+	}
+
+	sort.Slice(imp.keys, func(i, j int) bool {
+		ki, kj := imp.keys[i], imp.keys[j]
+		if ki.kind != kj.kind {
+			return ki.kind < kj.kind
+		}
+		return ki.name < kj.name
+	})
+
+	return imp
+}
+
+// objectsOfKind returns the unconsumed objects of the given kind.
+func (imp *importer) objectsOfKind(group, kind string) []*unstructured.Unstructured {
+	var objs []*unstructured.Unstructured
+	for _, k := range imp.keys {
+		if k.group == group && k.kind == kind && !imp.consumed[k] {
+			objs = append(objs, imp.objects[k])
+		}
+	}
+	return objs
+}
+
+// Group of the Knative APIs and TriggerMesh APIs involved in the mapping of
+// components.
+const (
+	groupEventing   = "eventing.knative.dev"
+	groupMessaging  = "messaging.knative.dev"
+	groupServing    = "serving.knative.dev"
+	groupFlow       = "flow.triggermesh.io"
+	groupExtensions = "extensions.triggermesh.io"
+)
+
+// mapComponents maps all objects to Bridge components.
+//
+// Knative eventing primitives are mapped first, because their interpretation
+// depends on the objects they are attached to.
+func (imp *importer) mapComponents() {
+	imp.mapBrokers()
+	imp.mapChannels()
+
+	idx := kindIndex()
+
+	for _, k := range imp.keys {
+		if imp.consumed[k] {
+			continue
+		}
+
+		o := imp.objects[k]
+
+		cat, typ, ok := imp.componentType(o, idx)
+		if !ok {
+			imp.diags = imp.diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Unsupported object",
+				Detail: fmt.Sprintf("The object %s (%s) does not correspond to any known type of "+
+					"Bridge component, and was not imported.", k, o.GetAPIVersion()),
+			})
+			continue
+		}
+
+		imp.addComponent(cat, typ, o)
+	}
+}
+
+// addComponent records a component reconstructed from the given object.
+func (imp *importer) addComponent(cat config.ComponentCategory, typ string,
+	o *unstructured.Unstructured, related ...*unstructured.Unstructured) *component {
+
+	c := &component{
+		category:   cat,
+		typ:        typ,
+		identifier: identifier(o.GetName()),
+		obj:        o,
+		related:    related,
+	}
+
+	imp.components = append(imp.components, c)
+
+	k := keyOf(o)
+	imp.consumed[k] = true
+	imp.addrs[k] = c
+
+	for _, r := range related {
+		imp.consumed[keyOf(r)] = true
+	}
+
+	return c
+}
+
+// mapBrokers maps Brokers and their Triggers to content-based routers.
+func (imp *importer) mapBrokers() {
+	for _, b := range imp.objectsOfKind(groupEventing, "Broker") {
+		var triggers []*unstructured.Unstructured
+
+		for _, t := range imp.objectsOfKind(groupEventing, "Trigger") {
+			if broker, _, _ := unstructured.NestedString(t.Object, "spec", "broker"); broker != b.GetName() {
+				continue
+			}
+			triggers = append(triggers, t)
+
+			// A Filter named after the Trigger is interpolated
+			// between the Trigger and its subscriber when a route
+			// has a condition.
+			sk, ok := refKey(nestedRef(t.Object, "spec", "subscriber", "ref"))
+			if !ok || sk.group != groupFlow || sk.kind != "Filter" || sk.name != t.GetName() {
+				continue
+			}
+			if f, exists := imp.objects[sk]; exists {
+				imp.routeFilters[sk] = f
+				imp.consumed[sk] = true
+			}
+		}
+
+		imp.addComponent(config.CategoryRouters, "content_based", b, triggers...)
+	}
+}
+
+// mapChannels maps Channels and their Subscriptions to either channel
+// components, or to the delivery settings of the components they are attached
+// to.
+func (imp *importer) mapChannels() {
+	for _, ch := range imp.objectsOfKind(groupMessaging, "Channel") {
+		chKey := keyOf(ch)
+
+		var subs []*unstructured.Unstructured
+		for _, s := range imp.objectsOfKind(groupMessaging, "Subscription") {
+			if name, _, _ := unstructured.NestedString(s.Object, "spec", "channel", "name"); name == ch.GetName() {
+				subs = append(subs, s)
+			}
+		}
+
+		if len(subs) == 1 && subs[0].GetName() == ch.GetName() {
+			sub := subs[0]
+			subscriber, ok := refKey(nestedRef(sub.Object, "spec", "subscriber", "ref"))
+
+			switch {
+			// Channel which routes the replies of its subscriber
+			// (e.g. target with a "reply_to" attribute).
+			case ok && subscriber.name == ch.GetName() && nestedRef(sub.Object, "spec", "reply", "ref") != nil:
+				imp.aliases[chKey] = subscriber
+				imp.replies[subscriber] = nestedRef(sub.Object, "spec", "reply", "ref")
+				imp.consumed[chKey] = true
+				imp.consumed[keyOf(sub)] = true
+				continue
+
+			// Channel interposed between a component and its
+			// destination to apply global delivery settings.
+			case ok && imp.isInterposedChannel(ch, subscriber):
+				imp.aliases[chKey] = subscriber
+				imp.consumed[chKey] = true
+				imp.consumed[keyOf(sub)] = true
+				continue
+			}
+
+			imp.addComponent(config.CategoryChannels, "point_to_point", ch, sub)
+			continue
+		}
+
+		imp.addComponent(config.CategoryChannels, "pubsub", ch, subs...)
+	}
+}
+
+// isInterposedChannel returns whether the given Channel was interposed by
+// delivery settings between an object and the given subscriber. Such Channels
+// are named "<object>-<subscriber>".
+func (imp *importer) isInterposedChannel(ch *unstructured.Unstructured, subscriber objKey) bool {
+	chKey := keyOf(ch)
+
+	for _, k := range imp.keys {
+		sink, ok := refKey(nestedRef(imp.objects[k].Object, "spec", "sink", "ref"))
+		if ok && sink == chKey && ch.GetName() == k.name+"-"+subscriber.name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// groupKind identifies a kind of Kubernetes object.
+type groupKind struct {
+	group string
+	kind  string
+}
+
+// kindIndex returns all component types indexed by the kind of the main
+// object they generate.
+func kindIndex() map[groupKind][]*catalog.ComponentType {
+	idx := make(map[groupKind][]*catalog.ComponentType)
+
+	for _, cat := range catalog.Categories() {
+		for _, typ := range catalog.Types(cat) {
+			cmpType, _ := catalog.Lookup(cat, typ)
+			if len(cmpType.Kinds) == 0 {
+				continue
+			}
+
+			gk := groupKind{
+				group: cmpType.Kinds[0].Group,
+				kind:  cmpType.Kinds[0].Kind,
+			}
+			idx[gk] = append(idx[gk], cmpType)
+		}
+	}
+
+	return idx
+}
+
+// componentType returns the category and type of the component the given
+// object corresponds to.
+func (imp *importer) componentType(o *unstructured.Unstructured,
+	idx map[groupKind][]*catalog.ComponentType) (config.ComponentCategory, string, bool) {
+
+	k := keyOf(o)
+
+	switch {
+	// several target types generate Knative Services
+	case k.group == groupServing && k.kind == "Service":
+		return config.CategoryTargets, serviceComponentType(o), true
+
+	// functions which send their responses to a sink are transformers
+	case k.group == groupExtensions && k.kind == "Function":
+		if nestedRef(o.Object, "spec", "sink", "ref") != nil {
+			return config.CategoryTransformers, "function", true
+		}
+		return config.CategoryTargets, "function", true
+	}
+
+	cmpTypes := idx[groupKind{group: k.group, kind: k.kind}]
+	if len(cmpTypes) != 1 {
+		return 0, "", false
+	}
+
+	return cmpTypes[0].Category, cmpTypes[0].Name, true
+}
+
+// Target types which generate a Knative Service that runs a fixed image.
+var serviceTargetTypes = []string{
+	"event_display",
+	"sockeye",
+}
+
+// serviceComponentType returns the type of target the given Knative Service
+// corresponds to.
+//
+// A Service is attributed to one of the serviceTargetTypes only if it runs the
+// exact image (including its digest) of that type, with the same visibility
+// and without environment variables. Any other Service, including customized
+// ones, is imported as a container target so that no setting is lost.
+func serviceComponentType(o *unstructured.Unstructured) string {
+	for _, typ := range serviceTargetTypes {
+		ref, ok := referenceService(typ)
+		if !ok {
+			continue
+		}
+
+		if serviceImage(o) == serviceImage(ref) &&
+			isPublicService(o) == isPublicService(ref) &&
+			!hasServiceEnv(o) {
+
+			return typ
+		}
+	}
+
+	return "container"
+}
+
+// referenceService returns the Knative Service generated by the given target
+// type, which is used as a reference for identifying Services generated by
+// that type.
+func referenceService(typ string) (*unstructured.Unstructured, bool) {
+	cmpType, ok := catalog.Lookup(config.CategoryTargets, typ)
+	if !ok {
+		return nil, false
+	}
+
+	const id = "reference"
+	null := cty.NullVal(cty.DynamicPseudoType)
+
+	var manifests []interface{}
+
+	switch impl := cmpType.Impl.(type) {
+	case translation.TranslatableV2:
+		manifests, _ = impl.ManifestsV2(&translation.Context{
+			Identifier:       id,
+			Config:           null,
+			EventDestination: null,
+		})
+	case translation.Translatable:
+		manifests = impl.Manifests(id, null, null, nil)
+	}
+
+	for _, m := range manifests {
+		if u, ok := m.(*unstructured.Unstructured); ok && u.GetKind() == "Service" {
+			return u, true
+		}
+	}
+
+	return nil, false
+}
+
+// hasServiceEnv returns whether the first container of the given Knative
+// Service sets environment variables.
+func hasServiceEnv(o *unstructured.Unstructured) bool {
+	containers, _, _ := unstructured.NestedSlice(o.Object, "spec", "template", "spec", "containers")
+	if len(containers) == 0 {
+		return false
+	}
+
+	env, _, _ := unstructured.NestedSlice(asMap(containers[0]), "env")
+	return len(env) > 0
+}
+
+// serviceImage returns the image of the first container of the given Knative
+// Service.
+func serviceImage(o *unstructured.Unstructured) string {
+	containers, _, _ := unstructured.NestedSlice(o.Object, "spec", "template", "spec", "containers")
+	if len(containers) == 0 {
+		return ""
+	}
+
+	img, _, _ := unstructured.NestedString(asMap(containers[0]), "image")
+	return img
+}
+
+// resolve returns the expression which references the component that
+// receives the events sent to the given object reference.
+func (imp *importer) resolve(from *unstructured.Unstructured, ref map[string]interface{}) (hcl.Traversal, bool) {
+	k, ok := refKey(ref)
+	if !ok {
+		return nil, false
+	}
+
+	// guard against cycles between aliases
+	for i := 0; i <= len(imp.aliases); i++ {
+		alias, isAlias := imp.aliases[k]
+		if !isAlias {
+			break
+		}
+		k = alias
+	}
+
+	c, ok := imp.addrs[k]
+	if !ok {
+		imp.diags = imp.diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Unresolved reference",
+			Detail: fmt.Sprintf("The object %s references the object %s, which is not part of the "+
+				"imported manifests or could not be imported. The reference must be set manually.",
+				keyOf(from), k),
+		})
+		return nil, false
+	}
+
+	return c.traversal(), true
+}
+
+// write writes the imported Bridge description to the given body.
+func (imp *importer) write(body *hclwrite.Body, bridgeName string) {
+	if bridgeName != "" {
+		body.AppendNewBlock(config.BlkBridge, []string{identifier(bridgeName)})
+	}
+
+	catOrder := make(map[config.ComponentCategory]int)
+	for i, cat := range catalog.Categories() {
+		catOrder[cat] = i
+	}
+
+	sort.SliceStable(imp.components, func(i, j int) bool {
+		ci, cj := imp.components[i], imp.components[j]
+		if ci.category != cj.category {
+			return catOrder[ci.category] < catOrder[cj.category]
+		}
+		return ci.identifier < cj.identifier
+	})
+
+	for i, c := range imp.components {
+		if i > 0 || bridgeName != "" {
+			body.AppendNewline()
+		}
+
+		blk := body.AppendNewBlock(c.category.String(), []string{c.typ, c.identifier})
+		imp.decode(c, blk.Body())
+	}
+}
+
+// identifier converts the given Kubernetes object name to a HCL identifier.
+func identifier(name string) string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/hcl/v2"
+
+	. "til/importer"
+)
+
+func TestImport(t *testing.T) {
+	const manifests = `
+apiVersion: sources.triggermesh.io/v1alpha1
+kind: AWSSQSSource
+metadata:
+  name: my-queue
+spec:
+  arn: arn:aws:sqs:us-east-2:123456789012:my-queue
+  credentials:
+    accessKeyID:
+      valueFromSecret:
+        name: aws-creds
+        key: access_key_id
+    secretAccessKey:
+      valueFromSecret:
+        name: aws-creds
+        key: secret_access_key
+  sink:
+    ref:
+      apiVersion: eventing.knative.dev/v1
+      kind: Broker
+      name: my-router
+---
+apiVersion: eventing.knative.dev/v1
+kind: Broker
+metadata:
+  name: my-router
+---
+apiVersion: eventing.knative.dev/v1
+kind: Trigger
+metadata:
+  name: my-router-display
+spec:
+  broker: my-router
+  filter:
+    attributes:
+      type: com.amazon.sqs.message
+  subscriber:
+    ref:
+      apiVersion: serving.knative.dev/v1
+      kind: Service
+      name: display
+---
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: display
+  labels:
+    networking.knative.dev/visibility: cluster-local
+spec:
+  template:
+    spec:
+      containers:
+      - image: gcr.io/knative-releases/knative.dev/eventing/cmd/event_display@sha256:d53673872272dbe8cb044a567e43c4b5e9dfb2bb2bc5b61cf72ff4911ed93979
+---
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: foo
+`
+
+	const expectBridge = `source "aws_sqs" "my_queue" {
+  arn         = "arn:aws:sqs:us-east-2:123456789012:my-queue"
+  credentials = secret_name("aws-creds")
+
+  to = router.my_router
+}
+
+router "content_based" "my_router" {
+  route {
+    attributes = {
+      type = "com.amazon.sqs.message"
+    }
+    to = target.display
+  }
+}
+
+target "event_display" "display" {
+}
+`
+
+	f, diags := Import(strings.NewReader(manifests))
+
+	expectDiags := []diagSummary{
+		{hcl.DiagWarning, "Unsupported object"},
+	}
+	if diff := cmp.Diff(expectDiags, summaries(diags)); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+
+	if diff := cmp.Diff(expectBridge, string(f.Bytes())); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+func TestImportServiceTargetTypes(t *testing.T) {
+	const sockeyeImage = "docker.io/n3wscott/sockeye:v0.7.0" +
+		"@sha256:e603d8494eeacce966e57f8f508e4c4f6bebc71d095e3f5a0a1abaf42c5f0e48"
+
+	testCases := map[string]struct {
+		manifest     string
+		expectBridge string
+	}{
+		"Service generated by a sockeye target": {
+			manifest: `
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: display
+spec:
+  template:
+    spec:
+      containers:
+      - image: ` + sockeyeImage + `
+`,
+			expectBridge: `target "sockeye" "display" {
+}
+`,
+		},
+		"container target running the sockeye image": {
+			manifest: `
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: display
+spec:
+  template:
+    spec:
+      containers:
+      - image: ` + sockeyeImage + `
+        env:
+        - name: FOO
+          value: bar
+`,
+			expectBridge: `target "container" "display" {
+  image  = "` + sockeyeImage + `"
+  public = true
+  env_var "FOO" {
+    value = "bar"
+  }
+}
+`,
+		},
+		"container target running another tag of the sockeye image": {
+			manifest: `
+apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: display
+spec:
+  template:
+    spec:
+      containers:
+      - image: docker.io/n3wscott/sockeye:latest
+`,
+			expectBridge: `target "container" "display" {
+  image  = "docker.io/n3wscott/sockeye:latest"
+  public = true
+}
+`,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			f, diags := Import(strings.NewReader(tc.manifest))
+			if len(diags) > 0 {
+				t.Fatal("Unexpected diagnostics:", diags)
+			}
+
+			if diff := cmp.Diff(tc.expectBridge, string(f.Bytes())); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+		})
+	}
+}
+
+func TestImportIncompleteComponent(t *testing.T) {
+	// The ce_context block of "js-otto" function transformers isn't
+	// represented in the generated InfraTarget.
+	const manifests = `
+apiVersion: targets.triggermesh.io/v1alpha1
+kind: InfraTarget
+metadata:
+  name: fn
+spec:
+  script:
+    code: function handle(input) { return input; }
+`
+
+	_, diags := Import(strings.NewReader(manifests))
+
+	var found bool
+	for _, d := range diags {
+		if d.Severity == hcl.DiagError && d.Summary == "Incomplete component" &&
+			strings.Contains(d.Detail, `"ce_context"`) {
+
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected an error diagnostic about the ce_context block, got:", diags)
+	}
+}
+
+func TestImportInvalidInput(t *testing.T) {
+	_, diags := Import(strings.NewReader("kind: [unterminated"))
+	if !diags.HasErrors() {
+		t.Error("Expected error diagnostics for invalid input")
+	}
+}
+
+type diagSummary struct {
+	Severity hcl.DiagnosticSeverity
+	Summary  string
+}
+
+func summaries(diags hcl.Diagnostics) []diagSummary {
+	var s []diagSummary
+	for _, d := range diags {
+		s = append(s, diagSummary{d.Severity, d.Summary})
+	}
+	return s
+}
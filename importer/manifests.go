@@ -0,0 +1,116 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// API of the Bridge objects produced by "til generate --bridge".
+const (
+	bridgeAPIVersion = "flow.triggermesh.io/v1alpha1"
+	bridgeKind       = "Bridge"
+)
+
+// manifests is a collection of Kubernetes objects read from a stream of
+// manifests.
+type manifests struct {
+	objects []*unstructured.Unstructured
+
+	// Name of the Bridge object the objects were read from, if any.
+	bridgeName string
+}
+
+// readManifests reads a stream of Kubernetes manifests in YAML or JSON format.
+//
+// Objects of kind "List" and Bridge objects are expanded into the objects they
+// contain.
+func readManifests(r io.Reader) (*manifests, error) {
+	const bufferSize = 4096
+	dec := yaml.NewYAMLOrJSONDecoder(r, bufferSize)
+
+	m := &manifests{}
+
+	for {
+		var obj map[string]interface{}
+		err := dec.Decode(&obj)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding manifest: %w", err)
+		}
+
+		// empty YAML document
+		if len(obj) == 0 {
+			continue
+		}
+
+		if err := m.add(&unstructured.Unstructured{Object: obj}); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// add adds the given object to the collection, expanding it if it contains
+// other objects.
+func (m *manifests) add(u *unstructured.Unstructured) error {
+	switch {
+	case u.IsList():
+		return u.EachListItem(func(o runtime.Object) error {
+			return m.add(o.(*unstructured.Unstructured))
+		})
+
+	case u.GetAPIVersion() == bridgeAPIVersion && u.GetKind() == bridgeKind:
+		m.bridgeName = u.GetName()
+
+		cmps, _, err := unstructured.NestedSlice(u.Object, "spec", "components")
+		if err != nil {
+			return fmt.Errorf("reading components of Bridge %q: %w", u.GetName(), err)
+		}
+
+		for _, c := range cmps {
+			obj, _, err := unstructured.NestedMap(asMap(c), "object")
+			if err != nil || obj == nil {
+				return fmt.Errorf("invalid component in Bridge %q", u.GetName())
+			}
+
+			if err := m.add(&unstructured.Unstructured{Object: obj}); err != nil {
+				return err
+			}
+		}
+
+	default:
+		m.objects = append(m.objects, u)
+	}
+
+	return nil
+}
+
+// asMap returns the given value as a map, or nil if it isn't a map.
+func asMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
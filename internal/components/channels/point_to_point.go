@@ -19,6 +19,7 @@ package channels
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -29,9 +30,10 @@ import (
 type PointToPoint struct{}
 
 var (
-	_ translation.Decodable    = (*PointToPoint)(nil)
-	_ translation.Translatable = (*PointToPoint)(nil)
-	_ translation.Addressable  = (*PointToPoint)(nil)
+	_ translation.Decodable      = (*PointToPoint)(nil)
+	_ translation.Translatable   = (*PointToPoint)(nil)
+	_ translation.Introspectable = (*PointToPoint)(nil)
+	_ translation.Addressable    = (*PointToPoint)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -96,3 +98,11 @@ func (*PointToPoint) Manifests(id string, config, _ cty.Value, glb globals.Acces
 func (*PointToPoint) Address(id string, _, _ cty.Value) cty.Value {
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", k8s.RFC1123Name(id))
 }
+
+// Kinds implements translation.Introspectable.
+func (*PointToPoint) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -30,9 +31,10 @@ import (
 type PubSub struct{}
 
 var (
-	_ translation.Decodable    = (*PubSub)(nil)
-	_ translation.Translatable = (*PubSub)(nil)
-	_ translation.Addressable  = (*PubSub)(nil)
+	_ translation.Decodable      = (*PubSub)(nil)
+	_ translation.Translatable   = (*PubSub)(nil)
+	_ translation.Introspectable = (*PubSub)(nil)
+	_ translation.Addressable    = (*PubSub)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -71,3 +73,11 @@ func (*PubSub) Manifests(id string, config, _ cty.Value, glb globals.Accessor) [
 func (*PubSub) Address(id string, _, _ cty.Value) cty.Value {
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", k8s.RFC1123Name(id))
 }
+
+// Kinds implements translation.Introspectable.
+func (*PubSub) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -31,9 +32,10 @@ import (
 type ContentBased struct{}
 
 var (
-	_ translation.Decodable    = (*ContentBased)(nil)
-	_ translation.Translatable = (*ContentBased)(nil)
-	_ translation.Addressable  = (*ContentBased)(nil)
+	_ translation.Decodable      = (*ContentBased)(nil)
+	_ translation.Translatable   = (*ContentBased)(nil)
+	_ translation.Introspectable = (*ContentBased)(nil)
+	_ translation.Addressable    = (*ContentBased)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -178,3 +180,12 @@ func attributesFromRoute(route cty.Value) map[string]interface{} {
 
 	return filterAttr
 }
+
+// Kinds implements translation.Introspectable.
+func (*ContentBased) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIEventing, "Broker"),
+		k8s.GroupVersionKind(k8s.APIEventing, "Trigger"),
+		k8s.GroupVersionKind(k8s.APIFlow, "Filter"),
+	}
+}
@@ -19,6 +19,7 @@ package routers
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -28,9 +29,10 @@ import (
 type DataExprFilter struct{}
 
 var (
-	_ translation.Decodable    = (*DataExprFilter)(nil)
-	_ translation.Translatable = (*DataExprFilter)(nil)
-	_ translation.Addressable  = (*DataExprFilter)(nil)
+	_ translation.Decodable      = (*DataExprFilter)(nil)
+	_ translation.Translatable   = (*DataExprFilter)(nil)
+	_ translation.Introspectable = (*DataExprFilter)(nil)
+	_ translation.Addressable    = (*DataExprFilter)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -73,3 +75,12 @@ func (*DataExprFilter) Manifests(id string, config, _ cty.Value, glb globals.Acc
 func (*DataExprFilter) Address(id string, _, _ cty.Value) cty.Value {
 	return k8s.NewDestination(k8s.APIFlow, "Filter", k8s.RFC1123Name(id))
 }
+
+// Kinds implements translation.Introspectable.
+func (*DataExprFilter) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIFlow, "Filter"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
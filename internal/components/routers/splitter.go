@@ -19,6 +19,7 @@ package routers
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk"
@@ -30,9 +31,10 @@ import (
 type Splitter struct{}
 
 var (
-	_ translation.Decodable    = (*Splitter)(nil)
-	_ translation.Translatable = (*Splitter)(nil)
-	_ translation.Addressable  = (*Splitter)(nil)
+	_ translation.Decodable      = (*Splitter)(nil)
+	_ translation.Translatable   = (*Splitter)(nil)
+	_ translation.Introspectable = (*Splitter)(nil)
+	_ translation.Addressable    = (*Splitter)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -110,3 +112,12 @@ func (*Splitter) Manifests(id string, config, _ cty.Value, glb globals.Accessor)
 func (*Splitter) Address(id string, _, _ cty.Value) cty.Value {
 	return k8s.NewDestination(k8s.APIFlow, "Splitter", k8s.RFC1123Name(id))
 }
+
+// Kinds implements translation.Introspectable.
+func (*Splitter) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIFlow, "Splitter"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
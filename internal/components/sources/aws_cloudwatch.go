@@ -20,8 +20,10 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
 	"til/internal/sdk/validation"
@@ -31,8 +33,9 @@ import (
 type AWSCloudWatch struct{}
 
 var (
-	_ translation.Decodable    = (*AWSCloudWatch)(nil)
-	_ translation.Translatable = (*AWSCloudWatch)(nil)
+	_ translation.Decodable      = (*AWSCloudWatch)(nil)
+	_ translation.Translatable   = (*AWSCloudWatch)(nil)
+	_ translation.Introspectable = (*AWSCloudWatch)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -101,27 +104,35 @@ func (*AWSCloudWatch) Spec() hcldec.Spec {
 		Func: validateCloudWatchAttrMetricQuery,
 	}
 
-	return &hcldec.ObjectSpec{
-		"region": &hcldec.AttrSpec{
-			Name:     "region",
-			Type:     cty.String,
-			Required: true,
-		},
-		"polling_interval": &hcldec.AttrSpec{
-			Name:     "polling_interval",
-			Type:     cty.String,
-			Required: false,
-		},
-		"metric_query": &hcldec.BlockSetSpec{
-			TypeName: "metric_query",
-			Nested:   metricQuerySpec,
-			MinItems: 1,
-		},
-		"credentials": &hcldec.AttrSpec{
-			Name:     "credentials",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"region": &hcldec.AttrSpec{
+				Name:     "region",
+				Type:     cty.String,
+				Required: true,
+			},
+			"polling_interval": &hcldec.AttrSpec{
+				Name:     "polling_interval",
+				Type:     cty.String,
+				Required: false,
+			},
+			"metric_query": &hcldec.BlockSetSpec{
+				TypeName: "metric_query",
+				Nested:   metricQuerySpec,
+				MinItems: 1,
+			},
+			"credentials": &hcldec.AttrSpec{
+				Name:     "credentials",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"iam_role": &hcldec.AttrSpec{
+				Name:     "iam_role",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("credentials", "iam_role"),
 	}
 
 	/*
@@ -240,10 +251,16 @@ func (*AWSCloudWatch) Manifests(id string, config, eventDst cty.Value, glb globa
 	}
 	s.SetNestedSlice(metricQueries, "spec", "metricQueries")
 
-	credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
-	accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
-	s.SetNestedMap(accKeySecretRef, "spec", "credentials", "accessKeyID", "valueFromSecret")
-	s.SetNestedMap(secrKeySecretRef, "spec", "credentials", "secretAccessKey", "valueFromSecret")
+	if iamRole := config.GetAttr("iam_role"); !iamRole.IsNull() {
+		saName := identity.ServiceAccountName("source", id)
+		manifests = append(manifests, identity.NewServiceAccountAWS(saName, iamRole.AsString()))
+		s.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
+		accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
+		s.SetNestedMap(accKeySecretRef, "spec", "credentials", "accessKeyID", "valueFromSecret")
+		s.SetNestedMap(secrKeySecretRef, "spec", "credentials", "secretAccessKey", "valueFromSecret")
+	}
 
 	sink := k8s.DecodeDestination(eventDst)
 	s.SetNestedMap(sink, "spec", "sink", "ref")
@@ -264,3 +281,13 @@ func validateCloudWatchAttrMetricQuery(val cty.Value) hcl.Diagnostics {
 
 	return diags
 }
+
+// Kinds implements translation.Introspectable.
+func (*AWSCloudWatch) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "AWSCloudWatchSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
@@ -19,44 +19,56 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type AWSCodeCommit struct{}
 
 var (
-	_ translation.Decodable    = (*AWSCodeCommit)(nil)
-	_ translation.Translatable = (*AWSCodeCommit)(nil)
+	_ translation.Decodable      = (*AWSCodeCommit)(nil)
+	_ translation.Translatable   = (*AWSCodeCommit)(nil)
+	_ translation.Introspectable = (*AWSCodeCommit)(nil)
 )
 
 // Spec implements translation.Decodable.
 func (*AWSCodeCommit) Spec() hcldec.Spec {
-	return &hcldec.ObjectSpec{
-		"arn": &hcldec.AttrSpec{
-			Name:     "arn",
-			Type:     cty.String,
-			Required: true,
-		},
-		"branch": &hcldec.AttrSpec{
-			Name:     "branch",
-			Type:     cty.String,
-			Required: true,
-		},
-		"event_types": &hcldec.AttrSpec{
-			Name:     "event_types",
-			Type:     cty.List(cty.String),
-			Required: true,
-		},
-		"credentials": &hcldec.AttrSpec{
-			Name:     "credentials",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"arn": &hcldec.AttrSpec{
+				Name:     "arn",
+				Type:     cty.String,
+				Required: true,
+			},
+			"branch": &hcldec.AttrSpec{
+				Name:     "branch",
+				Type:     cty.String,
+				Required: true,
+			},
+			"event_types": &hcldec.AttrSpec{
+				Name:     "event_types",
+				Type:     cty.List(cty.String),
+				Required: true,
+			},
+			"credentials": &hcldec.AttrSpec{
+				Name:     "credentials",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"iam_role": &hcldec.AttrSpec{
+				Name:     "iam_role",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("credentials", "iam_role"),
 	}
 }
 
@@ -79,13 +91,29 @@ func (*AWSCodeCommit) Manifests(id string, config, eventDst cty.Value, glb globa
 	eventTypes := sdk.DecodeStringSlice(config.GetAttr("event_types"))
 	s.SetNestedSlice(eventTypes, "spec", "eventTypes")
 
-	credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
-	accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
-	s.SetNestedMap(accKeySecretRef, "spec", "credentials", "accessKeyID", "valueFromSecret")
-	s.SetNestedMap(secrKeySecretRef, "spec", "credentials", "secretAccessKey", "valueFromSecret")
+	if iamRole := config.GetAttr("iam_role"); !iamRole.IsNull() {
+		saName := identity.ServiceAccountName("source", id)
+		manifests = append(manifests, identity.NewServiceAccountAWS(saName, iamRole.AsString()))
+		s.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
+		accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
+		s.SetNestedMap(accKeySecretRef, "spec", "credentials", "accessKeyID", "valueFromSecret")
+		s.SetNestedMap(secrKeySecretRef, "spec", "credentials", "secretAccessKey", "valueFromSecret")
+	}
 
 	sink := k8s.DecodeDestination(eventDst)
 	s.SetNestedMap(sink, "spec", "sink", "ref")
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*AWSCodeCommit) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "AWSCodeCommitSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
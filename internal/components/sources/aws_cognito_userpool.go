@@ -19,33 +19,45 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type AWSCognitoUserPool struct{}
 
 var (
-	_ translation.Decodable    = (*AWSCognitoUserPool)(nil)
-	_ translation.Translatable = (*AWSCognitoUserPool)(nil)
+	_ translation.Decodable      = (*AWSCognitoUserPool)(nil)
+	_ translation.Translatable   = (*AWSCognitoUserPool)(nil)
+	_ translation.Introspectable = (*AWSCognitoUserPool)(nil)
 )
 
 // Spec implements translation.Decodable.
 func (*AWSCognitoUserPool) Spec() hcldec.Spec {
-	return &hcldec.ObjectSpec{
-		"arn": &hcldec.AttrSpec{
-			Name:     "arn",
-			Type:     cty.String,
-			Required: true,
-		},
-		"credentials": &hcldec.AttrSpec{
-			Name:     "credentials",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"arn": &hcldec.AttrSpec{
+				Name:     "arn",
+				Type:     cty.String,
+				Required: true,
+			},
+			"credentials": &hcldec.AttrSpec{
+				Name:     "credentials",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"iam_role": &hcldec.AttrSpec{
+				Name:     "iam_role",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("credentials", "iam_role"),
 	}
 }
 
@@ -62,13 +74,29 @@ func (*AWSCognitoUserPool) Manifests(id string, config, eventDst cty.Value, glb
 	arn := config.GetAttr("arn").AsString()
 	s.SetNestedField(arn, "spec", "arn")
 
-	credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
-	accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
-	s.SetNestedMap(accKeySecretRef, "spec", "credentials", "accessKeyID", "valueFromSecret")
-	s.SetNestedMap(secrKeySecretRef, "spec", "credentials", "secretAccessKey", "valueFromSecret")
+	if iamRole := config.GetAttr("iam_role"); !iamRole.IsNull() {
+		saName := identity.ServiceAccountName("source", id)
+		manifests = append(manifests, identity.NewServiceAccountAWS(saName, iamRole.AsString()))
+		s.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
+		accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
+		s.SetNestedMap(accKeySecretRef, "spec", "credentials", "accessKeyID", "valueFromSecret")
+		s.SetNestedMap(secrKeySecretRef, "spec", "credentials", "secretAccessKey", "valueFromSecret")
+	}
 
 	sink := k8s.DecodeDestination(eventDst)
 	s.SetNestedMap(sink, "spec", "sink", "ref")
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*AWSCognitoUserPool) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "AWSCognitoUserPoolSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
@@ -19,44 +19,56 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type AzureActivityLogs struct{}
 
 var (
-	_ translation.Decodable    = (*AzureActivityLogs)(nil)
-	_ translation.Translatable = (*AzureActivityLogs)(nil)
+	_ translation.Decodable      = (*AzureActivityLogs)(nil)
+	_ translation.Translatable   = (*AzureActivityLogs)(nil)
+	_ translation.Introspectable = (*AzureActivityLogs)(nil)
 )
 
 // Spec implements translation.Decodable.
 func (*AzureActivityLogs) Spec() hcldec.Spec {
-	return &hcldec.ObjectSpec{
-		"event_hub_id": &hcldec.AttrSpec{
-			Name:     "event_hub_id",
-			Type:     cty.String,
-			Required: true,
-		},
-		"event_hubs_sas_policy": &hcldec.AttrSpec{
-			Name:     "event_hubs_sas_policy",
-			Type:     cty.String,
-			Required: false,
-		},
-		"categories": &hcldec.AttrSpec{
-			Name:     "categories",
-			Type:     cty.List(cty.String),
-			Required: false,
-		},
-		"auth": &hcldec.AttrSpec{
-			Name:     "auth",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"event_hub_id": &hcldec.AttrSpec{
+				Name:     "event_hub_id",
+				Type:     cty.String,
+				Required: true,
+			},
+			"event_hubs_sas_policy": &hcldec.AttrSpec{
+				Name:     "event_hubs_sas_policy",
+				Type:     cty.String,
+				Required: false,
+			},
+			"categories": &hcldec.AttrSpec{
+				Name:     "categories",
+				Type:     cty.List(cty.String),
+				Required: false,
+			},
+			"auth": &hcldec.AttrSpec{
+				Name:     "auth",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"workload_identity": &hcldec.AttrSpec{
+				Name:     "workload_identity",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("auth", "workload_identity"),
 	}
 }
 
@@ -83,14 +95,30 @@ func (*AzureActivityLogs) Manifests(id string, config, eventDst cty.Value, glb g
 		s.SetNestedSlice(categories, "spec", "categories")
 	}
 
-	authSecretName := config.GetAttr("auth").GetAttr("name").AsString()
-	tenantIDSecretRef, clientIDSecretRef, clientSecrSecretRef := secrets.SecretKeyRefsAzureSP(authSecretName)
-	s.SetNestedMap(tenantIDSecretRef, "spec", "auth", "servicePrincipal", "tenantID", "valueFromSecret")
-	s.SetNestedMap(clientIDSecretRef, "spec", "auth", "servicePrincipal", "clientID", "valueFromSecret")
-	s.SetNestedMap(clientSecrSecretRef, "spec", "auth", "servicePrincipal", "clientSecret", "valueFromSecret")
+	if clientID := config.GetAttr("workload_identity"); !clientID.IsNull() {
+		saName := identity.ServiceAccountName("source", id)
+		manifests = append(manifests, identity.NewServiceAccountAzure(saName, clientID.AsString()))
+		s.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		authSecretName := config.GetAttr("auth").GetAttr("name").AsString()
+		tenantIDSecretRef, clientIDSecretRef, clientSecrSecretRef := secrets.SecretKeyRefsAzureSP(authSecretName)
+		s.SetNestedMap(tenantIDSecretRef, "spec", "auth", "servicePrincipal", "tenantID", "valueFromSecret")
+		s.SetNestedMap(clientIDSecretRef, "spec", "auth", "servicePrincipal", "clientID", "valueFromSecret")
+		s.SetNestedMap(clientSecrSecretRef, "spec", "auth", "servicePrincipal", "clientSecret", "valueFromSecret")
+	}
 
 	sink := k8s.DecodeDestination(eventDst)
 	s.SetNestedMap(sink, "spec", "sink", "ref")
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*AzureActivityLogs) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "AzureActivityLogsSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
@@ -19,44 +19,56 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type AzureBlobStorage struct{}
 
 var (
-	_ translation.Decodable    = (*AzureBlobStorage)(nil)
-	_ translation.Translatable = (*AzureBlobStorage)(nil)
+	_ translation.Decodable      = (*AzureBlobStorage)(nil)
+	_ translation.Translatable   = (*AzureBlobStorage)(nil)
+	_ translation.Introspectable = (*AzureBlobStorage)(nil)
 )
 
 // Spec implements translation.Decodable.
 func (*AzureBlobStorage) Spec() hcldec.Spec {
-	return &hcldec.ObjectSpec{
-		"storage_account_id": &hcldec.AttrSpec{
-			Name:     "storage_account_id",
-			Type:     cty.String,
-			Required: true,
-		},
-		"event_hub_id": &hcldec.AttrSpec{
-			Name:     "event_hub_id",
-			Type:     cty.String,
-			Required: true,
-		},
-		"event_types": &hcldec.AttrSpec{
-			Name:     "event_types",
-			Type:     cty.List(cty.String),
-			Required: false,
-		},
-		"auth": &hcldec.AttrSpec{
-			Name:     "auth",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"storage_account_id": &hcldec.AttrSpec{
+				Name:     "storage_account_id",
+				Type:     cty.String,
+				Required: true,
+			},
+			"event_hub_id": &hcldec.AttrSpec{
+				Name:     "event_hub_id",
+				Type:     cty.String,
+				Required: true,
+			},
+			"event_types": &hcldec.AttrSpec{
+				Name:     "event_types",
+				Type:     cty.List(cty.String),
+				Required: false,
+			},
+			"auth": &hcldec.AttrSpec{
+				Name:     "auth",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"workload_identity": &hcldec.AttrSpec{
+				Name:     "workload_identity",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("auth", "workload_identity"),
 	}
 }
 
@@ -81,14 +93,30 @@ func (*AzureBlobStorage) Manifests(id string, config, eventDst cty.Value, glb gl
 		s.SetNestedSlice(eventTypes, "spec", "eventTypes")
 	}
 
-	authSecretName := config.GetAttr("auth").GetAttr("name").AsString()
-	tenantIDSecretRef, clientIDSecretRef, clientSecrSecretRef := secrets.SecretKeyRefsAzureSP(authSecretName)
-	s.SetNestedMap(tenantIDSecretRef, "spec", "auth", "servicePrincipal", "tenantID", "valueFromSecret")
-	s.SetNestedMap(clientIDSecretRef, "spec", "auth", "servicePrincipal", "clientID", "valueFromSecret")
-	s.SetNestedMap(clientSecrSecretRef, "spec", "auth", "servicePrincipal", "clientSecret", "valueFromSecret")
+	if clientID := config.GetAttr("workload_identity"); !clientID.IsNull() {
+		saName := identity.ServiceAccountName("source", id)
+		manifests = append(manifests, identity.NewServiceAccountAzure(saName, clientID.AsString()))
+		s.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		authSecretName := config.GetAttr("auth").GetAttr("name").AsString()
+		tenantIDSecretRef, clientIDSecretRef, clientSecrSecretRef := secrets.SecretKeyRefsAzureSP(authSecretName)
+		s.SetNestedMap(tenantIDSecretRef, "spec", "auth", "servicePrincipal", "tenantID", "valueFromSecret")
+		s.SetNestedMap(clientIDSecretRef, "spec", "auth", "servicePrincipal", "clientID", "valueFromSecret")
+		s.SetNestedMap(clientSecrSecretRef, "spec", "auth", "servicePrincipal", "clientSecret", "valueFromSecret")
+	}
 
 	sink := k8s.DecodeDestination(eventDst)
 	s.SetNestedMap(sink, "spec", "sink", "ref")
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*AzureBlobStorage) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "AzureBlobStorageSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
@@ -19,38 +19,50 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type AzureEventHubs struct{}
 
 var (
-	_ translation.Decodable    = (*AzureEventHubs)(nil)
-	_ translation.Translatable = (*AzureEventHubs)(nil)
+	_ translation.Decodable      = (*AzureEventHubs)(nil)
+	_ translation.Translatable   = (*AzureEventHubs)(nil)
+	_ translation.Introspectable = (*AzureEventHubs)(nil)
 )
 
 // Spec implements translation.Decodable.
 func (*AzureEventHubs) Spec() hcldec.Spec {
-	return &hcldec.ObjectSpec{
-		"hub_namespace": &hcldec.AttrSpec{
-			Name:     "hub_namespace",
-			Type:     cty.String,
-			Required: true,
-		},
-		"hub_name": &hcldec.AttrSpec{
-			Name:     "hub_name",
-			Type:     cty.String,
-			Required: true,
-		},
-		"auth": &hcldec.AttrSpec{
-			Name:     "auth",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"hub_namespace": &hcldec.AttrSpec{
+				Name:     "hub_namespace",
+				Type:     cty.String,
+				Required: true,
+			},
+			"hub_name": &hcldec.AttrSpec{
+				Name:     "hub_name",
+				Type:     cty.String,
+				Required: true,
+			},
+			"auth": &hcldec.AttrSpec{
+				Name:     "auth",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"workload_identity": &hcldec.AttrSpec{
+				Name:     "workload_identity",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("auth", "workload_identity"),
 	}
 }
 
@@ -70,14 +82,30 @@ func (*AzureEventHubs) Manifests(id string, config, eventDst cty.Value, glb glob
 	hubName := config.GetAttr("hub_name").AsString()
 	s.SetNestedField(hubName, "spec", "hubName")
 
-	authSecretName := config.GetAttr("auth").GetAttr("name").AsString()
-	tenantIDSecretRef, clientIDSecretRef, clientSecrSecretRef := secrets.SecretKeyRefsAzureSP(authSecretName)
-	s.SetNestedMap(tenantIDSecretRef, "spec", "auth", "servicePrincipal", "tenantID", "valueFromSecret")
-	s.SetNestedMap(clientIDSecretRef, "spec", "auth", "servicePrincipal", "clientID", "valueFromSecret")
-	s.SetNestedMap(clientSecrSecretRef, "spec", "auth", "servicePrincipal", "clientSecret", "valueFromSecret")
+	if clientID := config.GetAttr("workload_identity"); !clientID.IsNull() {
+		saName := identity.ServiceAccountName("source", id)
+		manifests = append(manifests, identity.NewServiceAccountAzure(saName, clientID.AsString()))
+		s.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		authSecretName := config.GetAttr("auth").GetAttr("name").AsString()
+		tenantIDSecretRef, clientIDSecretRef, clientSecrSecretRef := secrets.SecretKeyRefsAzureSP(authSecretName)
+		s.SetNestedMap(tenantIDSecretRef, "spec", "auth", "servicePrincipal", "tenantID", "valueFromSecret")
+		s.SetNestedMap(clientIDSecretRef, "spec", "auth", "servicePrincipal", "clientID", "valueFromSecret")
+		s.SetNestedMap(clientSecrSecretRef, "spec", "auth", "servicePrincipal", "clientSecret", "valueFromSecret")
+	}
 
 	sink := k8s.DecodeDestination(eventDst)
 	s.SetNestedMap(sink, "spec", "sink", "ref")
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*AzureEventHubs) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "AzureEventHubSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
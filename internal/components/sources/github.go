@@ -19,6 +19,7 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk"
@@ -30,8 +31,9 @@ import (
 type GitHub struct{}
 
 var (
-	_ translation.Decodable    = (*GitHub)(nil)
-	_ translation.Translatable = (*GitHub)(nil)
+	_ translation.Decodable      = (*GitHub)(nil)
+	_ translation.Translatable   = (*GitHub)(nil)
+	_ translation.Introspectable = (*GitHub)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -81,3 +83,12 @@ func (*GitHub) Manifests(id string, config, eventDst cty.Value, glb globals.Acce
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*GitHub) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind("sources.knative.dev/v1alpha1", "GitHubSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -19,6 +19,7 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -28,8 +29,9 @@ import (
 type HTTPPoller struct{}
 
 var (
-	_ translation.Decodable    = (*HTTPPoller)(nil)
-	_ translation.Translatable = (*HTTPPoller)(nil)
+	_ translation.Decodable      = (*HTTPPoller)(nil)
+	_ translation.Translatable   = (*HTTPPoller)(nil)
+	_ translation.Introspectable = (*HTTPPoller)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -95,3 +97,12 @@ func (*HTTPPoller) Manifests(id string, config, eventDst cty.Value, glb globals.
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*HTTPPoller) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "HTTPPollerSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
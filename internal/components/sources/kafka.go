@@ -20,6 +20,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk"
@@ -31,8 +32,9 @@ import (
 type Kafka struct{}
 
 var (
-	_ translation.Decodable    = (*Kafka)(nil)
-	_ translation.Translatable = (*Kafka)(nil)
+	_ translation.Decodable      = (*Kafka)(nil)
+	_ translation.Translatable   = (*Kafka)(nil)
+	_ translation.Introspectable = (*Kafka)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -139,3 +141,12 @@ func validateKafkaAttrTLS(val cty.Value) hcl.Diagnostics {
 
 	return diags
 }
+
+// Kinds implements translation.Introspectable.
+func (*Kafka) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind("sources.knative.dev/v1beta1", "KafkaSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
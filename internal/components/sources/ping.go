@@ -21,6 +21,7 @@ import (
 
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -30,8 +31,9 @@ import (
 type Ping struct{}
 
 var (
-	_ translation.Decodable    = (*Ping)(nil)
-	_ translation.Translatable = (*Ping)(nil)
+	_ translation.Decodable      = (*Ping)(nil)
+	_ translation.Translatable   = (*Ping)(nil)
+	_ translation.Introspectable = (*Ping)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -89,3 +91,12 @@ func (*Ping) Manifests(id string, config, eventDst cty.Value, glb globals.Access
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*Ping) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind("sources.knative.dev/v1", "PingSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
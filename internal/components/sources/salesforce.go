@@ -19,6 +19,7 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -30,8 +31,9 @@ import (
 type Salesforce struct{}
 
 var (
-	_ translation.Decodable    = (*Salesforce)(nil)
-	_ translation.Translatable = (*Salesforce)(nil)
+	_ translation.Decodable      = (*Salesforce)(nil)
+	_ translation.Translatable   = (*Salesforce)(nil)
+	_ translation.Introspectable = (*Salesforce)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -109,3 +111,12 @@ func (*Salesforce) Manifests(id string, config, eventDst cty.Value, glb globals.
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*Salesforce) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "SalesforceSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
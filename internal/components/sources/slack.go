@@ -19,6 +19,7 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -29,8 +30,9 @@ import (
 type Slack struct{}
 
 var (
-	_ translation.Decodable    = (*Slack)(nil)
-	_ translation.Translatable = (*Slack)(nil)
+	_ translation.Decodable      = (*Slack)(nil)
+	_ translation.Translatable   = (*Slack)(nil)
+	_ translation.Introspectable = (*Slack)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -75,3 +77,12 @@ func (*Slack) Manifests(id string, config, eventDst cty.Value, glb globals.Acces
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*Slack) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "SlackSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -19,17 +19,20 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type Webhook struct{}
 
 var (
-	_ translation.Decodable    = (*Webhook)(nil)
-	_ translation.Translatable = (*Webhook)(nil)
+	_ translation.Decodable      = (*Webhook)(nil)
+	_ translation.Translatable   = (*Webhook)(nil)
+	_ translation.Introspectable = (*Webhook)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -50,10 +53,13 @@ func (*Webhook) Spec() hcldec.Spec {
 			Type:     cty.String,
 			Required: false,
 		},
-		"basic_auth_password": &hcldec.AttrSpec{
-			Name:     "basic_auth_password",
-			Type:     cty.String,
-			Required: false,
+		"basic_auth_password": &hcldec.ValidateSpec{
+			Wrapped: &hcldec.AttrSpec{
+				Name:     "basic_auth_password",
+				Type:     cty.DynamicPseudoType,
+				Required: false,
+			},
+			Func: validation.IsStringOrSecretRef,
 		},
 	}
 }
@@ -83,7 +89,7 @@ func (*Webhook) Manifests(id string, config, eventDst cty.Value, glb globals.Acc
 
 	basicAuthPassword := config.GetAttr("basic_auth_password")
 	if !basicAuthPassword.IsNull() {
-		s.SetNestedField(basicAuthPassword.AsString(), "spec", "basicAuthPassword", "value")
+		s.SetNestedMap(k8s.ValueFromField(basicAuthPassword), "spec", "basicAuthPassword")
 	}
 
 	sink := k8s.DecodeDestination(eventDst)
@@ -91,3 +97,12 @@ func (*Webhook) Manifests(id string, config, eventDst cty.Value, glb globals.Acc
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*Webhook) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "WebhookSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -19,18 +19,21 @@ package sources
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type Zendesk struct{}
 
 var (
-	_ translation.Decodable    = (*Zendesk)(nil)
-	_ translation.Translatable = (*Zendesk)(nil)
+	_ translation.Decodable      = (*Zendesk)(nil)
+	_ translation.Translatable   = (*Zendesk)(nil)
+	_ translation.Introspectable = (*Zendesk)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -56,10 +59,13 @@ func (*Zendesk) Spec() hcldec.Spec {
 			Type:     cty.String,
 			Required: true,
 		},
-		"webhook_password": &hcldec.AttrSpec{
-			Name:     "webhook_password",
-			Type:     cty.String,
-			Required: true,
+		"webhook_password": &hcldec.ValidateSpec{
+			Wrapped: &hcldec.AttrSpec{
+				Name:     "webhook_password",
+				Type:     cty.DynamicPseudoType,
+				Required: true,
+			},
+			Func: validation.IsStringOrSecretRef,
 		},
 	}
 }
@@ -87,11 +93,20 @@ func (*Zendesk) Manifests(id string, config, eventDst cty.Value, glb globals.Acc
 	webhookUsername := config.GetAttr("webhook_username").AsString()
 	s.SetNestedField(webhookUsername, "spec", "webhookUsername")
 
-	webhookPassword := config.GetAttr("webhook_password").AsString()
-	s.SetNestedField(webhookPassword, "spec", "webhookPassword", "value")
+	webhookPassword := k8s.ValueFromField(config.GetAttr("webhook_password"))
+	s.SetNestedMap(webhookPassword, "spec", "webhookPassword")
 
 	sink := k8s.DecodeDestination(eventDst)
 	s.SetNestedMap(sink, "spec", "sink", "ref")
 
 	return append(manifests, s.Unstructured())
 }
+
+// Kinds implements translation.Introspectable.
+func (*Zendesk) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APISources, "ZendeskSource"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -19,34 +19,46 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type AWSDynamoDB struct{}
 
 var (
-	_ translation.Decodable    = (*AWSDynamoDB)(nil)
-	_ translation.Translatable = (*AWSDynamoDB)(nil)
-	_ translation.Addressable  = (*AWSDynamoDB)(nil)
+	_ translation.Decodable      = (*AWSDynamoDB)(nil)
+	_ translation.Translatable   = (*AWSDynamoDB)(nil)
+	_ translation.Introspectable = (*AWSDynamoDB)(nil)
+	_ translation.Addressable    = (*AWSDynamoDB)(nil)
 )
 
 // Spec implements translation.Decodable.
 func (*AWSDynamoDB) Spec() hcldec.Spec {
-	return &hcldec.ObjectSpec{
-		"arn": &hcldec.AttrSpec{
-			Name:     "arn",
-			Type:     cty.String,
-			Required: true,
-		},
-		"credentials": &hcldec.AttrSpec{
-			Name:     "credentials",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"arn": &hcldec.AttrSpec{
+				Name:     "arn",
+				Type:     cty.String,
+				Required: true,
+			},
+			"credentials": &hcldec.AttrSpec{
+				Name:     "credentials",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"iam_role": &hcldec.AttrSpec{
+				Name:     "iam_role",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("credentials", "iam_role"),
 	}
 }
 
@@ -61,10 +73,16 @@ func (*AWSDynamoDB) Manifests(id string, config, eventDst cty.Value, glb globals
 	arn := config.GetAttr("arn").AsString()
 	t.SetNestedField(arn, "spec", "arn")
 
-	credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
-	accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
-	t.SetNestedMap(accKeySecretRef, "spec", "awsApiKey", "secretKeyRef")
-	t.SetNestedMap(secrKeySecretRef, "spec", "awsApiSecret", "secretKeyRef")
+	if iamRole := config.GetAttr("iam_role"); !iamRole.IsNull() {
+		saName := identity.ServiceAccountName("target", id)
+		manifests = append(manifests, identity.NewServiceAccountAWS(saName, iamRole.AsString()))
+		t.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
+		accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
+		t.SetNestedMap(accKeySecretRef, "spec", "awsApiKey", "secretKeyRef")
+		t.SetNestedMap(secrKeySecretRef, "spec", "awsApiSecret", "secretKeyRef")
+	}
 
 	manifests = append(manifests, t.Unstructured())
 
@@ -93,3 +111,13 @@ func (*AWSDynamoDB) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*AWSDynamoDB) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "AWSDynamoDBTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
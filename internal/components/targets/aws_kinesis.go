@@ -19,34 +19,46 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type AWSKinesis struct{}
 
 var (
-	_ translation.Decodable    = (*AWSKinesis)(nil)
-	_ translation.Translatable = (*AWSKinesis)(nil)
-	_ translation.Addressable  = (*AWSKinesis)(nil)
+	_ translation.Decodable      = (*AWSKinesis)(nil)
+	_ translation.Translatable   = (*AWSKinesis)(nil)
+	_ translation.Introspectable = (*AWSKinesis)(nil)
+	_ translation.Addressable    = (*AWSKinesis)(nil)
 )
 
 // Spec implements translation.Decodable.
 func (*AWSKinesis) Spec() hcldec.Spec {
-	return &hcldec.ObjectSpec{
-		"arn": &hcldec.AttrSpec{
-			Name:     "arn",
-			Type:     cty.String,
-			Required: true,
-		},
-		"credentials": &hcldec.AttrSpec{
-			Name:     "credentials",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"arn": &hcldec.AttrSpec{
+				Name:     "arn",
+				Type:     cty.String,
+				Required: true,
+			},
+			"credentials": &hcldec.AttrSpec{
+				Name:     "credentials",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"iam_role": &hcldec.AttrSpec{
+				Name:     "iam_role",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("credentials", "iam_role"),
 	}
 }
 
@@ -64,10 +76,16 @@ func (*AWSKinesis) Manifests(id string, config, eventDst cty.Value, glb globals.
 	const partitionKey = "static"
 	t.SetNestedField(partitionKey, "spec", "partition")
 
-	credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
-	accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
-	t.SetNestedMap(accKeySecretRef, "spec", "awsApiKey", "secretKeyRef")
-	t.SetNestedMap(secrKeySecretRef, "spec", "awsApiSecret", "secretKeyRef")
+	if iamRole := config.GetAttr("iam_role"); !iamRole.IsNull() {
+		saName := identity.ServiceAccountName("target", id)
+		manifests = append(manifests, identity.NewServiceAccountAWS(saName, iamRole.AsString()))
+		t.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
+		accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
+		t.SetNestedMap(accKeySecretRef, "spec", "awsApiKey", "secretKeyRef")
+		t.SetNestedMap(secrKeySecretRef, "spec", "awsApiSecret", "secretKeyRef")
+	}
 
 	manifests = append(manifests, t.Unstructured())
 
@@ -96,3 +114,13 @@ func (*AWSKinesis) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*AWSKinesis) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "AWSKinesisTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
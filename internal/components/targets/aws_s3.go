@@ -19,34 +19,46 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type AWSS3 struct{}
 
 var (
-	_ translation.Decodable    = (*AWSS3)(nil)
-	_ translation.Translatable = (*AWSS3)(nil)
-	_ translation.Addressable  = (*AWSS3)(nil)
+	_ translation.Decodable      = (*AWSS3)(nil)
+	_ translation.Translatable   = (*AWSS3)(nil)
+	_ translation.Introspectable = (*AWSS3)(nil)
+	_ translation.Addressable    = (*AWSS3)(nil)
 )
 
 // Spec implements translation.Decodable.
 func (*AWSS3) Spec() hcldec.Spec {
-	return &hcldec.ObjectSpec{
-		"arn": &hcldec.AttrSpec{
-			Name:     "arn",
-			Type:     cty.String,
-			Required: true,
-		},
-		"credentials": &hcldec.AttrSpec{
-			Name:     "credentials",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"arn": &hcldec.AttrSpec{
+				Name:     "arn",
+				Type:     cty.String,
+				Required: true,
+			},
+			"credentials": &hcldec.AttrSpec{
+				Name:     "credentials",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"iam_role": &hcldec.AttrSpec{
+				Name:     "iam_role",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("credentials", "iam_role"),
 	}
 }
 
@@ -61,10 +73,16 @@ func (*AWSS3) Manifests(id string, config, eventDst cty.Value, glb globals.Acces
 	arn := config.GetAttr("arn").AsString()
 	t.SetNestedField(arn, "spec", "arn")
 
-	credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
-	accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
-	t.SetNestedMap(accKeySecretRef, "spec", "awsApiKey", "secretKeyRef")
-	t.SetNestedMap(secrKeySecretRef, "spec", "awsApiSecret", "secretKeyRef")
+	if iamRole := config.GetAttr("iam_role"); !iamRole.IsNull() {
+		saName := identity.ServiceAccountName("target", id)
+		manifests = append(manifests, identity.NewServiceAccountAWS(saName, iamRole.AsString()))
+		t.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		credsSecretName := config.GetAttr("credentials").GetAttr("name").AsString()
+		accKeySecretRef, secrKeySecretRef := secrets.SecretKeyRefsAWS(credsSecretName)
+		t.SetNestedMap(accKeySecretRef, "spec", "awsApiKey", "secretKeyRef")
+		t.SetNestedMap(secrKeySecretRef, "spec", "awsApiSecret", "secretKeyRef")
+	}
 
 	manifests = append(manifests, t.Unstructured())
 
@@ -93,3 +111,13 @@ func (*AWSS3) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*AWSS3) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "AWSS3Target"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -29,9 +30,10 @@ import (
 type Container struct{}
 
 var (
-	_ translation.Decodable    = (*Container)(nil)
-	_ translation.Translatable = (*Container)(nil)
-	_ translation.Addressable  = (*Container)(nil)
+	_ translation.Decodable      = (*Container)(nil)
+	_ translation.Translatable   = (*Container)(nil)
+	_ translation.Introspectable = (*Container)(nil)
+	_ translation.Addressable    = (*Container)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -74,7 +76,7 @@ func (*Container) Spec() hcldec.Spec {
 func (*Container) Manifests(id string, config, eventDst cty.Value, glb globals.Accessor) []interface{} {
 	var manifests []interface{}
 
-	name := k8s.RFC1123Name(id)
+	name := k8s.RFC1035LabelName(id)
 
 	img := config.GetAttr("image").AsString()
 	public := config.GetAttr("public").True()
@@ -115,7 +117,7 @@ func (*Container) Manifests(id string, config, eventDst cty.Value, glb globals.A
 
 // Address implements translation.Addressable.
 func (*Container) Address(id string, _, eventDst cty.Value) cty.Value {
-	name := k8s.RFC1123Name(id)
+	name := k8s.RFC1035LabelName(id)
 
 	if eventDst.IsNull() {
 		return k8s.NewDestination(k8s.APIServing, "Service", name)
@@ -157,3 +159,12 @@ func validateContainerAttrEnvVar(val cty.Value) hcl.Diagnostics {
 
 	return diags
 }
+
+// Kinds implements translation.Introspectable.
+func (*Container) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIServing, "Service"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
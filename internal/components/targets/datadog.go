@@ -19,6 +19,7 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -29,9 +30,10 @@ import (
 type Datadog struct{}
 
 var (
-	_ translation.Decodable    = (*Datadog)(nil)
-	_ translation.Translatable = (*Datadog)(nil)
-	_ translation.Addressable  = (*Datadog)(nil)
+	_ translation.Decodable      = (*Datadog)(nil)
+	_ translation.Translatable   = (*Datadog)(nil)
+	_ translation.Introspectable = (*Datadog)(nil)
+	_ translation.Addressable    = (*Datadog)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -94,3 +96,12 @@ func (*Datadog) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*Datadog) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "DatadogTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
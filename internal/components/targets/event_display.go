@@ -17,9 +17,10 @@ limitations under the License.
 package targets
 
 import (
+	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
-	"til/config/globals"
 	"til/internal/sdk/k8s"
 	"til/translation"
 )
@@ -27,13 +28,15 @@ import (
 type EventDisplay struct{}
 
 var (
-	_ translation.Translatable = (*EventDisplay)(nil)
-	_ translation.Addressable  = (*EventDisplay)(nil)
+	_ translation.TranslatableV2 = (*EventDisplay)(nil)
+	_ translation.Introspectable = (*EventDisplay)(nil)
+	_ translation.Addressable    = (*EventDisplay)(nil)
 )
 
-// Manifests implements translation.Translatable.
-func (*EventDisplay) Manifests(id string, _, _ cty.Value, _ globals.Accessor) []interface{} {
+// ManifestsV2 implements translation.TranslatableV2.
+func (*EventDisplay) ManifestsV2(tctx *translation.Context) ([]interface{}, hcl.Diagnostics) {
 	var manifests []interface{}
+	var diags hcl.Diagnostics
 
 	// Knative v0.23.0
 	// https://prow.knative.dev/?job=ci-knative-eventing-auto-release
@@ -42,12 +45,19 @@ func (*EventDisplay) Manifests(id string, _, _ cty.Value, _ globals.Accessor) []
 
 	const public = false
 
-	ksvc := k8s.NewKnService(k8s.RFC1123Name(id), img, public)
+	ksvc := k8s.NewKnService(k8s.RFC1035LabelName(tctx.Identifier), img, public)
 
-	return append(manifests, ksvc)
+	return append(manifests, ksvc), diags
 }
 
 // Address implements translation.Addressable.
 func (*EventDisplay) Address(id string, _, _ cty.Value) cty.Value {
-	return k8s.NewDestination(k8s.APIServing, "Service", k8s.RFC1123Name(id))
+	return k8s.NewDestination(k8s.APIServing, "Service", k8s.RFC1035LabelName(id))
+}
+
+// Kinds implements translation.Introspectable.
+func (*EventDisplay) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIServing, "Service"),
+	}
 }
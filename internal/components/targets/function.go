@@ -19,6 +19,7 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk"
@@ -29,9 +30,10 @@ import (
 type Function struct{}
 
 var (
-	_ translation.Decodable    = (*Function)(nil)
-	_ translation.Translatable = (*Function)(nil)
-	_ translation.Addressable  = (*Function)(nil)
+	_ translation.Decodable      = (*Function)(nil)
+	_ translation.Translatable   = (*Function)(nil)
+	_ translation.Introspectable = (*Function)(nil)
+	_ translation.Addressable    = (*Function)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -137,3 +139,12 @@ func (*Function) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*Function) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIExt, "Function"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -19,39 +19,51 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type GCloudFirestore struct{}
 
 var (
-	_ translation.Decodable    = (*GCloudFirestore)(nil)
-	_ translation.Translatable = (*GCloudFirestore)(nil)
-	_ translation.Addressable  = (*GCloudFirestore)(nil)
+	_ translation.Decodable      = (*GCloudFirestore)(nil)
+	_ translation.Translatable   = (*GCloudFirestore)(nil)
+	_ translation.Introspectable = (*GCloudFirestore)(nil)
+	_ translation.Addressable    = (*GCloudFirestore)(nil)
 )
 
 // Spec implements translation.Decodable.
 func (*GCloudFirestore) Spec() hcldec.Spec {
-	return &hcldec.ObjectSpec{
-		"default_collection": &hcldec.AttrSpec{
-			Name:     "default_collection",
-			Type:     cty.String,
-			Required: true,
-		},
-		"project_id": &hcldec.AttrSpec{
-			Name:     "project_id",
-			Type:     cty.String,
-			Required: true,
-		},
-		"service_account": &hcldec.AttrSpec{
-			Name:     "service_account",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"default_collection": &hcldec.AttrSpec{
+				Name:     "default_collection",
+				Type:     cty.String,
+				Required: true,
+			},
+			"project_id": &hcldec.AttrSpec{
+				Name:     "project_id",
+				Type:     cty.String,
+				Required: true,
+			},
+			"service_account": &hcldec.AttrSpec{
+				Name:     "service_account",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"workload_identity": &hcldec.AttrSpec{
+				Name:     "workload_identity",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("service_account", "workload_identity"),
 	}
 }
 
@@ -69,9 +81,15 @@ func (*GCloudFirestore) Manifests(id string, config, eventDst cty.Value, glb glo
 	projectID := config.GetAttr("project_id").AsString()
 	t.SetNestedField(projectID, "spec", "projectID")
 
-	svcAccountSecretName := config.GetAttr("service_account").GetAttr("name").AsString()
-	keySecretRef := secrets.SecretKeyRefsGCloudServiceAccount(svcAccountSecretName)
-	t.SetNestedMap(keySecretRef, "spec", "credentialsJson", "secretKeyRef")
+	if svcAccountEmail := config.GetAttr("workload_identity"); !svcAccountEmail.IsNull() {
+		saName := identity.ServiceAccountName("target", id)
+		manifests = append(manifests, identity.NewServiceAccountGCloud(saName, svcAccountEmail.AsString()))
+		t.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		svcAccountSecretName := config.GetAttr("service_account").GetAttr("name").AsString()
+		keySecretRef := secrets.SecretKeyRefsGCloudServiceAccount(svcAccountSecretName)
+		t.SetNestedMap(keySecretRef, "spec", "credentialsJson", "secretKeyRef")
+	}
 
 	manifests = append(manifests, t.Unstructured())
 
@@ -100,3 +118,13 @@ func (*GCloudFirestore) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*GCloudFirestore) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "GoogleCloudFirestoreTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
@@ -19,34 +19,46 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
+	"til/internal/sdk/identity"
 	"til/internal/sdk/k8s"
 	"til/internal/sdk/secrets"
+	"til/internal/sdk/validation"
 	"til/translation"
 )
 
 type GCloudStorage struct{}
 
 var (
-	_ translation.Decodable    = (*GCloudStorage)(nil)
-	_ translation.Translatable = (*GCloudStorage)(nil)
-	_ translation.Addressable  = (*GCloudStorage)(nil)
+	_ translation.Decodable      = (*GCloudStorage)(nil)
+	_ translation.Translatable   = (*GCloudStorage)(nil)
+	_ translation.Introspectable = (*GCloudStorage)(nil)
+	_ translation.Addressable    = (*GCloudStorage)(nil)
 )
 
 // Spec implements translation.Decodable.
 func (*GCloudStorage) Spec() hcldec.Spec {
-	return &hcldec.ObjectSpec{
-		"bucket_name": &hcldec.AttrSpec{
-			Name:     "bucket_name",
-			Type:     cty.String,
-			Required: true,
-		},
-		"service_account": &hcldec.AttrSpec{
-			Name:     "service_account",
-			Type:     k8s.ObjectReferenceCty,
-			Required: true,
+	return &hcldec.ValidateSpec{
+		Wrapped: &hcldec.ObjectSpec{
+			"bucket_name": &hcldec.AttrSpec{
+				Name:     "bucket_name",
+				Type:     cty.String,
+				Required: true,
+			},
+			"service_account": &hcldec.AttrSpec{
+				Name:     "service_account",
+				Type:     k8s.ObjectReferenceCty,
+				Required: false,
+			},
+			"workload_identity": &hcldec.AttrSpec{
+				Name:     "workload_identity",
+				Type:     cty.String,
+				Required: false,
+			},
 		},
+		Func: validation.ExactlyOneOf("service_account", "workload_identity"),
 	}
 }
 
@@ -61,9 +73,15 @@ func (*GCloudStorage) Manifests(id string, config, eventDst cty.Value, glb globa
 	bucketName := config.GetAttr("bucket_name").AsString()
 	t.SetNestedField(bucketName, "spec", "bucketName")
 
-	svcAccountSecretName := config.GetAttr("service_account").GetAttr("name").AsString()
-	keySecretRef := secrets.SecretKeyRefsGCloudServiceAccount(svcAccountSecretName)
-	t.SetNestedMap(keySecretRef, "spec", "credentialsJson", "secretKeyRef")
+	if svcAccountEmail := config.GetAttr("workload_identity"); !svcAccountEmail.IsNull() {
+		saName := identity.ServiceAccountName("target", id)
+		manifests = append(manifests, identity.NewServiceAccountGCloud(saName, svcAccountEmail.AsString()))
+		t.SetNestedField(saName, "spec", "serviceAccountName")
+	} else {
+		svcAccountSecretName := config.GetAttr("service_account").GetAttr("name").AsString()
+		keySecretRef := secrets.SecretKeyRefsGCloudServiceAccount(svcAccountSecretName)
+		t.SetNestedMap(keySecretRef, "spec", "credentialsJson", "secretKeyRef")
+	}
 
 	manifests = append(manifests, t.Unstructured())
 
@@ -92,3 +110,13 @@ func (*GCloudStorage) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*GCloudStorage) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "GoogleCloudStorageTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+		k8s.GroupVersionKind(k8s.APICore, "ServiceAccount"),
+	}
+}
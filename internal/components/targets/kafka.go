@@ -19,6 +19,7 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk"
@@ -29,9 +30,10 @@ import (
 type Kafka struct{}
 
 var (
-	_ translation.Decodable    = (*Kafka)(nil)
-	_ translation.Translatable = (*Kafka)(nil)
-	_ translation.Addressable  = (*Kafka)(nil)
+	_ translation.Decodable      = (*Kafka)(nil)
+	_ translation.Translatable   = (*Kafka)(nil)
+	_ translation.Introspectable = (*Kafka)(nil)
+	_ translation.Addressable    = (*Kafka)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -99,3 +101,12 @@ func (*Kafka) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*Kafka) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIEventingV1Alpha1, "KafkaSink"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -19,6 +19,7 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -29,9 +30,10 @@ import (
 type Logz struct{}
 
 var (
-	_ translation.Decodable    = (*Logz)(nil)
-	_ translation.Translatable = (*Logz)(nil)
-	_ translation.Addressable  = (*Logz)(nil)
+	_ translation.Decodable      = (*Logz)(nil)
+	_ translation.Translatable   = (*Logz)(nil)
+	_ translation.Introspectable = (*Logz)(nil)
+	_ translation.Addressable    = (*Logz)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -92,3 +94,12 @@ func (*Logz) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*Logz) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "LogzTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -19,6 +19,7 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -29,9 +30,10 @@ import (
 type Sendgrid struct{}
 
 var (
-	_ translation.Decodable    = (*Sendgrid)(nil)
-	_ translation.Translatable = (*Sendgrid)(nil)
-	_ translation.Addressable  = (*Sendgrid)(nil)
+	_ translation.Decodable      = (*Sendgrid)(nil)
+	_ translation.Translatable   = (*Sendgrid)(nil)
+	_ translation.Introspectable = (*Sendgrid)(nil)
+	_ translation.Addressable    = (*Sendgrid)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -134,3 +136,12 @@ func (*Sendgrid) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*Sendgrid) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "SendgridTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
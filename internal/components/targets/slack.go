@@ -19,6 +19,7 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -29,9 +30,10 @@ import (
 type Slack struct{}
 
 var (
-	_ translation.Decodable    = (*Slack)(nil)
-	_ translation.Translatable = (*Slack)(nil)
-	_ translation.Addressable  = (*Slack)(nil)
+	_ translation.Decodable      = (*Slack)(nil)
+	_ translation.Translatable   = (*Slack)(nil)
+	_ translation.Introspectable = (*Slack)(nil)
+	_ translation.Addressable    = (*Slack)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -84,3 +86,12 @@ func (*Slack) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*Slack) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "SlackTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -18,6 +18,7 @@ package targets
 
 import (
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -27,8 +28,9 @@ import (
 type Sockeye struct{}
 
 var (
-	_ translation.Translatable = (*Sockeye)(nil)
-	_ translation.Addressable  = (*Sockeye)(nil)
+	_ translation.Translatable   = (*Sockeye)(nil)
+	_ translation.Introspectable = (*Sockeye)(nil)
+	_ translation.Addressable    = (*Sockeye)(nil)
 )
 
 // Manifests implements translation.Translatable.
@@ -41,12 +43,19 @@ func (*Sockeye) Manifests(id string, _, _ cty.Value, _ globals.Accessor) []inter
 
 	const public = true
 
-	ksvc := k8s.NewKnService(k8s.RFC1123Name(id), img, public)
+	ksvc := k8s.NewKnService(k8s.RFC1035LabelName(id), img, public)
 
 	return append(manifests, ksvc)
 }
 
 // Address implements translation.Addressable.
 func (*Sockeye) Address(id string, _, _ cty.Value) cty.Value {
-	return k8s.NewDestination(k8s.APIServing, "Service", k8s.RFC1123Name(id))
+	return k8s.NewDestination(k8s.APIServing, "Service", k8s.RFC1035LabelName(id))
+}
+
+// Kinds implements translation.Introspectable.
+func (*Sockeye) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIServing, "Service"),
+	}
 }
@@ -19,6 +19,7 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -29,9 +30,10 @@ import (
 type Splunk struct{}
 
 var (
-	_ translation.Decodable    = (*Splunk)(nil)
-	_ translation.Translatable = (*Splunk)(nil)
-	_ translation.Addressable  = (*Splunk)(nil)
+	_ translation.Decodable      = (*Splunk)(nil)
+	_ translation.Translatable   = (*Splunk)(nil)
+	_ translation.Introspectable = (*Splunk)(nil)
+	_ translation.Addressable    = (*Splunk)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -111,3 +113,12 @@ func (*Splunk) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*Splunk) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "SplunkTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -19,6 +19,7 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -29,9 +30,10 @@ import (
 type Twilio struct{}
 
 var (
-	_ translation.Decodable    = (*Twilio)(nil)
-	_ translation.Translatable = (*Twilio)(nil)
-	_ translation.Addressable  = (*Twilio)(nil)
+	_ translation.Decodable      = (*Twilio)(nil)
+	_ translation.Translatable   = (*Twilio)(nil)
+	_ translation.Introspectable = (*Twilio)(nil)
+	_ translation.Addressable    = (*Twilio)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -105,3 +107,12 @@ func (*Twilio) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*Twilio) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "TwilioTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
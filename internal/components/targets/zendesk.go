@@ -19,6 +19,7 @@ package targets
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -29,9 +30,10 @@ import (
 type Zendesk struct{}
 
 var (
-	_ translation.Decodable    = (*Zendesk)(nil)
-	_ translation.Translatable = (*Zendesk)(nil)
-	_ translation.Addressable  = (*Zendesk)(nil)
+	_ translation.Decodable      = (*Zendesk)(nil)
+	_ translation.Translatable   = (*Zendesk)(nil)
+	_ translation.Introspectable = (*Zendesk)(nil)
+	_ translation.Addressable    = (*Zendesk)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -108,3 +110,12 @@ func (*Zendesk) Address(id string, _, eventDst cty.Value) cty.Value {
 	}
 	return k8s.NewDestination(k8s.APIMessaging, "Channel", name)
 }
+
+// Kinds implements translation.Introspectable.
+func (*Zendesk) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APITargets, "ZendeskTarget"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
@@ -19,6 +19,7 @@ package transformers
 import (
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 	"til/internal/sdk/k8s"
@@ -28,9 +29,10 @@ import (
 type Bumblebee struct{}
 
 var (
-	_ translation.Decodable    = (*Bumblebee)(nil)
-	_ translation.Translatable = (*Bumblebee)(nil)
-	_ translation.Addressable  = (*Bumblebee)(nil)
+	_ translation.Decodable      = (*Bumblebee)(nil)
+	_ translation.Translatable   = (*Bumblebee)(nil)
+	_ translation.Introspectable = (*Bumblebee)(nil)
+	_ translation.Addressable    = (*Bumblebee)(nil)
 )
 
 // Spec implements translation.Decodable.
@@ -165,3 +167,12 @@ func parseBumblebeeOperations(operationVals []cty.Value) []interface{} {
 
 	return operations
 }
+
+// Kinds implements translation.Introspectable.
+func (*Bumblebee) Kinds() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		k8s.GroupVersionKind(k8s.APIFlow, "Transformation"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Channel"),
+		k8s.GroupVersionKind(k8s.APIMessaging, "Subscription"),
+	}
+}
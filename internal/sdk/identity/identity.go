@@ -0,0 +1,66 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/internal/sdk/k8s"
+)
+
+// Annotations which associate a Kubernetes ServiceAccount with a cloud
+// identity.
+const (
+	// IAM Roles for Service Accounts (EKS)
+	annotationAWSRoleARN = "eks.amazonaws.com/role-arn"
+	// Azure AD Workload Identity (AKS)
+	annotationAzureClientID = "azure.workload.identity/client-id"
+	// Workload Identity (GKE)
+	annotationGCloudSvcAccount = "iam.gke.io/gcp-service-account"
+)
+
+// ServiceAccountName returns the name of the ServiceAccount which carries the
+// cloud identity of the component with the given category (e.g. "source") and
+// identifier. Identifiers are only unique within a category, so the name
+// includes both.
+func ServiceAccountName(category, id string) string {
+	return k8s.RFC1123Name(category + "-" + id)
+}
+
+// NewServiceAccountAWS returns a ServiceAccount which assumes the AWS IAM role
+// with the given ARN.
+func NewServiceAccountAWS(name, roleARN string) *unstructured.Unstructured {
+	return k8s.NewServiceAccount(name, map[string]string{
+		annotationAWSRoleARN: roleARN,
+	})
+}
+
+// NewServiceAccountAzure returns a ServiceAccount which authenticates as the
+// Azure AD application or managed identity with the given client ID.
+func NewServiceAccountAzure(name, clientID string) *unstructured.Unstructured {
+	return k8s.NewServiceAccount(name, map[string]string{
+		annotationAzureClientID: clientID,
+	})
+}
+
+// NewServiceAccountGCloud returns a ServiceAccount which impersonates the
+// Google Cloud service account with the given email address.
+func NewServiceAccountGCloud(name, svcAccountEmail string) *unstructured.Unstructured {
+	return k8s.NewServiceAccount(name, map[string]string{
+		annotationGCloudSvcAccount: svcAccountEmail,
+	})
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+// APICore is the API version of the Kubernetes core API group.
+const APICore = "v1"
+
+// NewServiceAccount returns a new ServiceAccount with the given annotations.
+func NewServiceAccount(name string, annotations map[string]string) *unstructured.Unstructured {
+	validateDNS1123Subdomain(name)
+
+	sa := &unstructured.Unstructured{}
+
+	sa.SetAPIVersion(APICore)
+	sa.SetKind("ServiceAccount")
+	sa.SetName(name)
+
+	if len(annotations) > 0 {
+		sa.SetAnnotations(annotations)
+	}
+
+	return sa
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "til/internal/sdk/k8s"
+)
+
+func TestNewServiceAccount(t *testing.T) {
+	const name = "test"
+
+	sa := NewServiceAccount(name, map[string]string{
+		"example.com/identity": "test-identity",
+	})
+
+	expectSA := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": APICore,
+			"kind":       "ServiceAccount",
+			"metadata": map[string]interface{}{
+				"name": name,
+				"annotations": map[string]interface{}{
+					"example.com/identity": "test-identity",
+				},
+			},
+		},
+	}
+
+	if d := cmp.Diff(expectSA, sa); d != "" {
+		t.Errorf("Unexpected diff: (-:expect, +:got) %s", d)
+	}
+}
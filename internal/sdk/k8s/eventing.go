@@ -184,7 +184,7 @@ func MaybeAppendChannel(name string, manifests []interface{}, eventDst cty.Value
 		return manifests, eventDst
 	}
 
-	name = name + "-" + eventDst.GetAttr("ref").GetAttr("name").AsString()
+	name = RFC1123Name(name + "-" + eventDst.GetAttr("ref").GetAttr("name").AsString())
 
 	ch := NewChannel(name)
 	manifests = append(manifests, ch)
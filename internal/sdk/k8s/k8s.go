@@ -17,10 +17,13 @@ limitations under the License.
 package k8s
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
 
@@ -31,21 +34,76 @@ import (
 // The implementation is purposedly naive because it is assumed that this
 // function will only ever sanitize HCL identifiers, which already contain a
 // limited set of characters (see hclsyntax.ValidIdentifier).
+//
+// Names which exceed the maximum length of a DNS subdomain are shortened
+// deterministically (see ShortenName).
 func RFC1123Name(id string) string {
+	return ShortenName(sanitizeName(id), validation.DNS1123SubdomainMaxLength)
+}
+
+// RFC1035LabelName sanitizes the given input string, ensuring it is a valid
+// DNS label (as defined in RFC 1035) which can be used as the name of
+// Kubernetes objects that are exposed as DNS records, such as Services.
+//
+// Names which exceed the maximum length of a DNS label are shortened
+// deterministically (see ShortenName).
+func RFC1035LabelName(id string) string {
+	return ShortenName(sanitizeName(id), validation.DNS1035LabelMaxLength)
+}
+
+// sanitizeName replaces characters from HCL identifiers which are not allowed
+// in Kubernetes object names.
+func sanitizeName(id string) string {
 	return strings.ToLower(strings.ReplaceAll(id, "_", "-"))
 }
 
+// nameHashLength is the number of hexadecimal characters of the hash suffix
+// appended to shortened names.
+const nameHashLength = 8
+
+// ShortenName returns the given name unchanged if its length doesn't exceed
+// maxLen. Otherwise, the name is truncated and suffixed with a hash of the
+// original name, so that distinct long names remain distinct and a given name
+// is always shortened the same way.
+func ShortenName(name string, maxLen int) string {
+	if len(name) <= maxLen {
+		return name
+	}
+
+	h := sha256.Sum256([]byte(name))
+	suffix := hex.EncodeToString(h[:])[:nameHashLength]
+
+	prefix := strings.TrimRight(name[:maxLen-nameHashLength-1], "-.")
+
+	return prefix + "-" + suffix
+}
+
 // validateDNS1123Subdomain panics if the given value is not a valid Kubernetes
 // object name.
 // It is intended to be used inside constructors for throwing loud errors
 // whenever component implementations forget to sanitize their input.
 func validateDNS1123Subdomain(v string) {
-	errs := validation.IsDNS1123Subdomain(v)
-	if len(errs) > 0 {
-		panic(fmt.Errorf("%q is not a valid Kubernetes object name: %v", v, errs))
+	if errs := validation.IsDNS1123Subdomain(v); len(errs) > 0 {
+		panic(&Error{Err: fmt.Errorf("%q is not a valid Kubernetes object name: %s",
+			v, strings.Join(errs, "; "))})
+	}
+}
+
+// validateDNS1035Label panics if the given value is not a valid name for a
+// Kubernetes object that is exposed as a DNS record.
+func validateDNS1035Label(v string) {
+	if errs := validation.IsDNS1035Label(v); len(errs) > 0 {
+		panic(&Error{Err: fmt.Errorf("%q is not a valid Kubernetes Service name: %s",
+			v, strings.Join(errs, "; "))})
 	}
 }
 
+// GroupVersionKind returns the GroupVersionKind matching the given
+// group/version and kind.
+func GroupVersionKind(apiVersion, kind string) schema.GroupVersionKind {
+	return schema.FromAPIVersionAndKind(apiVersion, kind)
+}
+
 // Object wraps an instance of unstructured.Unstructured to expose convenience
 // field setters.
 type Object struct {
@@ -79,7 +137,7 @@ func (o *Object) Unstructured() *unstructured.Unstructured {
 func (o *Object) SetNestedField(value interface{}, fields ...string) {
 	err := unstructured.SetNestedField(o.u.Object, value, fields...)
 	if err != nil {
-		panic(err)
+		panic(&Error{Err: err})
 	}
 }
 
@@ -88,7 +146,7 @@ func (o *Object) SetNestedField(value interface{}, fields ...string) {
 func (o *Object) SetNestedSlice(value []interface{}, fields ...string) {
 	err := unstructured.SetNestedSlice(o.u.Object, value, fields...)
 	if err != nil {
-		panic(err)
+		panic(&Error{Err: err})
 	}
 }
 
@@ -97,6 +155,6 @@ func (o *Object) SetNestedSlice(value []interface{}, fields ...string) {
 func (o *Object) SetNestedMap(value map[string]interface{}, fields ...string) {
 	err := unstructured.SetNestedMap(o.u.Object, value, fields...)
 	if err != nil {
-		panic(err)
+		panic(&Error{Err: err})
 	}
 }
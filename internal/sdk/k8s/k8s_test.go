@@ -17,6 +17,7 @@ limitations under the License.
 package k8s_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -53,7 +54,7 @@ func TestNewObject(t *testing.T) {
 
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
-			defer handlePanic(t, tc.expectPanic)
+			defer handleErrorPanic(t, tc.expectPanic)
 
 			out := NewObject(apiVersion, kind, tc.name)
 
@@ -65,6 +66,89 @@ func TestNewObject(t *testing.T) {
 	}
 }
 
+func TestShortenName(t *testing.T) {
+	longName := strings.Repeat("a", 40) + "-" + strings.Repeat("b", 40)
+
+	testCases := map[string]struct {
+		name   string
+		maxLen int
+		expect string
+	}{
+		"name within limit": {
+			name:   "my-object",
+			maxLen: 63,
+			expect: "my-object",
+		},
+		"name at limit": {
+			name:   longName,
+			maxLen: len(longName),
+			expect: longName,
+		},
+		"name exceeding limit": {
+			name:   longName,
+			maxLen: 60,
+			expect: strings.Repeat("a", 40) + "-" + strings.Repeat("b", 10) + "-f5e4207a",
+		},
+		"truncated name ending with separator": {
+			name:   longName,
+			maxLen: 50,
+			expect: strings.Repeat("a", 40) + "-f5e4207a",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := ShortenName(tc.name, tc.maxLen)
+
+			if got != tc.expect {
+				t.Errorf("Expected %q, got %q", tc.expect, got)
+			}
+			if len(got) > tc.maxLen {
+				t.Errorf("Expected at most %d characters, got %d", tc.maxLen, len(got))
+			}
+		})
+	}
+}
+
+func TestRFC1035LabelName(t *testing.T) {
+	id := strings.Repeat("My_Target_", 10)
+
+	name := RFC1035LabelName(id)
+
+	if len(name) != 63 {
+		t.Errorf("Expected a name of 63 characters, got %d (%q)", len(name), name)
+	}
+	if !strings.HasPrefix(name, "my-target-my-target-") {
+		t.Errorf("Expected the name to start with the sanitized identifier, got %q", name)
+	}
+	if other := RFC1035LabelName(id + "x"); other == name {
+		t.Errorf("Expected distinct identifiers to yield distinct names, got %q twice", name)
+	}
+	if again := RFC1035LabelName(id); again != name {
+		t.Errorf("Expected name shortening to be deterministic, got %q and %q", name, again)
+	}
+
+	// does not panic
+	_ = NewKnService(name, "example.com/img", false)
+}
+
+// handleErrorPanic is like handlePanic, but also asserts that the value of the
+// panic is an Error.
+func handleErrorPanic(t *testing.T, expectPanic bool) {
+	t.Helper()
+
+	r := recover()
+	if r == nil {
+		return
+	}
+	if !expectPanic {
+		t.Fatal("Unexpected panic:", r)
+	}
+	if _, ok := r.(*Error); !ok {
+		t.Fatalf("Expected panic value of type %T, got %T", (*Error)(nil), r)
+	}
+}
+
 func handlePanic(t *testing.T, expectPanic bool) {
 	t.Helper()
 
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+// APINetworking is the API version of the Kubernetes networking API group.
+const APINetworking = "networking.k8s.io/v1"
+
+// NetworkPolicyPeer represents a networkingv1.NetworkPolicyPeer which selects
+// either the Pods matching the given labels in the namespace of the
+// NetworkPolicy, or all Pods in the given namespaces.
+type NetworkPolicyPeer struct {
+	PodLabels  map[string]string
+	Namespaces []string
+}
+
+// NewNetworkPolicy returns a new NetworkPolicy which only allows ingress
+// traffic from the given peers to the Pods matching the given labels. All
+// ingress traffic is denied if no peer is given.
+func NewNetworkPolicy(name string, podLabels map[string]string, from ...NetworkPolicyPeer) *unstructured.Unstructured {
+	validateDNS1123Subdomain(name)
+
+	np := &unstructured.Unstructured{}
+
+	np.SetAPIVersion(APINetworking)
+	np.SetKind("NetworkPolicy")
+	np.SetName(name)
+
+	_ = unstructured.SetNestedStringMap(np.Object, podLabels, "spec", "podSelector", "matchLabels")
+	_ = unstructured.SetNestedStringSlice(np.Object, []string{"Ingress"}, "spec", "policyTypes")
+
+	// an ingress rule without peers would allow all ingress traffic
+	if len(from) == 0 {
+		_ = unstructured.SetNestedSlice(np.Object, []interface{}{}, "spec", "ingress")
+		return np
+	}
+
+	peers := make([]interface{}, 0, len(from))
+	for _, p := range from {
+		peer := make(map[string]interface{}, 1)
+
+		if p.PodLabels != nil {
+			_ = unstructured.SetNestedStringMap(peer, p.PodLabels, "podSelector", "matchLabels")
+		}
+
+		if p.Namespaces != nil {
+			_ = unstructured.SetNestedSlice(peer, []interface{}{
+				map[string]interface{}{
+					"key":      "kubernetes.io/metadata.name",
+					"operator": "In",
+					"values":   stringsToInterfaces(p.Namespaces),
+				},
+			}, "namespaceSelector", "matchExpressions")
+		}
+
+		peers = append(peers, peer)
+	}
+
+	_ = unstructured.SetNestedSlice(np.Object, []interface{}{
+		map[string]interface{}{
+			"from": peers,
+		},
+	}, "spec", "ingress")
+
+	return np
+}
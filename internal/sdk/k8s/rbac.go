@@ -0,0 +1,91 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k8s
+
+import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+// APIRBAC is the API version of the Kubernetes RBAC API group.
+const APIRBAC = "rbac.authorization.k8s.io/v1"
+
+// PolicyRule represents a rbacv1.PolicyRule.
+type PolicyRule struct {
+	APIGroups []string
+	Resources []string
+	Verbs     []string
+}
+
+// NewRole returns a new Role which grants the given permissions.
+func NewRole(name string, rules ...PolicyRule) *unstructured.Unstructured {
+	validateDNS1123Subdomain(name)
+
+	r := &unstructured.Unstructured{}
+
+	r.SetAPIVersion(APIRBAC)
+	r.SetKind("Role")
+	r.SetName(name)
+
+	rulesVal := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		rulesVal = append(rulesVal, map[string]interface{}{
+			"apiGroups": stringsToInterfaces(rule.APIGroups),
+			"resources": stringsToInterfaces(rule.Resources),
+			"verbs":     stringsToInterfaces(rule.Verbs),
+		})
+	}
+	_ = unstructured.SetNestedSlice(r.Object, rulesVal, "rules")
+
+	return r
+}
+
+// NewRoleBinding returns a new RoleBinding which grants the permissions of the
+// given Role to the given ServiceAccounts.
+func NewRoleBinding(name, roleName string, serviceAccounts ...string) *unstructured.Unstructured {
+	validateDNS1123Subdomain(name)
+
+	rb := &unstructured.Unstructured{}
+
+	rb.SetAPIVersion(APIRBAC)
+	rb.SetKind("RoleBinding")
+	rb.SetName(name)
+
+	_ = unstructured.SetNestedMap(rb.Object, map[string]interface{}{
+		"apiGroup": "rbac.authorization.k8s.io",
+		"kind":     "Role",
+		"name":     roleName,
+	}, "roleRef")
+
+	subjects := make([]interface{}, 0, len(serviceAccounts))
+	for _, sa := range serviceAccounts {
+		subjects = append(subjects, map[string]interface{}{
+			"kind": "ServiceAccount",
+			"name": sa,
+		})
+	}
+	_ = unstructured.SetNestedSlice(rb.Object, subjects, "subjects")
+
+	return rb
+}
+
+// stringsToInterfaces returns a copy of the given slice of strings, in a
+// format that can be passed to unstructured.SetNestedSlice.
+func stringsToInterfaces(ss []string) []interface{} {
+	out := make([]interface{}, len(ss))
+	for i, s := range ss {
+		out[i] = s
+	}
+	return out
+}
@@ -27,7 +27,7 @@ const APIServing = "serving.knative.dev/v1"
 
 // NewKnService returns a new Knative Service.
 func NewKnService(name, image string, public bool, opts ...KnServiceOption) *unstructured.Unstructured {
-	validateDNS1123Subdomain(name)
+	validateDNS1035Label(name)
 
 	s := &unstructured.Unstructured{}
 
@@ -16,9 +16,30 @@ limitations under the License.
 
 package k8s
 
+import "github.com/zclconf/go-cty/cty"
+
 const (
 	APISources = "sources.triggermesh.io/v1alpha1"
 	APITargets = "targets.triggermesh.io/v1alpha1"
 	APIFlow    = "flow.triggermesh.io/v1alpha1"
 	APIExt     = "extensions.triggermesh.io/v1alpha1"
 )
+
+// ValueFromField returns a TriggerMesh "ValueFromField" which contains either
+// the given literal string or a reference to a key of a Kubernetes Secret,
+// depending on the type of the given value.
+// Panics if the given value is neither a string nor a secret reference.
+func ValueFromField(v cty.Value) map[string]interface{} {
+	if IsSecretKeySelector(v) {
+		return map[string]interface{}{
+			"valueFromSecret": map[string]interface{}{
+				"name": v.GetAttr("name").AsString(),
+				"key":  v.GetAttr("key").AsString(),
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"value": v.AsString(),
+	}
+}
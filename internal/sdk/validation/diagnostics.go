@@ -18,6 +18,7 @@ package validation
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
@@ -46,3 +47,18 @@ func invalidCEContextAttrDiagnostic(v string) *hcl.Diagnostic {
 			"('0' to '9') from the ASCII character set.",
 	}
 }
+
+// exactlyOneOfDiagnostic returns a validation diagnostic which indicates that
+// not exactly one of the given attributes is set.
+func exactlyOneOfDiagnostic(attrs []string) *hcl.Diagnostic {
+	quoted := make([]string, len(attrs))
+	for i, a := range attrs {
+		quoted[i] = strconv.Quote(a)
+	}
+
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  diagSummaryValidation,
+		Detail:   "Exactly one of the following attributes must be set: " + strings.Join(quoted, ", ") + ".",
+	}
+}
@@ -21,6 +21,8 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
+
+	"til/lang/k8s"
 )
 
 // ValidateSpecFunc is the signature of a validation function used in hcldec.ValidateSpec
@@ -41,6 +43,46 @@ func IsInt(v cty.Value) hcl.Diagnostics {
 	return diags
 }
 
+// IsStringOrSecretRef is a ValidateSpecFunc which asserts that the given value
+// is either a string or a reference to a key of a Kubernetes Secret.
+func IsStringOrSecretRef(v cty.Value) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	if v.IsNull() {
+		return diags
+	}
+	if !(v.Type() == cty.String || k8s.IsSecretKeySelector(v)) {
+		diags = diags.Append(wrongTypeDiagnostic(v, "string or secret reference"))
+	}
+
+	return diags
+}
+
+// ExactlyOneOf returns a ValidateSpecFunc which asserts that exactly one of
+// the given attributes of an object is set.
+func ExactlyOneOf(attrs ...string) ValidateSpecFunc {
+	return func(v cty.Value) hcl.Diagnostics {
+		var diags hcl.Diagnostics
+
+		if v.IsNull() || !v.IsKnown() {
+			return diags
+		}
+
+		var numSet int
+		for _, a := range attrs {
+			if v.Type().HasAttribute(a) && !v.GetAttr(a).IsNull() {
+				numSet++
+			}
+		}
+
+		if numSet != 1 {
+			diags = diags.Append(exactlyOneOfDiagnostic(attrs))
+		}
+
+		return diags
+	}
+}
+
 // isInt64 returns whether the given cty.Number value can be represented as an int64.
 func isInt64(v *big.Float) bool {
 	bigInt, accuracy := v.Int(nil)
@@ -77,6 +77,110 @@ func TestIsInt(t *testing.T) {
 	}
 }
 
+func TestIsStringOrSecretRef(t *testing.T) {
+	testCases := map[string]struct {
+		in        cty.Value
+		expectErr bool
+	}{
+		"string": {
+			in:        cty.StringVal("pa$$w0rd"),
+			expectErr: false,
+		},
+		"secret reference": {
+			in: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("my-secret"),
+				"key":  cty.StringVal("password"),
+			}),
+			expectErr: false,
+		},
+		"null value": {
+			in:        cty.NullVal(cty.DynamicPseudoType),
+			expectErr: false,
+		},
+		"object which is not a secret reference": {
+			in: cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("my-secret"),
+			}),
+			expectErr: true,
+		},
+		"not a string": {
+			in:        cty.NumberIntVal(42),
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			diags := IsStringOrSecretRef(tc.in)
+
+			if tc.expectErr && diags == nil {
+				t.Error("Expected validation to fail")
+			}
+			if !tc.expectErr && diags != nil {
+				t.Error("Expected validation to pass. Got diagnostic:", diags)
+			}
+		})
+	}
+}
+
+func TestExactlyOneOf(t *testing.T) {
+	objType := cty.Object(map[string]cty.Type{
+		"credentials": cty.String,
+		"iam_role":    cty.String,
+	})
+
+	testCases := map[string]struct {
+		in        cty.Value
+		expectErr bool
+	}{
+		"first attribute set": {
+			in: cty.ObjectVal(map[string]cty.Value{
+				"credentials": cty.StringVal("my-secret"),
+				"iam_role":    cty.NullVal(cty.String),
+			}),
+			expectErr: false,
+		},
+		"second attribute set": {
+			in: cty.ObjectVal(map[string]cty.Value{
+				"credentials": cty.NullVal(cty.String),
+				"iam_role":    cty.StringVal("arn:aws:iam::123456789012:role/my-role"),
+			}),
+			expectErr: false,
+		},
+		"both attributes set": {
+			in: cty.ObjectVal(map[string]cty.Value{
+				"credentials": cty.StringVal("my-secret"),
+				"iam_role":    cty.StringVal("arn:aws:iam::123456789012:role/my-role"),
+			}),
+			expectErr: true,
+		},
+		"no attribute set": {
+			in: cty.ObjectVal(map[string]cty.Value{
+				"credentials": cty.NullVal(cty.String),
+				"iam_role":    cty.NullVal(cty.String),
+			}),
+			expectErr: true,
+		},
+		"null value": {
+			in:        cty.NullVal(objType),
+			expectErr: false,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			diags := ExactlyOneOf("credentials", "iam_role")(tc.in)
+
+			if tc.expectErr && diags == nil {
+				t.Error("Expected validation to fail")
+			}
+			if !tc.expectErr && diags != nil {
+				t.Error("Expected validation to pass. Got diagnostic:", diags)
+			}
+		})
+	}
+}
+
 func TestIsCEContextAttribute(t *testing.T) {
 	testCases := map[string]struct {
 		in        cty.Value
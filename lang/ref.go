@@ -119,6 +119,12 @@ func ParseBlockReference(attr hcl.Traversal) (*addr.Reference, hcl.Diagnostics)
 	return ref, diags
 }
 
+// IsReferenceable returns whether blocks of the given component category can be
+// referenced inside expressions.
+func IsReferenceable(cat config.ComponentCategory) bool {
+	return referenceableTypes().Has(cat)
+}
+
 // referenceableTypes returns a set containing the block types that can be
 // referenced inside expressions.
 func referenceableTypes() compCatSet {
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"til/config"
+	"til/config/addr"
+	"til/config/file"
+	"til/core"
+	"til/fs"
+	"til/lang"
+)
+
+// analysis is the result of the analysis of a Bridge description.
+type analysis struct {
+	// Decoded Bridge. Nil if the document couldn't be decoded.
+	bridge *config.Bridge
+
+	// Diagnostics produced by the validation pipeline.
+	diags hcl.Diagnostics
+
+	// Referenceable components indexed by address.
+	refMap core.ReferenceMap
+	// References to other components found in the document.
+	refs []*addr.Reference
+}
+
+// analyze runs the given Bridge description through the same validation
+// pipeline as the "validate" command, and indexes references between
+// components.
+//
+// The text is served from an in-memory file system so that unsaved changes
+// can be analyzed.
+func analyze(path string, text []byte) *analysis {
+	a := &analysis{}
+
+	p := &file.Parser{
+		Parser: hclparse.NewParser(),
+		FS:     fs.MemFS{path: text},
+	}
+
+	brg, diags := p.LoadBridge(path)
+	a.diags = diags
+	if brg == nil {
+		return a
+	}
+	a.bridge = brg
+
+	cctx, diags := core.NewContext(brg)
+	a.diags = a.diags.Extend(diags)
+	if cctx == nil {
+		return a
+	}
+
+	// The graph is built regardless of previous errors to allow
+	// navigating between components of a partially valid document.
+	g, graphDiags := cctx.Graph()
+
+	a.refMap = core.NewReferenceMap(g.Vertices())
+
+	for _, v := range g.Vertices() {
+		rfr, ok := v.(core.ReferencerVertex)
+		if !ok {
+			continue
+		}
+
+		// diagnostics are already reported by the graph builder
+		refs, _ := rfr.References()
+		a.refs = append(a.refs, refs...)
+	}
+
+	if dlv := brg.Delivery; dlv != nil && dlv.DeadLetterSink != nil {
+		if ref, _ := lang.ParseBlockReference(dlv.DeadLetterSink); ref != nil {
+			a.refs = append(a.refs, ref)
+		}
+	}
+
+	if a.diags.HasErrors() {
+		a.diags = a.diags.Extend(graphDiags)
+		return a
+	}
+
+	// Generate() builds its own graph, so its diagnostics include the
+	// ones returned while building the graph above.
+	_, diags = cctx.Generate()
+	a.diags = a.diags.Extend(diags)
+
+	return a
+}
+
+// referenceAt returns the reference located at the given byte offset, if any.
+func (a *analysis) referenceAt(offs int) *addr.Reference {
+	for _, ref := range a.refs {
+		if containsOffset(ref.SourceRange, offs) {
+			return ref
+		}
+	}
+	return nil
+}
+
+// componentAt returns the referenceable component whose block header is
+// located at the given byte offset, if any.
+func (a *analysis) componentAt(offs int) (addr.MessagingComponent, bool) {
+	for _, v := range a.refMap {
+		cmpAddr := v.(core.MessagingComponentVertex).ComponentAddr()
+		if containsOffset(cmpAddr.SourceRange, offs) {
+			return cmpAddr, true
+		}
+	}
+	return addr.MessagingComponent{}, false
+}
+
+// component returns the referenceable component with the given address, if
+// it exists.
+func (a *analysis) component(key string) (addr.MessagingComponent, bool) {
+	v, ok := a.refMap[key]
+	if !ok {
+		return addr.MessagingComponent{}, false
+	}
+	// all referenceable vertices are messaging components, so this type
+	// assertion is assumed to be safe
+	return v.(core.MessagingComponentVertex).ComponentAddr(), true
+}
+
+// referencesTo returns all references to the component with the given
+// address.
+func (a *analysis) referencesTo(key string) []*addr.Reference {
+	var refs []*addr.Reference
+	for _, ref := range a.refs {
+		if ref.Subject.Addr() == key {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// containsOffset returns whether the given byte offset is located within the
+// hcl.Range. The end of the range is inclusive, so that a cursor placed right
+// after a word is considered to be on that word.
+func containsOffset(r hcl.Range, offs int) bool {
+	return offs >= r.Start.Byte && offs <= r.End.Byte
+}
+
+// referenceKey returns the address of the given referenceable component.
+func referenceKey(cmp addr.MessagingComponent) string {
+	return cmp.Category.String() + "." + cmp.Identifier
+}
@@ -0,0 +1,325 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"til/catalog"
+	"til/config"
+	"til/lang"
+)
+
+// complete returns completion proposals for the given byte offset in text.
+func complete(text []byte, offs int) []CompletionItem {
+	ctx := contextAt(text, offs)
+
+	switch {
+	case ctx.isExpression():
+		return completeExpression(text, offs)
+
+	case len(ctx.blocks) == 0 && (ctx.labelIndex() == 0 || ctx.isAfterBlockType()):
+		return completeComponentTypes(ctx.firstWord())
+
+	case len(ctx.lineTokens) <= 1 && (len(ctx.lineTokens) == 0 || ctx.firstWord() != ""):
+		return completeBody(ctx.blocks)
+	}
+
+	return nil
+}
+
+// refPrefixRegexp matches a partial reference to a component (e.g.
+// "target.my_"), located at the end of a line.
+var refPrefixRegexp = regexp.MustCompile(`([A-Za-z_][\w-]*)\.[\w-]*$`)
+
+// completeExpression returns completion proposals for a position located
+// inside an expression.
+func completeExpression(text []byte, offs int) []CompletionItem {
+	lineStart := strings.LastIndexByte(string(text[:offs]), '\n') + 1
+
+	if m := refPrefixRegexp.FindSubmatch(text[lineStart:offs]); m != nil {
+		cat := config.AsComponentCategory(string(m[1]))
+		if !lang.IsReferenceable(cat) {
+			return nil
+		}
+
+		var items []CompletionItem
+		for _, cmp := range declaredComponents(text) {
+			if cmp.category != cat {
+				continue
+			}
+			items = append(items, CompletionItem{
+				Label:  cmp.identifier,
+				Kind:   CompletionItemKindReference,
+				Detail: cat.String() + " " + `"` + cmp.typ + `"`,
+			})
+		}
+		return items
+	}
+
+	var items []CompletionItem
+
+	for _, cat := range catalog.Categories() {
+		if !lang.IsReferenceable(cat) {
+			continue
+		}
+		items = append(items, CompletionItem{
+			Label:  cat.String(),
+			Kind:   CompletionItemKindModule,
+			Detail: "Reference to a " + cat.String() + " component",
+		})
+	}
+
+	fns := lang.Functions("", nil)
+	fnNames := make([]string, 0, len(fns))
+	for n := range fns {
+		fnNames = append(fnNames, n)
+	}
+	sort.Strings(fnNames)
+
+	for _, n := range fnNames {
+		params := fns[n].Params()
+		paramNames := make([]string, len(params))
+		for i, p := range params {
+			paramNames[i] = p.Name
+		}
+
+		items = append(items, CompletionItem{
+			Label:      n,
+			Kind:       CompletionItemKindFunction,
+			Detail:     n + "(" + strings.Join(paramNames, ", ") + ")",
+			InsertText: n + "(",
+		})
+	}
+
+	return items
+}
+
+// completeComponentTypes returns completion proposals for the type label of
+// a block of the given type.
+func completeComponentTypes(blkType string) []CompletionItem {
+	cat := config.AsComponentCategory(blkType)
+	if cat == config.CategoryUnknown {
+		return nil
+	}
+
+	var items []CompletionItem
+	for _, typ := range catalog.Types(cat) {
+		items = append(items, CompletionItem{
+			Label:         typ,
+			Kind:          CompletionItemKindClass,
+			Detail:        cat.String() + " type",
+			Documentation: componentTypeDoc(cat, typ),
+		})
+	}
+
+	return items
+}
+
+// completeBody returns completion proposals for the names of attributes and
+// blocks which can appear in the body of the innermost given block.
+func completeBody(blocks []*blockFrame) []CompletionItem {
+	if len(blocks) == 0 {
+		return topLevelBlockItems()
+	}
+
+	body := bodyOf(blocks)
+	if body == nil {
+		return nil
+	}
+
+	var items []CompletionItem
+
+	for _, a := range body.Attributes {
+		items = append(items, CompletionItem{
+			Label:      a.Name,
+			Kind:       CompletionItemKindProperty,
+			Detail:     catalog.TypeString(a.Type) + ", " + catalog.AttributeAnnotation(a),
+			InsertText: a.Name + " = ",
+		})
+	}
+
+	for _, blk := range body.Blocks {
+		items = append(items, CompletionItem{
+			Label:  blk.TypeName,
+			Kind:   CompletionItemKindField,
+			Detail: "block, " + catalog.BlockAnnotation(blk),
+		})
+	}
+
+	return items
+}
+
+// topLevelBlockItems returns completion proposals for the block types that
+// can appear at the top level of a Bridge description.
+func topLevelBlockItems() []CompletionItem {
+	items := make([]CompletionItem, 0, len(config.BridgeSchema.Blocks))
+
+	for _, blk := range config.BridgeSchema.Blocks {
+		items = append(items, CompletionItem{
+			Label: blk.Type,
+			Kind:  CompletionItemKindKeyword,
+			Documentation: &MarkupContent{
+				Kind:  MarkupKindMarkdown,
+				Value: blockTypeDescription(blk.Type),
+			},
+		})
+	}
+
+	return items
+}
+
+// bodyOf returns the description of the body of the innermost given block.
+// Returns nil if that body can't be described.
+func bodyOf(blocks []*blockFrame) *catalog.Body {
+	root := blocks[0]
+
+	var body *catalog.Body
+
+	switch root.typ {
+	case config.BlkBridge:
+		body = bridgeBody()
+
+	default:
+		cat := config.AsComponentCategory(root.typ)
+		if cat == config.CategoryUnknown || len(root.labels) == 0 {
+			return nil
+		}
+
+		cmpType, ok := catalog.Lookup(cat, root.labels[0])
+		if !ok {
+			return nil
+		}
+		body = cmpType.BlockBody()
+	}
+
+	for _, f := range blocks[1:] {
+		if f.isExpr() {
+			return nil
+		}
+
+		blk := body.Block(f.typ)
+		if blk == nil {
+			return nil
+		}
+		body = blk.Body
+	}
+
+	return body
+}
+
+// bridgeBody returns a description of the body of the "bridge" block.
+func bridgeBody() *catalog.Body {
+	delivery := &catalog.Body{}
+	for _, a := range config.DeliveryBlockSchema.Attributes {
+		delivery.Attributes = append(delivery.Attributes, &catalog.Attribute{
+			Name:     a.Name,
+			Type:     deliveryAttributeType(a.Name),
+			Required: a.Required,
+		})
+	}
+
+	rbac := &catalog.Body{}
+	for _, a := range config.RBACBlockSchema.Attributes {
+		rbac.Attributes = append(rbac.Attributes, &catalog.Attribute{
+			Name:     a.Name,
+			Type:     cty.String,
+			Required: a.Required,
+		})
+	}
+
+	var attrs []*catalog.Attribute
+	for _, a := range config.BridgeBlockSchema.Attributes {
+		attrs = append(attrs, &catalog.Attribute{
+			Name:     a.Name,
+			Type:     cty.String,
+			Required: a.Required,
+		})
+	}
+
+	return &catalog.Body{
+		Attributes: attrs,
+		Blocks: []*catalog.Block{{
+			TypeName: config.BlkDelivery,
+			Nesting:  catalog.NestingSingle,
+			Body:     delivery,
+		}, {
+			TypeName: config.BlkRBAC,
+			Nesting:  catalog.NestingSingle,
+			Body:     rbac,
+		}},
+	}
+}
+
+// declaredComponent is a component declared in a Bridge description.
+type declaredComponent struct {
+	category   config.ComponentCategory
+	typ        string
+	identifier string
+	rng        hcl.Range
+}
+
+// declaredComponents returns all components declared at the top level of the
+// given text. Declarations are discovered by lexing the text, so that they
+// remain available while the document is being edited and isn't
+// syntactically valid.
+func declaredComponents(text []byte) []*declaredComponent {
+	toks, _ := hclsyntax.LexConfig(text, "", hcl.InitialPos)
+
+	var cmps []*declaredComponent
+
+	depth := 0
+	var line hclsyntax.Tokens
+
+	for _, tok := range toks {
+		switch tok.Type {
+		case hclsyntax.TokenNewline:
+			line = nil
+			continue
+
+		case hclsyntax.TokenOBrace:
+			if depth == 0 {
+				f := newBlockFrame(line)
+				cat := config.AsComponentCategory(f.typ)
+				if cat != config.CategoryUnknown && len(f.labels) == 2 {
+					cmps = append(cmps, &declaredComponent{
+						category:   cat,
+						typ:        f.labels[0],
+						identifier: f.labels[1],
+						rng:        hcl.RangeBetween(line[0].Range, tok.Range),
+					})
+				}
+			}
+			depth++
+
+		case hclsyntax.TokenCBrace:
+			if depth > 0 {
+				depth--
+			}
+		}
+
+		line = append(line, tok)
+	}
+
+	return cmps
+}
@@ -0,0 +1,24 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lsp implements a server for the Language Server Protocol, which
+// provides editors with language features for Bridge Description Files.
+//
+// The server communicates with its client using JSON-RPC 2.0 messages over a
+// stream (typically stdin/stdout), as described by the base protocol of the
+// specification:
+// https://microsoft.github.io/language-server-protocol/specifications/specification-current/
+package lsp
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+import (
+	"bytes"
+	"net/url"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// document is a text document opened in the client.
+type document struct {
+	uri  string
+	path string
+
+	version int
+	text    []byte
+
+	// result of the analysis of the current version of the document
+	analysis *analysis
+}
+
+// uriToPath returns the file system path corresponding to the given
+// document URI.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		// not a file (e.g. untitled buffer), the URI itself is a
+		// decent unique identifier
+		return uri
+	}
+
+	return filepath.FromSlash(u.Path)
+}
+
+// pathToURI returns the document URI corresponding to the given file system
+// path.
+func pathToURI(path string) string {
+	if !filepath.IsAbs(path) {
+		return path
+	}
+
+	u := &url.URL{
+		Scheme: "file",
+		Path:   filepath.ToSlash(path),
+	}
+	return u.String()
+}
+
+// offsetAt returns the byte offset in text that corresponds to the given
+// Position. Positions beyond the end of a line or of the text are clamped.
+func offsetAt(text []byte, p Position) int {
+	offs := 0
+
+	for line := 0; line < p.Line; line++ {
+		i := bytes.IndexByte(text[offs:], '\n')
+		if i < 0 {
+			return len(text)
+		}
+		offs += i + 1
+	}
+
+	for char := 0; char < p.Character && offs < len(text); {
+		r, size := utf8.DecodeRune(text[offs:])
+		if r == '\n' {
+			break
+		}
+		offs += size
+		char += utf16Len(r)
+	}
+
+	return offs
+}
+
+// positionAt returns the Position that corresponds to the given byte offset
+// in text.
+func positionAt(text []byte, offs int) Position {
+	if offs > len(text) {
+		offs = len(text)
+	}
+
+	var p Position
+
+	for i := 0; i < offs; {
+		r, size := utf8.DecodeRune(text[i:])
+		if r == '\n' {
+			p.Line++
+			p.Character = 0
+		} else {
+			p.Character += utf16Len(r)
+		}
+		i += size
+	}
+
+	return p
+}
+
+// rangeOf returns the Range that corresponds to the given hcl.Range in text.
+func rangeOf(text []byte, r hcl.Range) Range {
+	return Range{
+		Start: positionAt(text, r.Start.Byte),
+		End:   positionAt(text, r.End.Byte),
+	}
+}
+
+// utf16Len returns the number of UTF-16 code units required to encode r.
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2 // surrogate pair
+	}
+	return 1
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"til/catalog"
+	"til/config"
+	"til/lang/k8s"
+)
+
+// hover returns documentation about the word located at the given byte
+// offset in text, along with the range of that word.
+func hover(text []byte, offs int, a *analysis) (string, *hcl.Range) {
+	ctx := contextAt(text, offs)
+	tok := ctx.token
+
+	if a != nil {
+		if ref := a.referenceAt(offs); ref != nil {
+			if cmp, ok := a.component(ref.Subject.Addr()); ok {
+				return fmt.Sprintf("```hcl\n%s %q %q\n```\nDeclared on line %d.",
+					cmp.Category, cmp.Type, cmp.Identifier, cmp.SourceRange.Start.Line), &ref.SourceRange
+			}
+		}
+	}
+
+	if tok == nil {
+		return "", nil
+	}
+	word := string(tok.Bytes)
+	rng := &tok.Range
+
+	// hovered word is the first word of its line
+	isFirstWord := len(ctx.lineTokens) == 0 ||
+		len(ctx.lineTokens) == 1 && ctx.lineTokens[0].Range == tok.Range
+
+	switch {
+	case len(ctx.blocks) == 0 && isFirstWord:
+		return blockTypeDescription(word), rng
+
+	case len(ctx.blocks) == 0 && ctx.labelIndex() == 0:
+		cat := config.AsComponentCategory(ctx.firstWord())
+		if doc := componentTypeDoc(cat, word); doc != nil {
+			return doc.Value, rng
+		}
+
+	case len(ctx.blocks) > 0 && isFirstWord && !ctx.isExpression():
+		body := bodyOf(ctx.blocks)
+		if body == nil {
+			return "", nil
+		}
+		if attr := body.Attribute(word); attr != nil {
+			return fmt.Sprintf("```hcl\n%s = %s\n```\nAttribute (%s).",
+				attr.Name, catalog.TypeString(attr.Type), catalog.AttributeAnnotation(attr)), rng
+		}
+		if blk := body.Block(word); blk != nil {
+			return fmt.Sprintf("```hcl\n%s {}\n```\nBlock (%s).",
+				blk.TypeName, catalog.BlockAnnotation(blk)), rng
+		}
+	}
+
+	return "", nil
+}
+
+// componentTypeDoc returns the documentation of the given component type.
+// Returns nil if the component type doesn't exist.
+func componentTypeDoc(cat config.ComponentCategory, typ string) *MarkupContent {
+	cmpType, ok := catalog.Lookup(cat, typ)
+	if !ok {
+		return nil
+	}
+
+	var desc strings.Builder
+	desc.WriteString("```hcl\n")
+	catalog.Describe(&desc, cmpType)
+	desc.WriteString("```\n")
+
+	return &MarkupContent{
+		Kind:  MarkupKindMarkdown,
+		Value: desc.String(),
+	}
+}
+
+// blockTypeDescription returns a description of the given top-level block
+// type.
+func blockTypeDescription(blkType string) string {
+	switch blkType {
+	case config.BlkBridge:
+		return "**bridge**: global settings of the Bridge, such as its identifier, delivery options and component defaults."
+	case config.BlkChannel:
+		return "**channel**: a component that receives events and delivers them to one or more destinations."
+	case config.BlkRouter:
+		return "**router**: a component that dispatches events to destinations based on their content."
+	case config.BlkTransf:
+		return "**transformer**: a component that modifies events and sends the result to a destination."
+	case config.BlkSource:
+		return "**source**: a component that ingests events from an external system."
+	case config.BlkTarget:
+		return "**target**: a component that delivers events to an external system."
+	case config.BlkVariable:
+		return "**variable**: an input variable, referenced as `var.<name>` in the `enabled` attribute of components."
+	default:
+		return ""
+	}
+}
+
+// deliveryAttributeType returns the type of the given attribute of the
+// "bridge.delivery" block.
+func deliveryAttributeType(name string) cty.Type {
+	switch name {
+	case config.AttrRetries:
+		return cty.Number
+	case config.AttrDeadLetterSink:
+		return k8s.DestinationCty
+	default:
+		return cty.DynamicPseudoType
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// jsonrpcVersion is the version of the JSON-RPC protocol used by LSP.
+const jsonrpcVersion = "2.0"
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+
+	// LSP-specific
+	codeServerNotInitialized = -32002
+)
+
+// message is a JSON-RPC message, which can be either a request, a
+// notification or a response.
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+// isNotification returns whether the message is a notification, to which no
+// response should be sent.
+func (m *message) isNotification() bool {
+	return m.ID == nil
+}
+
+// responseError is the error object of a JSON-RPC response.
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+var _ error = (*responseError)(nil)
+
+// Error implements error.
+func (e *responseError) Error() string {
+	return e.Message
+}
+
+// conn reads and writes JSON-RPC messages framed with the headers of LSP's
+// base protocol.
+type conn struct {
+	r *textproto.Reader
+
+	mu sync.Mutex // guards writes
+	w  io.Writer
+}
+
+// newConn returns a conn that reads from r and writes to w.
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		r: textproto.NewReader(bufio.NewReader(r)),
+		w: w,
+	}
+}
+
+// errMissingContentLength is returned when a message header doesn't contain
+// a valid Content-Length field.
+var errMissingContentLength = errors.New("missing or invalid Content-Length header")
+
+// read reads the next message from the stream.
+func (c *conn) read() (*message, error) {
+	hdr, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(hdr.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, errMissingContentLength
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r.R, body); err != nil {
+		return nil, err
+	}
+
+	msg := &message{}
+	if err := json.Unmarshal(body, msg); err != nil {
+		return nil, &responseError{
+			Code:    codeParseError,
+			Message: err.Error(),
+		}
+	}
+
+	return msg, nil
+}
+
+// write writes the given message to the stream.
+func (c *conn) write(msg *message) error {
+	msg.JSONRPC = jsonrpcVersion
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("serializing message: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = c.w.Write(body)
+	return err
+}
+
+// notify sends a notification with the given method and parameters.
+func (c *conn) notify(method string, params interface{}) error {
+	p, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("serializing notification parameters: %w", err)
+	}
+
+	return c.write(&message{
+		Method: method,
+		Params: p,
+	})
+}
+
+// reply sends a response to the request with the given ID.
+func (c *conn) reply(id *json.RawMessage, result interface{}, err error) error {
+	if id == nil {
+		// the ID of the request couldn't be determined
+		null := json.RawMessage("null")
+		id = &null
+	}
+
+	resp := &message{
+		ID: id,
+	}
+
+	if err != nil {
+		respErr := &responseError{}
+		if !errors.As(err, &respErr) {
+			respErr = &responseError{
+				Code:    codeInternalError,
+				Message: err.Error(),
+			}
+		}
+		resp.Error = respErr
+	} else {
+		if result == nil {
+			result = json.RawMessage("null")
+		}
+		resp.Result = result
+	}
+
+	return c.write(resp)
+}
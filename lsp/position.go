@@ -0,0 +1,225 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// blockFrame represents a block which encloses a position in a document.
+type blockFrame struct {
+	// Type and labels of the block.
+	// Both are empty if the frame represents a brace-delimited expression
+	// (e.g. an object constructor) instead of a block.
+	typ    string
+	labels []string
+}
+
+// isExpr returns whether the frame is an expression rather than a block.
+func (f *blockFrame) isExpr() bool {
+	return f.typ == ""
+}
+
+// cursorContext describes the syntactic context of a position in a document.
+type cursorContext struct {
+	// Blocks enclosing the position, from the outermost to the innermost.
+	blocks []*blockFrame
+
+	// Tokens located between the beginning of the line and the position.
+	lineTokens hclsyntax.Tokens
+
+	// Token located under the position, if any.
+	token *hclsyntax.Token
+
+	// Byte offset of the position.
+	offs int
+}
+
+// contextAt returns the syntactic context of the given byte offset in text.
+//
+// The context is determined by lexing the text instead of parsing it, in
+// order to remain accurate while the document is being edited and isn't
+// syntactically valid.
+func contextAt(text []byte, offs int) *cursorContext {
+	toks, _ := hclsyntax.LexConfig(text, "", hcl.InitialPos)
+
+	ctx := &cursorContext{
+		offs: offs,
+	}
+
+	var line hclsyntax.Tokens
+
+	for i := range toks {
+		tok := &toks[i]
+
+		if tok.Range.Start.Byte < offs && offs <= tok.Range.End.Byte ||
+			ctx.token == nil && tok.Range.Start.Byte == offs && tok.Range.End.Byte > offs {
+
+			if isWordToken(tok) {
+				ctx.token = tok
+			}
+		}
+
+		if tok.Range.Start.Byte >= offs {
+			break
+		}
+
+		switch tok.Type {
+		case hclsyntax.TokenNewline:
+			line = nil
+			continue
+
+		case hclsyntax.TokenOBrace:
+			ctx.blocks = append(ctx.blocks, newBlockFrame(line))
+
+		case hclsyntax.TokenCBrace:
+			if n := len(ctx.blocks); n > 0 {
+				ctx.blocks = ctx.blocks[:n-1]
+			}
+		}
+
+		line = append(line, *tok)
+	}
+
+	// an open brace starts a new logical line
+	for i := len(line) - 1; i >= 0; i-- {
+		if line[i].Type == hclsyntax.TokenOBrace {
+			line = line[i+1:]
+			break
+		}
+	}
+	ctx.lineTokens = line
+
+	return ctx
+}
+
+// newBlockFrame returns the blockFrame opened by the given line of tokens,
+// which is assumed to end with an opening brace.
+func newBlockFrame(line hclsyntax.Tokens) *blockFrame {
+	f := &blockFrame{}
+
+	for i := 0; i < len(line); i++ {
+		tok := line[i]
+
+		switch tok.Type {
+		case hclsyntax.TokenIdent:
+			// labels can be written as identifiers
+			if f.typ != "" {
+				f.labels = append(f.labels, string(tok.Bytes))
+				continue
+			}
+			f.typ = string(tok.Bytes)
+
+		case hclsyntax.TokenOQuote:
+			if f.typ == "" {
+				return &blockFrame{}
+			}
+
+			var lbl string
+			for i++; i < len(line) && line[i].Type == hclsyntax.TokenQuotedLit; i++ {
+				lbl += string(line[i].Bytes)
+			}
+			f.labels = append(f.labels, lbl)
+
+		case hclsyntax.TokenOBrace:
+			// start of a nested frame on the same line
+			// (e.g. "block { nested {")
+			if i != len(line)-1 {
+				return newBlockFrame(line[i+1:])
+			}
+
+		default:
+			return &blockFrame{}
+		}
+	}
+
+	return f
+}
+
+// isWordToken returns whether the given token may represent a word which
+// is relevant to hovering (identifier or label).
+func isWordToken(tok *hclsyntax.Token) bool {
+	return tok.Type == hclsyntax.TokenIdent || tok.Type == hclsyntax.TokenQuotedLit
+}
+
+// isExpression returns whether the position is located inside an expression
+// (e.g. the right-hand side of an attribute).
+func (c *cursorContext) isExpression() bool {
+	for _, f := range c.blocks {
+		if f.isExpr() {
+			return true
+		}
+	}
+
+	for _, tok := range c.lineTokens {
+		if tok.Type == hclsyntax.TokenEqual {
+			return true
+		}
+	}
+
+	return false
+}
+
+// labelIndex returns the index of the block label the position is located
+// in, or -1 if the position isn't located inside a block label.
+func (c *cursorContext) labelIndex() int {
+	idx := -1
+	inLabel := false
+
+	for i, tok := range c.lineTokens {
+		switch tok.Type {
+		case hclsyntax.TokenIdent:
+			if i == 0 {
+				continue
+			}
+			// unquoted label, the position is inside it only if
+			// nothing separates them
+			idx++
+			inLabel = tok.Range.End.Byte >= c.offs
+		case hclsyntax.TokenOQuote:
+			idx++
+			inLabel = true
+		case hclsyntax.TokenCQuote:
+			inLabel = false
+		case hclsyntax.TokenQuotedLit:
+		default:
+			return -1
+		}
+	}
+
+	if !inLabel {
+		return -1
+	}
+	return idx
+}
+
+// isAfterBlockType returns whether the position is located after a block
+// type, and before any label.
+func (c *cursorContext) isAfterBlockType() bool {
+	return len(c.lineTokens) == 1 && c.firstWord() != "" &&
+		c.lineTokens[0].Range.End.Byte < c.offs
+}
+
+// firstWord returns the first word of the line, if the line starts with an
+// identifier.
+func (c *cursorContext) firstWord() string {
+	if len(c.lineTokens) == 0 || c.lineTokens[0].Type != hclsyntax.TokenIdent {
+		return ""
+	}
+	return string(c.lineTokens[0].Bytes)
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+// This file contains the subset of LSP structures used by the server.
+
+// Position in a text document, expressed as a zero-based line and a
+// zero-based character offset in UTF-16 code units.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range in a text document.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location inside a resource.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// TextDocumentIdentifier identifies a text document by its URI.
+type TextDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+// TextDocumentItem is a text document transferred from the client to the server.
+type TextDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+// VersionedTextDocumentIdentifier identifies a specific version of a text document.
+type VersionedTextDocumentIdentifier struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+}
+
+// TextDocumentPositionParams are the parameters of requests which target a position inside a text document.
+type TextDocumentPositionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// InitializeResult is the result of an "initialize" request.
+type InitializeResult struct {
+	Capabilities ServerCapabilities `json:"capabilities"`
+	ServerInfo   *ServerInfo        `json:"serverInfo,omitempty"`
+}
+
+// ServerInfo describes the server to the client.
+type ServerInfo struct {
+	Name string `json:"name"`
+}
+
+// ServerCapabilities are the language features provided by the server.
+type ServerCapabilities struct {
+	TextDocumentSync   *TextDocumentSyncOptions `json:"textDocumentSync,omitempty"`
+	CompletionProvider *CompletionOptions       `json:"completionProvider,omitempty"`
+	HoverProvider      bool                     `json:"hoverProvider,omitempty"`
+	DefinitionProvider bool                     `json:"definitionProvider,omitempty"`
+	ReferencesProvider bool                     `json:"referencesProvider,omitempty"`
+}
+
+// TextDocumentSyncKind defines how the client syncs document changes to the
+// server.
+type TextDocumentSyncKind int
+
+// Supported TextDocumentSyncKind. The server only supports full syncs.
+const TextDocumentSyncKindFull TextDocumentSyncKind = 1
+
+// TextDocumentSyncOptions describe how text documents are synced to the server.
+type TextDocumentSyncOptions struct {
+	OpenClose bool                 `json:"openClose"`
+	Change    TextDocumentSyncKind `json:"change"`
+}
+
+// CompletionOptions are the options of the completion provider.
+type CompletionOptions struct {
+	TriggerCharacters []string `json:"triggerCharacters,omitempty"`
+}
+
+// DidOpenTextDocumentParams are the parameters of a "textDocument/didOpen" notification.
+type DidOpenTextDocumentParams struct {
+	TextDocument TextDocumentItem `json:"textDocument"`
+}
+
+// DidChangeTextDocumentParams are the parameters of a "textDocument/didChange" notification.
+type DidChangeTextDocumentParams struct {
+	TextDocument   VersionedTextDocumentIdentifier  `json:"textDocument"`
+	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
+}
+
+// TextDocumentContentChangeEvent describes a change to a text document.
+// Because the server only supports full syncs, Text always contains the full
+// content of the document.
+type TextDocumentContentChangeEvent struct {
+	Text string `json:"text"`
+}
+
+// DidCloseTextDocumentParams are the parameters of a "textDocument/didClose" notification.
+type DidCloseTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// DiagnosticSeverity is the severity of a Diagnostic.
+type DiagnosticSeverity int
+
+// Supported DiagnosticSeverity.
+const (
+	SeverityError   DiagnosticSeverity = 1
+	SeverityWarning DiagnosticSeverity = 2
+)
+
+// Diagnostic represents a problem in a text document, such as an error or a warning.
+type Diagnostic struct {
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Source   string             `json:"source"`
+	Message  string             `json:"message"`
+}
+
+// PublishDiagnosticsParams are the parameters of a "textDocument/publishDiagnostics" notification.
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Version     *int         `json:"version,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// CompletionItemKind is the kind of a CompletionItem.
+type CompletionItemKind int
+
+// Supported CompletionItemKind.
+const (
+	CompletionItemKindFunction  CompletionItemKind = 3
+	CompletionItemKindField     CompletionItemKind = 5
+	CompletionItemKindClass     CompletionItemKind = 7
+	CompletionItemKindModule    CompletionItemKind = 9
+	CompletionItemKindProperty  CompletionItemKind = 10
+	CompletionItemKindKeyword   CompletionItemKind = 14
+	CompletionItemKindReference CompletionItemKind = 18
+)
+
+// CompletionItem is a completion proposal.
+type CompletionItem struct {
+	Label         string             `json:"label"`
+	Kind          CompletionItemKind `json:"kind,omitempty"`
+	Detail        string             `json:"detail,omitempty"`
+	Documentation *MarkupContent     `json:"documentation,omitempty"`
+	InsertText    string             `json:"insertText,omitempty"`
+}
+
+// CompletionList is a list of completion proposals.
+type CompletionList struct {
+	IsIncomplete bool             `json:"isIncomplete"`
+	Items        []CompletionItem `json:"items"`
+}
+
+// MarkupKind describes the format of a MarkupContent.
+type MarkupKind string
+
+// Supported MarkupKind.
+const MarkupKindMarkdown MarkupKind = "markdown"
+
+// MarkupContent is a formatted piece of documentation.
+type MarkupContent struct {
+	Kind  MarkupKind `json:"kind"`
+	Value string     `json:"value"`
+}
+
+// Hover is the result of a "textDocument/hover" request.
+type Hover struct {
+	Contents MarkupContent `json:"contents"`
+	Range    *Range        `json:"range,omitempty"`
+}
+
+// ReferenceParams are the parameters of a "textDocument/references" request.
+type ReferenceParams struct {
+	TextDocumentPositionParams
+	Context ReferenceContext `json:"context"`
+}
+
+// ReferenceContext is the context of a "textDocument/references" request.
+type ReferenceContext struct {
+	IncludeDeclaration bool `json:"includeDeclaration"`
+}
@@ -0,0 +1,415 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// diagnosticSource is the value of the "source" field of diagnostics
+// published by the server.
+const diagnosticSource = "til"
+
+// Server is a language server for Bridge Description Files.
+type Server struct {
+	conn *conn
+
+	docs map[string]*document
+
+	initialized bool
+	shutdown    bool
+}
+
+// NewServer returns a Server which communicates with its client over the
+// given reader and writer.
+func NewServer(r io.Reader, w io.Writer) *Server {
+	return &Server{
+		conn: newConn(r, w),
+		docs: make(map[string]*document),
+	}
+}
+
+// errExitWithoutShutdown is returned by Serve when the client requests the
+// server to exit without asking it to shut down first.
+var errExitWithoutShutdown = errors.New("received exit notification before shutdown request")
+
+// Serve reads and handles messages sent by the client until the client asks
+// the server to exit, or the connection is closed.
+func (s *Server) Serve() error {
+	for {
+		msg, err := s.conn.read()
+		if err != nil {
+			var respErr *responseError
+			if errors.As(err, &respErr) {
+				if err := s.conn.reply(nil, nil, respErr); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("reading message: %w", err)
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return errExitWithoutShutdown
+			}
+			return nil
+		}
+
+		result, err := s.handle(msg)
+
+		if msg.isNotification() {
+			continue
+		}
+		if err := s.conn.reply(msg.ID, result, err); err != nil {
+			return fmt.Errorf("writing response: %w", err)
+		}
+	}
+}
+
+// handle handles the given message, and returns the result to send back to
+// the client if the message is a request.
+func (s *Server) handle(msg *message) (result interface{}, err error) {
+	defer func() {
+		// do not let unexpected failures of the language
+		// implementation take the server down
+		if r := recover(); r != nil {
+			result = nil
+			err = &responseError{
+				Code:    codeInternalError,
+				Message: fmt.Sprint("internal error: ", r),
+			}
+		}
+	}()
+
+	if !s.initialized && msg.Method != "initialize" {
+		return nil, &responseError{
+			Code:    codeServerNotInitialized,
+			Message: "server not initialized",
+		}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		s.initialized = true
+		return s.initialize(), nil
+
+	case "initialized":
+		return nil, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var p DidOpenTextDocumentParams
+		if err := decodeParams(msg, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.didOpen(&p)
+
+	case "textDocument/didChange":
+		var p DidChangeTextDocumentParams
+		if err := decodeParams(msg, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.didChange(&p)
+
+	case "textDocument/didClose":
+		var p DidCloseTextDocumentParams
+		if err := decodeParams(msg, &p); err != nil {
+			return nil, err
+		}
+		return nil, s.didClose(&p)
+
+	case "textDocument/completion":
+		var p TextDocumentPositionParams
+		if err := decodeParams(msg, &p); err != nil {
+			return nil, err
+		}
+		return s.completion(&p), nil
+
+	case "textDocument/hover":
+		var p TextDocumentPositionParams
+		if err := decodeParams(msg, &p); err != nil {
+			return nil, err
+		}
+		return s.hover(&p), nil
+
+	case "textDocument/definition":
+		var p TextDocumentPositionParams
+		if err := decodeParams(msg, &p); err != nil {
+			return nil, err
+		}
+		return s.definition(&p), nil
+
+	case "textDocument/references":
+		var p ReferenceParams
+		if err := decodeParams(msg, &p); err != nil {
+			return nil, err
+		}
+		return s.references(&p), nil
+	}
+
+	return nil, &responseError{
+		Code:    codeMethodNotFound,
+		Message: "method not supported: " + msg.Method,
+	}
+}
+
+// decodeParams decodes the parameters of the given message into v.
+func decodeParams(msg *message, v interface{}) error {
+	if err := json.Unmarshal(msg.Params, v); err != nil {
+		return &responseError{
+			Code:    codeInvalidParams,
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
+
+// initialize returns the capabilities of the server.
+func (s *Server) initialize() *InitializeResult {
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: &TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    TextDocumentSyncKindFull,
+			},
+			CompletionProvider: &CompletionOptions{
+				TriggerCharacters: []string{".", `"`},
+			},
+			HoverProvider:      true,
+			DefinitionProvider: true,
+			ReferencesProvider: true,
+		},
+		ServerInfo: &ServerInfo{
+			Name: "til",
+		},
+	}
+}
+
+// didOpen starts tracking the given document, and publishes its diagnostics.
+func (s *Server) didOpen(p *DidOpenTextDocumentParams) error {
+	doc := &document{
+		uri:     p.TextDocument.URI,
+		path:    uriToPath(p.TextDocument.URI),
+		version: p.TextDocument.Version,
+		text:    []byte(p.TextDocument.Text),
+	}
+	s.docs[doc.uri] = doc
+
+	return s.update(doc)
+}
+
+// didChange updates the content of the given document, and publishes its
+// diagnostics.
+func (s *Server) didChange(p *DidChangeTextDocumentParams) error {
+	doc, ok := s.docs[p.TextDocument.URI]
+	if !ok || len(p.ContentChanges) == 0 {
+		return nil
+	}
+
+	// full sync, the last change contains the entire document
+	doc.text = []byte(p.ContentChanges[len(p.ContentChanges)-1].Text)
+	doc.version = p.TextDocument.Version
+
+	return s.update(doc)
+}
+
+// didClose stops tracking the given document, and clears its diagnostics.
+func (s *Server) didClose(p *DidCloseTextDocumentParams) error {
+	delete(s.docs, p.TextDocument.URI)
+
+	return s.conn.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+		URI:         p.TextDocument.URI,
+		Diagnostics: []Diagnostic{},
+	})
+}
+
+// update analyzes the given document, and publishes its diagnostics.
+func (s *Server) update(doc *document) error {
+	doc.analysis = analyze(doc.path, doc.text)
+
+	version := doc.version
+
+	return s.conn.notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     &version,
+		Diagnostics: toDiagnostics(doc, doc.analysis.diags),
+	})
+}
+
+// toDiagnostics converts the given HCL diagnostics into LSP diagnostics for
+// the given document. Diagnostics without a subject are attached to the
+// beginning of the document.
+func toDiagnostics(doc *document, hclDiags hcl.Diagnostics) []Diagnostic {
+	diags := make([]Diagnostic, 0, len(hclDiags))
+
+	for _, d := range hclDiags {
+		var rng Range
+		if d.Subject != nil {
+			if d.Subject.Filename != doc.path {
+				continue
+			}
+			rng = rangeOf(doc.text, *d.Subject)
+		}
+
+		sev := SeverityError
+		if d.Severity == hcl.DiagWarning {
+			sev = SeverityWarning
+		}
+
+		msg := d.Summary
+		if d.Detail != "" {
+			msg += ": " + d.Detail
+		}
+
+		diags = append(diags, Diagnostic{
+			Range:    rng,
+			Severity: sev,
+			Source:   diagnosticSource,
+			Message:  msg,
+		})
+	}
+
+	return diags
+}
+
+// completion handles a "textDocument/completion" request.
+func (s *Server) completion(p *TextDocumentPositionParams) *CompletionList {
+	list := &CompletionList{
+		Items: []CompletionItem{},
+	}
+
+	doc, ok := s.docs[p.TextDocument.URI]
+	if !ok {
+		return list
+	}
+
+	if items := complete(doc.text, offsetAt(doc.text, p.Position)); items != nil {
+		list.Items = items
+	}
+
+	return list
+}
+
+// hover handles a "textDocument/hover" request.
+func (s *Server) hover(p *TextDocumentPositionParams) *Hover {
+	doc, ok := s.docs[p.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+
+	desc, rng := hover(doc.text, offsetAt(doc.text, p.Position), doc.analysis)
+	if desc == "" {
+		return nil
+	}
+
+	h := &Hover{
+		Contents: MarkupContent{
+			Kind:  MarkupKindMarkdown,
+			Value: desc,
+		},
+	}
+	if rng != nil {
+		r := rangeOf(doc.text, *rng)
+		h.Range = &r
+	}
+
+	return h
+}
+
+// definition handles a "textDocument/definition" request.
+func (s *Server) definition(p *TextDocumentPositionParams) []Location {
+	doc, ok := s.docs[p.TextDocument.URI]
+	if !ok || doc.analysis == nil {
+		return nil
+	}
+
+	ref := doc.analysis.referenceAt(offsetAt(doc.text, p.Position))
+	if ref == nil {
+		return nil
+	}
+
+	cmp, ok := doc.analysis.component(ref.Subject.Addr())
+	if !ok {
+		return nil
+	}
+
+	return []Location{{
+		URI:   doc.uri,
+		Range: rangeOf(doc.text, cmp.SourceRange),
+	}}
+}
+
+// references handles a "textDocument/references" request.
+// The position can be located either on a reference or on the header of the
+// referenced block.
+func (s *Server) references(p *ReferenceParams) []Location {
+	doc, ok := s.docs[p.TextDocument.URI]
+	if !ok || doc.analysis == nil {
+		return nil
+	}
+	a := doc.analysis
+
+	offs := offsetAt(doc.text, p.Position)
+
+	var key string
+	if ref := a.referenceAt(offs); ref != nil {
+		key = ref.Subject.Addr()
+	} else if cmp, ok := a.componentAt(offs); ok {
+		key = referenceKey(cmp)
+	} else {
+		return nil
+	}
+
+	var rngs []hcl.Range
+
+	if p.Context.IncludeDeclaration {
+		if cmp, ok := a.component(key); ok {
+			rngs = append(rngs, cmp.SourceRange)
+		}
+	}
+	for _, ref := range a.referencesTo(key) {
+		rngs = append(rngs, ref.SourceRange)
+	}
+
+	sort.Slice(rngs, func(i, j int) bool {
+		return rngs[i].Start.Byte < rngs[j].Start.Byte
+	})
+
+	locs := make([]Location, len(rngs))
+	for i, r := range rngs {
+		locs[i] = Location{
+			URI:   doc.uri,
+			Range: rangeOf(doc.text, r),
+		}
+	}
+
+	return locs
+}
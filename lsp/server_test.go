@@ -0,0 +1,303 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	. "til/lsp"
+)
+
+const testDocURI = "file:///bridges/test.brg.hcl"
+
+const testDoc = `bridge "test" {}
+
+source ping "heartbeat" {
+  schedule = "* * * * *"
+  data = "{}"
+  to = target.
+}
+
+target event_display "printer" {
+}
+
+router content_based "unused" {
+  route {
+    to = target.printer
+  }
+}
+`
+
+func TestServer(t *testing.T) {
+	var in bytes.Buffer
+
+	writeMessage(t, &in, 1, "initialize", struct{}{})
+	writeMessage(t, &in, 0, "initialized", struct{}{})
+	writeMessage(t, &in, 0, "textDocument/didOpen", &DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{
+			URI:        testDocURI,
+			LanguageID: "hcl",
+			Version:    1,
+			Text:       testDoc,
+		},
+	})
+	// "to = target.|"
+	writeMessage(t, &in, 2, "textDocument/completion", positionParams(5, 14))
+	// "source ping|"
+	writeMessage(t, &in, 3, "textDocument/completion", positionParams(2, 9))
+	// "  |" (inside route block)
+	writeMessage(t, &in, 4, "textDocument/completion", positionParams(13, 0))
+	writeMessage(t, &in, 0, "textDocument/didChange", &DidChangeTextDocumentParams{
+		TextDocument: VersionedTextDocumentIdentifier{
+			URI:     testDocURI,
+			Version: 2,
+		},
+		ContentChanges: []TextDocumentContentChangeEvent{{
+			Text: strings.Replace(testDoc, "to = target.\n", "to = target.printer\n", 1),
+		}},
+	})
+	// "to = target.prin|ter"
+	writeMessage(t, &in, 5, "textDocument/definition", positionParams(13, 20))
+	// "target event_display "pri|nter""
+	writeMessage(t, &in, 6, "textDocument/references", &ReferenceParams{
+		TextDocumentPositionParams: *positionParams(8, 25),
+	})
+	// "da|ta"
+	writeMessage(t, &in, 7, "textDocument/hover", positionParams(4, 4))
+	writeMessage(t, &in, 8, "shutdown", nil)
+	writeMessage(t, &in, 0, "exit", nil)
+
+	var out bytes.Buffer
+
+	if err := NewServer(&in, &out).Serve(); err != nil {
+		t.Fatal("Server returned an error:", err)
+	}
+
+	results, notifs := readMessages(t, &out)
+
+	t.Run("diagnostics", func(t *testing.T) {
+		diags := notifs["textDocument/publishDiagnostics"]
+		if len(diags) != 2 {
+			t.Fatalf("Expected diagnostics to be published twice, got %d", len(diags))
+		}
+
+		var p PublishDiagnosticsParams
+
+		if err := json.Unmarshal(diags[0], &p); err != nil {
+			t.Fatal("Error decoding notification:", err)
+		}
+		if len(p.Diagnostics) == 0 {
+			t.Fatal("Expected diagnostics for the invalid document")
+		}
+		if line := p.Diagnostics[0].Range.Start.Line; line != 5 {
+			t.Errorf("Expected diagnostic to be reported on line 5, got %d", line)
+		}
+
+		if err := json.Unmarshal(diags[1], &p); err != nil {
+			t.Fatal("Error decoding notification:", err)
+		}
+		if len(p.Diagnostics) != 0 {
+			t.Error("Expected no diagnostic for the corrected document, got", p.Diagnostics)
+		}
+	})
+
+	t.Run("reference completion", func(t *testing.T) {
+		expect := []string{"printer"}
+		if diff := cmp.Diff(expect, completionLabels(t, results["2"])); diff != "" {
+			t.Error("Unexpected diff: (-:expect, +:got)", diff)
+		}
+	})
+
+	t.Run("component type completion", func(t *testing.T) {
+		labels := completionLabels(t, results["3"])
+		if !contains(labels, "ping") || !contains(labels, "aws_sqs") {
+			t.Error("Expected source types in completion items, got", labels)
+		}
+	})
+
+	t.Run("nested block attributes completion", func(t *testing.T) {
+		expect := []string{"attributes", "condition", "to"}
+		if diff := cmp.Diff(expect, completionLabels(t, results["4"])); diff != "" {
+			t.Error("Unexpected diff: (-:expect, +:got)", diff)
+		}
+	})
+
+	t.Run("definition", func(t *testing.T) {
+		var locs []Location
+		if err := json.Unmarshal(results["5"], &locs); err != nil {
+			t.Fatal("Error decoding result:", err)
+		}
+
+		expect := []Location{{
+			URI: testDocURI,
+			Range: Range{
+				Start: Position{Line: 8, Character: 0},
+				End:   Position{Line: 8, Character: 30},
+			},
+		}}
+		if diff := cmp.Diff(expect, locs); diff != "" {
+			t.Error("Unexpected diff: (-:expect, +:got)", diff)
+		}
+	})
+
+	t.Run("references", func(t *testing.T) {
+		var locs []Location
+		if err := json.Unmarshal(results["6"], &locs); err != nil {
+			t.Fatal("Error decoding result:", err)
+		}
+
+		expect := []Location{{
+			URI: testDocURI,
+			Range: Range{
+				Start: Position{Line: 5, Character: 7},
+				End:   Position{Line: 5, Character: 21},
+			},
+		}, {
+			URI: testDocURI,
+			Range: Range{
+				Start: Position{Line: 13, Character: 9},
+				End:   Position{Line: 13, Character: 23},
+			},
+		}}
+		if diff := cmp.Diff(expect, locs); diff != "" {
+			t.Error("Unexpected diff: (-:expect, +:got)", diff)
+		}
+	})
+
+	t.Run("hover", func(t *testing.T) {
+		var h Hover
+		if err := json.Unmarshal(results["7"], &h); err != nil {
+			t.Fatal("Error decoding result:", err)
+		}
+
+		const expect = "```hcl\ndata = string\n```\nAttribute (required)."
+		if diff := cmp.Diff(expect, h.Contents.Value); diff != "" {
+			t.Error("Unexpected diff: (-:expect, +:got)", diff)
+		}
+	})
+}
+
+// writeMessage writes a JSON-RPC message to w. An id of 0 denotes a
+// notification.
+func writeMessage(t *testing.T, w io.Writer, id int, method string, params interface{}) {
+	t.Helper()
+
+	msg := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+	}
+	if id != 0 {
+		msg["id"] = id
+	}
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal("Error serializing message:", err)
+	}
+
+	fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(b), b)
+}
+
+// readMessages reads all JSON-RPC messages from r, and returns the results of
+// responses indexed by request ID, and the parameters of notifications
+// indexed by method.
+func readMessages(t *testing.T, r io.Reader) (map[string]json.RawMessage, map[string][]json.RawMessage) {
+	t.Helper()
+
+	results := make(map[string]json.RawMessage)
+	notifs := make(map[string][]json.RawMessage)
+
+	tr := textproto.NewReader(bufio.NewReader(r))
+
+	for {
+		hdr, err := tr.ReadMIMEHeader()
+		if err == io.EOF {
+			return results, notifs
+		}
+		if err != nil {
+			t.Fatal("Error reading message header:", err)
+		}
+
+		length, _ := strconv.Atoi(hdr.Get("Content-Length"))
+		body := make([]byte, length)
+		if _, err := io.ReadFull(tr.R, body); err != nil {
+			t.Fatal("Error reading message body:", err)
+		}
+
+		var msg struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal("Error decoding message:", err)
+		}
+
+		switch {
+		case msg.Error != nil:
+			t.Errorf("Request %s returned an error: %s", msg.ID, msg.Error)
+		case msg.Method != "":
+			notifs[msg.Method] = append(notifs[msg.Method], msg.Params)
+		default:
+			results[string(msg.ID)] = msg.Result
+		}
+	}
+}
+
+func positionParams(line, char int) *TextDocumentPositionParams {
+	return &TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: testDocURI},
+		Position:     Position{Line: line, Character: char},
+	}
+}
+
+func completionLabels(t *testing.T, result json.RawMessage) []string {
+	t.Helper()
+
+	var l CompletionList
+	if err := json.Unmarshal(result, &l); err != nil {
+		t.Fatal("Error decoding completion list:", err)
+	}
+
+	labels := make([]string, len(l.Items))
+	for i, it := range l.Items {
+		labels[i] = it.Label
+	}
+	return labels
+}
+
+func contains(strs []string, s string) bool {
+	for _, e := range strs {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
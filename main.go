@@ -25,21 +25,33 @@ import (
 )
 
 func main() {
-	if err := run(os.Args, os.Stdout, os.Stderr); err != nil {
+	if err := run(os.Args, os.Stdin, os.Stdout, os.Stderr); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running command: %s\n", err)
 		os.Exit(1)
 	}
 }
 
 // run executes the command.
-func run(args []string, stdout, stderr io.Writer) error {
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
 	c := cli.New(args, usage,
+		cli.StdReader(stdin),
 		cli.StdWriter(stdout),
 		cli.ErrWriter(stderr),
 
 		cli.Subcommand(cmdGenerate, new(GenerateCommand)),
 		cli.Subcommand(cmdValidate, new(ValidateCommand)),
 		cli.Subcommand(cmdGraph, new(GraphCommand)),
+		cli.Subcommand(cmdExplain, new(ExplainCommand)),
+		cli.Subcommand(cmdSchema, new(SchemaCommand)),
+		cli.Subcommand(cmdLSP, new(LSPCommand)),
+		cli.Subcommand(cmdImport, new(ImportCommand)),
+		cli.Subcommand(cmdSimulate, new(SimulateCommand)),
+		cli.Subcommand(cmdTest, new(TestCommand)),
+		cli.Subcommand(cmdDocs, new(DocsCommand)),
+		cli.Subcommand(cmdSecrets, new(SecretsCommand)),
+		cli.Subcommand(cmdUpgrade, new(UpgradeCommand)),
+		cli.Subcommand(cmdRename, new(RenameCommand)),
+		cli.Subcommand(cmdVersion, new(VersionCommand)),
 	)
 
 	return c.Run()
@@ -0,0 +1,362 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/tryfunc"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config"
+	"til/config/addr"
+	"til/core"
+	"til/fs"
+	"til/lang"
+	"til/lang/k8s"
+)
+
+// API version of the placeholder destinations assigned to components while
+// their configuration is decoded for the evaluation of policies.
+const placeholderAPIVersion = "policy.til/v1"
+
+// Names of the variables available in the expressions of policies.
+const (
+	varBridge    = "bridge"
+	varComponent = "component"
+	varConfig    = "config"
+	varManifests = "manifests"
+	varImages    = "images"
+)
+
+// Check evaluates the given policies against the Bridge of the given Context,
+// and returns a diagnostic for each violation. The severity of each diagnostic
+// is the one of the violated policy.
+//
+// Diagnostics about the translation of the Bridge itself are expected to be
+// reported by the caller, and are therefore not returned.
+func Check(cctx *core.Context, policies []*Policy) hcl.Diagnostics {
+	g, graphDiags := cctx.Graph()
+	if graphDiags.HasErrors() {
+		return graphDiags
+	}
+
+	cmpsManifests, _ := cctx.GenerateComponents()
+
+	manifests := make(map[string][]interface{}, len(cmpsManifests))
+	for _, cm := range cmpsManifests {
+		manifests[componentKey(cm.Component)] = cm.Manifests
+	}
+
+	eval := core.NewEvaluator(filepath.Dir(cctx.Bridge.Path), cctx.FS, cctx.Bridge.Delivery)
+
+	var cmps []core.MessagingComponentVertex
+
+	// Every referenceable component is assigned a placeholder address, so
+	// that the configurations of components which reference it can be
+	// decoded.
+	for _, v := range g.SortedVertices() {
+		cmp, ok := v.(core.MessagingComponentVertex)
+		if !ok {
+			continue
+		}
+		cmps = append(cmps, cmp)
+
+		if _, ok := v.(core.ReferenceableVertex); ok {
+			cmpAddr := cmp.ComponentAddr()
+			eval.InsertVariable(cmpAddr.Category.String(), cmpAddr.Identifier,
+				k8s.NewDestination(placeholderAPIVersion, cmpAddr.Category.String(), cmpAddr.Identifier))
+		}
+	}
+
+	brg := bridgeValue(cctx.Bridge)
+
+	var diags hcl.Diagnostics
+
+	for _, pol := range policies {
+		if pol.Match == nil {
+			vars := map[string]cty.Value{
+				varBridge: brg,
+			}
+			diags = diags.Extend(evalPolicy(pol, vars, cctx.FS, "The Bridge", bridgeRange(cctx.Bridge)))
+			continue
+		}
+
+		for _, cmp := range cmps {
+			cmpAddr := cmp.ComponentAddr()
+			if !pol.Match.matches(cmpAddr) {
+				continue
+			}
+
+			// Components which configuration can't be decoded are
+			// reported by the translation.
+			cfg := cty.NullVal(cty.DynamicPseudoType)
+			if dec, ok := cmp.(core.DecodableConfigVertex); ok {
+				if v, _, cfgDiags := dec.DecodedConfig(eval); !cfgDiags.HasErrors() {
+					cfg = v
+				}
+			}
+
+			ms := manifests[componentKey(cmpAddr)]
+
+			vars := map[string]cty.Value{
+				varBridge:    brg,
+				varComponent: componentValue(cmpAddr),
+				varConfig:    cfg,
+				varManifests: manifestsValue(ms),
+				varImages:    imagesValue(ms),
+			}
+			diags = diags.Extend(evalPolicy(pol, vars, cctx.FS,
+				"The component "+componentKey(cmpAddr), cmpAddr.SourceRange))
+		}
+	}
+
+	return diags
+}
+
+// matches returns whether the component with the given address is matched.
+func (m *Match) matches(cmpAddr addr.MessagingComponent) bool {
+	if m.Category != config.CategoryUnknown && m.Category != cmpAddr.Category {
+		return false
+	}
+
+	if len(m.Types) == 0 {
+		return true
+	}
+	for _, t := range m.Types {
+		if t == cmpAddr.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// evalPolicy evaluates the condition of a policy using the given variables,
+// and returns a diagnostic about the given subject if the condition doesn't
+// hold.
+func evalPolicy(pol *Policy, vars map[string]cty.Value, fs fs.FS,
+	subjDesc string, subj hcl.Range) hcl.Diagnostics {
+
+	evalCtx := &hcl.EvalContext{
+		Variables: vars,
+		Functions: functions(pol.BaseDir, fs),
+	}
+
+	val, diags := pol.Condition.Value(evalCtx)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	val, err := convert.Convert(val, cty.Bool)
+	if err != nil || val.IsNull() || !val.IsKnown() {
+		return diags.Append(badConditionDiagnostic(pol.Condition.Range()))
+	}
+
+	if val.True() {
+		return diags
+	}
+
+	msg := pol.Description
+	if pol.Message != nil {
+		msgVal, msgDiags := pol.Message.Value(evalCtx)
+		diags = diags.Extend(msgDiags)
+		if msgDiags.HasErrors() {
+			return diags
+		}
+
+		if msgVal, err = convert.Convert(msgVal, cty.String); err != nil || msgVal.IsNull() || !msgVal.IsKnown() {
+			return diags.Append(wrongTypeDiagnostic("a string", pol.Message.Range()))
+		}
+		msg = msgVal.AsString()
+	}
+
+	return diags.Append(violationDiagnostic(pol, subjDesc, msg, subj))
+}
+
+// functions returns the functions available in the expressions of policies.
+func functions(baseDir string, fs fs.FS) map[string]function.Function {
+	funcs := lang.Functions(baseDir, fs)
+
+	funcs["can"] = tryfunc.CanFunc
+	funcs["try"] = tryfunc.TryFunc
+	funcs["contains"] = stdlib.ContainsFunc
+	funcs["length"] = stdlib.LengthFunc
+	funcs["keys"] = stdlib.KeysFunc
+	funcs["lookup"] = stdlib.LookupFunc
+	funcs["concat"] = stdlib.ConcatFunc
+	funcs["flatten"] = stdlib.FlattenFunc
+	funcs["format"] = stdlib.FormatFunc
+	funcs["join"] = stdlib.JoinFunc
+	funcs["split"] = stdlib.SplitFunc
+	funcs["lower"] = stdlib.LowerFunc
+	funcs["upper"] = stdlib.UpperFunc
+	funcs["regex"] = stdlib.RegexFunc
+
+	return funcs
+}
+
+// bridgeValue returns the value of the "bridge" variable for the given Bridge.
+func bridgeValue(brg *config.Bridge) cty.Value {
+	retries := cty.NullVal(cty.Number)
+	dls := cty.NullVal(cty.String)
+
+	if d := brg.Delivery; d != nil {
+		if d.Retries != nil {
+			retries = cty.NumberIntVal(*d.Retries)
+		}
+		if d.DeadLetterSink != nil {
+			ts := d.DeadLetterSink.SimpleSplit()
+			if len(ts.Rel) == 1 {
+				if attr, ok := ts.Rel[0].(hcl.TraverseAttr); ok {
+					dls = cty.StringVal(ts.RootName() + "." + attr.Name)
+				}
+			}
+		}
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"identifier":  cty.StringVal(brg.Identifier),
+		"description": cty.StringVal(brg.Description),
+		"owner":       cty.StringVal(brg.Owner),
+		"delivery": cty.ObjectVal(map[string]cty.Value{
+			"retries":          retries,
+			"dead_letter_sink": dls,
+		}),
+	})
+}
+
+// bridgeRange returns the range of the "bridge" block of the given Bridge, or
+// the beginning of its file if it doesn't contain any.
+func bridgeRange(brg *config.Bridge) hcl.Range {
+	if brg.SourceRange.Filename != "" {
+		return brg.SourceRange
+	}
+	return hcl.Range{
+		Filename: brg.Path,
+		Start:    hcl.InitialPos,
+		End:      hcl.InitialPos,
+	}
+}
+
+// componentValue returns the value of the "component" variable for the
+// component with the given address.
+func componentValue(cmpAddr addr.MessagingComponent) cty.Value {
+	return cty.ObjectVal(map[string]cty.Value{
+		"category":   cty.StringVal(cmpAddr.Category.String()),
+		"type":       cty.StringVal(cmpAddr.Type),
+		"identifier": cty.StringVal(cmpAddr.Identifier),
+	})
+}
+
+// manifestsValue returns the value of the "manifests" variable for the given
+// generated manifests.
+func manifestsValue(manifests []interface{}) cty.Value {
+	vals := make([]cty.Value, 0, len(manifests))
+
+	for _, m := range manifests {
+		u, ok := m.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+
+		b, err := json.Marshal(u.Object)
+		if err != nil {
+			continue
+		}
+		t, err := ctyjson.ImpliedType(b)
+		if err != nil {
+			continue
+		}
+		v, err := ctyjson.Unmarshal(b, t)
+		if err != nil {
+			continue
+		}
+
+		vals = append(vals, v)
+	}
+
+	if len(vals) == 0 {
+		return cty.EmptyTupleVal
+	}
+	return cty.TupleVal(vals)
+}
+
+// imagesValue returns the value of the "images" variable, which contains the
+// container images referenced in the given manifests, sorted alphabetically.
+func imagesValue(manifests []interface{}) cty.Value {
+	imgs := make(map[string]struct{})
+
+	for _, m := range manifests {
+		if u, ok := m.(*unstructured.Unstructured); ok {
+			collectImages(u.Object, imgs)
+		}
+	}
+
+	if len(imgs) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+
+	sorted := make([]string, 0, len(imgs))
+	for img := range imgs {
+		sorted = append(sorted, img)
+	}
+	sort.Strings(sorted)
+
+	vals := make([]cty.Value, len(sorted))
+	for i, img := range sorted {
+		vals[i] = cty.StringVal(img)
+	}
+
+	return cty.ListVal(vals)
+}
+
+// collectImages walks the given object recursively and records the values of
+// all "image" fields.
+func collectImages(obj map[string]interface{}, imgs map[string]struct{}) {
+	for k, v := range obj {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			collectImages(v, imgs)
+
+		case []interface{}:
+			for _, e := range v {
+				if m, ok := e.(map[string]interface{}); ok {
+					collectImages(m, imgs)
+				}
+			}
+
+		case string:
+			if k == "image" {
+				imgs[v] = struct{}{}
+			}
+		}
+	}
+}
+
+// componentKey returns a key which uniquely identifies the component with the
+// given address (e.g. "target.my_target").
+func componentKey(cmpAddr addr.MessagingComponent) string {
+	return cmpAddr.Category.String() + "." + cmpAddr.Identifier
+}
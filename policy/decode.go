@@ -0,0 +1,283 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/gocty"
+
+	"til/config"
+	"til/config/file"
+)
+
+// FileExt is the extension of files containing policies.
+const FileExt = ".policy.hcl"
+
+// Names of blocks and attributes in policy files.
+const (
+	blkPolicy = "policy"
+	blkMatch  = "match"
+
+	attrDescription = "description"
+	attrSeverity    = "severity"
+	attrCondition   = "condition"
+	attrMessage     = "message"
+	attrCategory    = "category"
+	attrTypes       = "types"
+)
+
+// Supported values of the "severity" attribute.
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+)
+
+// fileSchema is the schema of the body of policy files.
+var fileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: blkPolicy, LabelNames: []string{"name"}},
+	},
+}
+
+// policySchema is the schema of the body of "policy" blocks.
+var policySchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: attrDescription},
+		{Name: attrSeverity},
+		{Name: attrCondition, Required: true},
+		{Name: attrMessage},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: blkMatch},
+	},
+}
+
+// matchSchema is the schema of the body of "match" blocks.
+var matchSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: attrCategory},
+		{Name: attrTypes},
+	},
+}
+
+// Policy is a rule which Bridges must comply with.
+type Policy struct {
+	Name        string
+	Description string
+	Severity    hcl.DiagnosticSeverity
+
+	// Components the policy applies to. A nil Match indicates that the
+	// policy applies to the Bridge as a whole.
+	Match *Match
+
+	// Expression which must evaluate to true for the policy to be
+	// satisfied.
+	Condition hcl.Expression
+	// Expression of the message reported upon violation, if set.
+	Message hcl.Expression
+
+	// Directory of the file the policy was loaded from, used as base
+	// directory by functions which access the file system.
+	BaseDir string
+
+	SourceRange hcl.Range
+}
+
+// Match selects the components a Policy applies to.
+type Match struct {
+	// Category of matched components. CategoryUnknown matches all
+	// categories.
+	Category config.ComponentCategory
+	// Types of matched components. An empty list matches all types.
+	Types []string
+}
+
+// LoadDir loads the policies contained in all policy files located in the
+// given directory.
+func LoadDir(p *file.Parser, dir string) ([]*Policy, hcl.Diagnostics) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+FileExt))
+	if err != nil {
+		return nil, hcl.Diagnostics{readDirDiagnostic(dir, err)}
+	}
+	if len(files) == 0 {
+		return nil, hcl.Diagnostics{emptyDirDiagnostic(dir)}
+	}
+	sort.Strings(files)
+
+	var diags hcl.Diagnostics
+	var policies []*Policy
+
+	for _, f := range files {
+		ps, loadDiags := LoadFile(p, f)
+		diags = diags.Extend(loadDiags)
+		policies = append(policies, ps...)
+	}
+
+	return policies, diags
+}
+
+// LoadFile parses the policy file at the given path and decodes the policies
+// it contains.
+func LoadFile(p *file.Parser, filePath string) ([]*Policy, hcl.Diagnostics) {
+	hclFile, diags := p.ParseHCLFile(filePath)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	content, contentDiags := hclFile.Body.Content(fileSchema)
+	diags = diags.Extend(contentDiags)
+
+	var policies []*Policy
+
+	for _, blk := range content.Blocks {
+		pol, decodeDiags := decodePolicyBlock(blk)
+		diags = diags.Extend(decodeDiags)
+		if pol != nil {
+			pol.BaseDir = filepath.Dir(filePath)
+			policies = append(policies, pol)
+		}
+	}
+
+	return policies, diags
+}
+
+// decodePolicyBlock decodes a "policy" block.
+func decodePolicyBlock(blk *hcl.Block) (*Policy, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := blk.Body.Content(policySchema)
+	diags = diags.Extend(contentDiags)
+	if contentDiags.HasErrors() {
+		return nil, diags
+	}
+
+	pol := &Policy{
+		Name:        blk.Labels[0],
+		Severity:    hcl.DiagError,
+		Condition:   content.Attributes[attrCondition].Expr,
+		SourceRange: blk.DefRange,
+	}
+
+	if attr, ok := content.Attributes[attrDescription]; ok {
+		desc, decodeDiags := decodeString(attr)
+		diags = diags.Extend(decodeDiags)
+		pol.Description = desc
+	}
+
+	if attr, ok := content.Attributes[attrSeverity]; ok {
+		sev, decodeDiags := decodeString(attr)
+		diags = diags.Extend(decodeDiags)
+
+		switch sev {
+		case severityError:
+			pol.Severity = hcl.DiagError
+		case severityWarning:
+			pol.Severity = hcl.DiagWarning
+		default:
+			if !decodeDiags.HasErrors() {
+				diags = diags.Append(badSeverityDiagnostic(attr.Expr.Range()))
+			}
+		}
+	}
+
+	if attr, ok := content.Attributes[attrMessage]; ok {
+		pol.Message = attr.Expr
+	}
+
+	for _, blk := range content.Blocks {
+		if pol.Match != nil {
+			diags = diags.Append(duplicateBlockDiagnostic(blk.Type, blk.DefRange))
+			continue
+		}
+
+		m, matchDiags := decodeMatchBlock(blk)
+		diags = diags.Extend(matchDiags)
+		pol.Match = m
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return pol, diags
+}
+
+// decodeMatchBlock decodes a "match" block.
+func decodeMatchBlock(blk *hcl.Block) (*Match, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := blk.Body.Content(matchSchema)
+	diags = diags.Extend(contentDiags)
+	if contentDiags.HasErrors() {
+		return nil, diags
+	}
+
+	m := &Match{
+		Category: config.CategoryUnknown,
+	}
+
+	if attr, ok := content.Attributes[attrCategory]; ok {
+		cat, decodeDiags := decodeString(attr)
+		diags = diags.Extend(decodeDiags)
+
+		if m.Category = config.AsComponentCategory(cat); m.Category == config.CategoryUnknown && !decodeDiags.HasErrors() {
+			diags = diags.Append(badCategoryDiagnostic(attr.Expr.Range()))
+		}
+	}
+
+	if attr, ok := content.Attributes[attrTypes]; ok {
+		val, valDiags := attr.Expr.Value(nil)
+		diags = diags.Extend(valDiags)
+
+		if !valDiags.HasErrors() {
+			if err := decodeStringList(val, &m.Types); err != nil {
+				diags = diags.Append(wrongTypeDiagnostic("a list of strings", attr.Expr.Range()))
+			}
+		}
+	}
+
+	return m, diags
+}
+
+// decodeStringList decodes a value of type list of strings into the given
+// slice.
+func decodeStringList(val cty.Value, dst *[]string) error {
+	val, err := convert.Convert(val, cty.List(cty.String))
+	if err != nil {
+		return err
+	}
+	return gocty.FromCtyValue(val, dst)
+}
+
+// decodeString decodes the value of an attribute of type string.
+func decodeString(attr *hcl.Attribute) (string, hcl.Diagnostics) {
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() {
+		return "", diags
+	}
+
+	if val.IsNull() || !val.IsKnown() || val.Type() != cty.String {
+		return "", diags.Append(wrongTypeDiagnostic("a string", attr.Expr.Range()))
+	}
+
+	return val.AsString(), diags
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// readDirDiagnostic returns a hcl.Diagnostic which indicates that the policy
+// files contained in a directory couldn't be listed.
+func readDirDiagnostic(dir string, err error) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Failed to read directory",
+		Detail:   fmt.Sprintf("The policy files in the directory %q couldn't be listed: %s.", dir, err),
+	}
+}
+
+// emptyDirDiagnostic returns a hcl.Diagnostic which indicates that a
+// directory doesn't contain any policy file.
+func emptyDirDiagnostic(dir string) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "No policy file",
+		Detail:   fmt.Sprintf("The directory %q doesn't contain any policy file (*%s).", dir, FileExt),
+	}
+}
+
+// duplicateBlockDiagnostic returns a hcl.Diagnostic which indicates that a
+// block which can only appear once in a policy was declared multiple times.
+func duplicateBlockDiagnostic(blkType string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Duplicate block",
+		Detail:   fmt.Sprintf("A policy can contain only one %q block.", blkType),
+		Subject:  subj.Ptr(),
+	}
+}
+
+// wrongTypeDiagnostic returns a hcl.Diagnostic which indicates that the value
+// of an attribute is of an unexpected type.
+func wrongTypeDiagnostic(expectType string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Wrong type",
+		Detail:   fmt.Sprintf("The value must be %s.", expectType),
+		Subject:  subj.Ptr(),
+	}
+}
+
+// badSeverityDiagnostic returns a hcl.Diagnostic which indicates that the
+// severity of a policy is not supported.
+func badSeverityDiagnostic(subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid severity",
+		Detail:   fmt.Sprintf("The severity of a policy must be either %q or %q.", severityError, severityWarning),
+		Subject:  subj.Ptr(),
+	}
+}
+
+// badCategoryDiagnostic returns a hcl.Diagnostic which indicates that a
+// policy matches an unknown component category.
+func badCategoryDiagnostic(subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unknown category",
+		Detail:   "The value must be the name of a component category, e.g. \"source\".",
+		Subject:  subj.Ptr(),
+	}
+}
+
+// badConditionDiagnostic returns a hcl.Diagnostic which indicates that the
+// condition of a policy didn't evaluate to a boolean.
+func badConditionDiagnostic(subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid condition",
+		Detail:   "The condition of a policy must evaluate to either true or false.",
+		Subject:  subj.Ptr(),
+	}
+}
+
+// violationDiagnostic returns a hcl.Diagnostic which indicates that the given
+// subject violates a policy.
+func violationDiagnostic(pol *Policy, subjDesc, msg string, subj hcl.Range) *hcl.Diagnostic {
+	detail := fmt.Sprintf("%s violates the policy %q.", subjDesc, pol.Name)
+	if msg != "" {
+		detail += " " + msg
+	}
+
+	return &hcl.Diagnostic{
+		Severity: pol.Severity,
+		Summary:  "Policy violation",
+		Detail:   detail,
+		Subject:  subj.Ptr(),
+	}
+}
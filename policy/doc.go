@@ -0,0 +1,45 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates organization rules against Bridges.
+//
+// Policies are declared in HCL files as "policy" blocks. Each policy has a
+// condition which must hold for a Bridge to be compliant. Policies without a
+// "match" block are evaluated once per Bridge, other policies are evaluated
+// once for each component selected by their "match" block:
+//
+//	policy "no_public_services" {
+//	  description = "Container targets must not be exposed publicly."
+//	  severity    = "error" // or "warning"
+//
+//	  match {
+//	    category = "target"
+//	    types    = ["container"]
+//	  }
+//
+//	  condition = config.public != true
+//	}
+//
+// Conditions can use the following variables:
+//
+//   - bridge: identifier, description, owner and delivery settings of the Bridge
+//   - component: category, type and identifier of the evaluated component
+//   - config: decoded configuration of the evaluated component
+//   - manifests: Kubernetes manifests generated for the evaluated component
+//   - images: container images referenced in those manifests
+//
+// Violations are reported as diagnostics which point at the offending block.
+package policy
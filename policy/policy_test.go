@@ -0,0 +1,206 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"til/config/file"
+	"til/core"
+	"til/fs"
+	. "til/policy"
+)
+
+func TestCheck(t *testing.T) {
+	const (
+		bridgeFile = "test.brg.hcl"
+		policyFile = "org" + FileExt
+	)
+
+	memFS := fs.NewMemFS()
+	if err := memFS.CreateFile(bridgeFile, []byte(testBridge)); err != nil {
+		t.Fatal("Failed to create Bridge file:", err)
+	}
+	if err := memFS.CreateFile(policyFile, []byte(testPolicies)); err != nil {
+		t.Fatal("Failed to create policy file:", err)
+	}
+
+	p := &file.Parser{
+		Parser: hclparse.NewParser(),
+		FS:     memFS,
+	}
+
+	brg, diags := p.LoadBridge(bridgeFile)
+	if diags.HasErrors() {
+		t.Fatal("Failed to load Bridge:", diags)
+	}
+	cctx, diags := core.NewContext(brg)
+	if diags.HasErrors() {
+		t.Fatal("Failed to initialize context:", diags)
+	}
+	cctx.FS = memFS
+
+	policies, diags := LoadFile(p, policyFile)
+	if diags.HasErrors() {
+		t.Fatal("Failed to load policies:", diags)
+	}
+
+	type violation struct {
+		severity hcl.DiagnosticSeverity
+		line     int
+		detail   string
+	}
+
+	expect := []violation{{
+		severity: hcl.DiagError,
+		line:     2,
+		detail:   `The Bridge violates the policy "require_dls". Every Bridge must set a dead-letter sink.`,
+	}, {
+		severity: hcl.DiagError,
+		line:     9,
+		detail:   `The component target.public violates the policy "no_public_services".`,
+	}, {
+		severity: hcl.DiagWarning,
+		line:     9,
+		detail: `The component target.public violates the policy "approved_registry". ` +
+			`Unapproved images: docker.io/unapproved:latest`,
+	}}
+
+	var violations []violation
+	for _, d := range Check(cctx, policies) {
+		violations = append(violations, violation{
+			severity: d.Severity,
+			line:     d.Subject.Start.Line,
+			detail:   d.Detail,
+		})
+	}
+
+	if diff := cmp.Diff(expect, violations, cmp.AllowUnexported(violation{})); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	testCases := map[string]struct {
+		policies      string
+		expectSummary string
+	}{
+		"missing condition": {
+			policies:      `policy "p" {}`,
+			expectSummary: "Missing required argument",
+		},
+		"invalid severity": {
+			policies: `
+policy "p" {
+  severity  = "fatal"
+  condition = true
+}`,
+			expectSummary: "Invalid severity",
+		},
+		"unknown category": {
+			policies: `
+policy "p" {
+  match {
+    category = "sink"
+  }
+  condition = true
+}`,
+			expectSummary: "Unknown category",
+		},
+		"duplicate match": {
+			policies: `
+policy "p" {
+  match {}
+  match {}
+  condition = true
+}`,
+			expectSummary: "Duplicate block",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			const policyFile = "test" + FileExt
+
+			memFS := fs.NewMemFS()
+			if err := memFS.CreateFile(policyFile, []byte(tc.policies)); err != nil {
+				t.Fatal("Failed to create policy file:", err)
+			}
+
+			p := &file.Parser{
+				Parser: hclparse.NewParser(),
+				FS:     memFS,
+			}
+
+			_, diags := LoadFile(p, policyFile)
+
+			errDiags := diags.Errs()
+			if len(errDiags) != 1 {
+				t.Fatal("Expected exactly one error diagnostic, got:", diags)
+			}
+			if s := errDiags[0].(*hcl.Diagnostic).Summary; s != tc.expectSummary {
+				t.Errorf("Expected diagnostic %q, got %q", tc.expectSummary, s)
+			}
+		})
+	}
+}
+
+const testBridge = `
+bridge "test" {}
+
+source webhook "hook" {
+  event_type = "my.type"
+  to         = target.public
+}
+
+target container "public" {
+  image  = "docker.io/unapproved:latest"
+  public = true
+}
+
+target container "private" {
+  image = "gcr.io/approved/display:latest"
+}
+`
+
+const testPolicies = `
+policy "require_dls" {
+  description = "Every Bridge must set a dead-letter sink."
+  condition   = bridge.delivery.dead_letter_sink != null
+}
+
+policy "no_public_services" {
+  match {
+    category = "target"
+    types    = ["container", "function"]
+  }
+  condition = config.public != true
+}
+
+policy "approved_registry" {
+  severity = "warning"
+
+  match {}
+
+  condition = length([for img in images : img if !can(regex("^gcr.io/approved/", img))]) == 0
+  message   = "Unapproved images: ${join(", ", images)}"
+}
+`
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package refactor
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// badAddressDiagnostic returns a hcl.Diagnostic which indicates that the
+// given string is not a valid component address.
+func badAddressDiagnostic(a string) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid component address",
+		Detail: fmt.Sprintf("%q is not a valid component address. Addresses have the format "+
+			"<category>.<identifier>, e.g. \"target.my_target\".", a),
+	}
+}
+
+// categoryMismatchDiagnostic returns a hcl.Diagnostic which indicates that a
+// component was requested to be moved to a different category.
+func categoryMismatchDiagnostic(from, to string) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Category mismatch",
+		Detail: fmt.Sprintf("The component %q can not be renamed to %q, because only its identifier "+
+			"can be changed, not its category.", from, to),
+	}
+}
+
+// unknownComponentDiagnostic returns a hcl.Diagnostic which indicates that
+// the Bridge doesn't contain a component with the given address.
+func unknownComponentDiagnostic(a string) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unknown component",
+		Detail:   fmt.Sprintf("The Bridge doesn't contain any component with the address %q.", a),
+	}
+}
+
+// identifierCollisionDiagnostic returns a hcl.Diagnostic which indicates that
+// the Bridge already contains a component with the given address.
+func identifierCollisionDiagnostic(a string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Identifier collision",
+		Detail:   fmt.Sprintf("The Bridge already contains a component with the address %q.", a),
+		Subject:  subj.Ptr(),
+	}
+}
+
+// unmatchedRangeDiagnostic returns a hcl.Diagnostic which indicates that the
+// source code at the given range doesn't contain the expected identifier.
+func unmatchedRangeDiagnostic(id string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Failed to rewrite identifier",
+		Detail: fmt.Sprintf("The identifier %q could not be located in the source code. "+
+			"The file may have been modified while being processed.", id),
+		Subject: subj.Ptr(),
+	}
+}
+
+// writeDiagnostic returns a hcl.Diagnostic which indicates that the rewritten
+// content of the given file could not be serialized.
+func writeDiagnostic(filename string, err error) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Failed to write file",
+		Detail: fmt.Sprintf("The rewritten content of the file %q could not be written. "+
+			"The error was: %s", filename, err),
+	}
+}
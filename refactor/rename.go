@@ -0,0 +1,304 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package refactor
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"til/config"
+	"til/core"
+	"til/graph"
+	"til/lang"
+	"til/tiltest"
+)
+
+// Rename changes the identifier of the component with the address from
+// (e.g. "target.old") to the identifier contained in the address to, and
+// rewrites every reference to that component accordingly.
+//
+// References contained in the given routing tests of the Bridge are rewritten
+// as well.
+//
+// The files map contains the parsed source files of the Bridge and of its
+// tests, indexed by file name. The rewritten content of each modified file is
+// returned, indexed by file name.
+func Rename(cctx *core.Context, files map[string]*hcl.File, tests []*tiltest.Test,
+	from, to string) (map[string][]byte, hcl.Diagnostics) {
+
+	var diags hcl.Diagnostics
+
+	fromCat, fromID, ok := parseComponentAddr(from)
+	if !ok {
+		return nil, diags.Append(badAddressDiagnostic(from))
+	}
+	toCat, toID, ok := parseComponentAddr(to)
+	if !ok {
+		return nil, diags.Append(badAddressDiagnostic(to))
+	}
+	if fromCat != toCat {
+		return nil, diags.Append(categoryMismatchDiagnostic(from, to))
+	}
+
+	g, diags := cctx.Graph()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	cmps := componentVertices(g)
+
+	renamed, exists := cmps[from]
+	if !exists {
+		return nil, diags.Append(unknownComponentDiagnostic(from))
+	}
+	if v, exists := cmps[to]; exists {
+		subj := v.(core.MessagingComponentVertex).ComponentAddr().SourceRange
+		return nil, diags.Append(identifierCollisionDiagnostic(to, subj))
+	}
+
+	// the last label of the block header is the component's identifier
+	edits := []edit{{
+		rng:  renamed.(core.MessagingComponentVertex).ComponentAddr().SourceRange,
+		from: fromID,
+		to:   toID,
+	}}
+
+	rngs := referenceRanges(cctx.Bridge, g, renamed)
+	rngs = append(rngs, testReferenceRanges(tests, from)...)
+
+	for _, rng := range rngs {
+		edits = append(edits, edit{
+			rng:  rng,
+			from: fromID,
+			to:   toID,
+		})
+	}
+
+	out, rwDiags := rewrite(files, edits)
+	diags = diags.Extend(rwDiags)
+
+	return out, diags
+}
+
+// parseComponentAddr parses the category and identifier of a component from
+// the given address.
+func parseComponentAddr(a string) (config.ComponentCategory, string, bool) {
+	parts := strings.Split(a, ".")
+	if len(parts) != 2 || !hclsyntax.ValidIdentifier(parts[1]) {
+		return config.CategoryUnknown, "", false
+	}
+
+	cat := config.AsComponentCategory(parts[0])
+	if cat == config.CategoryUnknown {
+		return config.CategoryUnknown, "", false
+	}
+
+	return cat, parts[1], true
+}
+
+// componentVertices returns the vertices of the given graph which represent
+// messaging components, indexed by address.
+func componentVertices(g *graph.DirectedGraph) map[string]graph.Vertex {
+	cmps := make(map[string]graph.Vertex)
+
+	for _, v := range g.Vertices() {
+		mcv, ok := v.(core.MessagingComponentVertex)
+		if !ok {
+			continue
+		}
+
+		a := mcv.ComponentAddr()
+		cmps[a.Category.String()+"."+a.Identifier] = v
+	}
+
+	return cmps
+}
+
+// referenceRanges returns the source ranges of all references to the given
+// vertex within the Bridge.
+func referenceRanges(brg *config.Bridge, g *graph.DirectedGraph, subj graph.Vertex) []hcl.Range {
+	rm := core.NewReferenceMap(g.Vertices())
+
+	var rngs []hcl.Range
+
+	for _, v := range g.Vertices() {
+		rfr, ok := v.(core.ReferencerVertex)
+		if !ok {
+			continue
+		}
+
+		// diagnostics are already reported by the graph builder
+		refs, _ := rfr.References()
+		for _, ref := range refs {
+			if rm[ref.Subject.Addr()] == subj {
+				rngs = append(rngs, ref.SourceRange)
+			}
+		}
+	}
+
+	if dlv := brg.Delivery; dlv != nil && dlv.DeadLetterSink != nil {
+		if ref, _ := lang.ParseBlockReference(dlv.DeadLetterSink); ref != nil && rm[ref.Subject.Addr()] == subj {
+			rngs = append(rngs, ref.SourceRange)
+		}
+	}
+
+	return rngs
+}
+
+// testReferenceRanges returns the source ranges of all references to the
+// component with the given address within the given routing tests.
+func testReferenceRanges(tests []*tiltest.Test, addr string) []hcl.Range {
+	var rngs []hcl.Range
+
+	appendIfMatch := func(ref *tiltest.ComponentRef) {
+		if ref != nil && ref.Addr == addr {
+			rngs = append(rngs, ref.SourceRange)
+		}
+	}
+
+	for _, t := range tests {
+		appendIfMatch(t.From)
+		for _, exp := range t.Expect {
+			appendIfMatch(exp.Target)
+		}
+		for _, ref := range t.NotReached {
+			appendIfMatch(ref)
+		}
+	}
+
+	return rngs
+}
+
+// edit is the replacement of an identifier located inside a source range.
+type edit struct {
+	rng  hcl.Range
+	from string
+	to   string
+}
+
+// rewrite applies the given edits to the given files, and returns the
+// rewritten content of each modified file.
+func rewrite(files map[string]*hcl.File, edits []edit) (map[string][]byte, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	// The same expression can be shared by multiple components, e.g. when
+	// it is inherited from bridge defaults, so its range may appear more
+	// than once.
+	seen := make(map[hcl.Range]struct{}, len(edits))
+
+	editsByFile := make(map[string][]edit)
+	for _, e := range edits {
+		if _, dupl := seen[e.rng]; dupl {
+			continue
+		}
+		seen[e.rng] = struct{}{}
+
+		editsByFile[e.rng.Filename] = append(editsByFile[e.rng.Filename], e)
+	}
+
+	filenames := make([]string, 0, len(editsByFile))
+	for fn := range editsByFile {
+		filenames = append(filenames, fn)
+	}
+	sort.Strings(filenames)
+
+	out := make(map[string][]byte, len(filenames))
+
+	for _, fn := range filenames {
+		f, exists := files[fn]
+		if !exists {
+			continue
+		}
+
+		wf, parseDiags := hclwrite.ParseConfig(f.Bytes, fn, hcl.InitialPos)
+		diags = diags.Extend(parseDiags)
+		if parseDiags.HasErrors() {
+			continue
+		}
+
+		toks := positionedTokens(wf.BuildTokens(nil))
+
+		for _, e := range editsByFile[fn] {
+			tok := lastIdentifierIn(toks, e.rng)
+			if tok == nil || string(tok.Bytes) != e.from {
+				diags = diags.Append(unmatchedRangeDiagnostic(e.from, e.rng))
+				continue
+			}
+			tok.Bytes = []byte(e.to)
+		}
+
+		// File.Bytes would reformat the entire file, whereas writing the
+		// raw tokens leaves the rest of the file untouched.
+		var b bytes.Buffer
+		if _, err := wf.BuildTokens(nil).WriteTo(&b); err != nil {
+			diags = diags.Append(writeDiagnostic(fn, err))
+			continue
+		}
+		out[fn] = b.Bytes()
+	}
+
+	return out, diags
+}
+
+// positionedToken is a hclwrite.Token annotated with its byte offset in the
+// source file.
+type positionedToken struct {
+	*hclwrite.Token
+	offset int
+}
+
+// positionedTokens computes the byte offset of each of the given tokens,
+// which are expected to be in the same order as in the source file.
+func positionedTokens(toks hclwrite.Tokens) []positionedToken {
+	ptoks := make([]positionedToken, len(toks))
+
+	var offs int
+	for i, t := range toks {
+		offs += t.SpacesBefore
+		ptoks[i] = positionedToken{Token: t, offset: offs}
+		offs += len(t.Bytes)
+	}
+
+	return ptoks
+}
+
+// lastIdentifierIn returns the last identifier or literal string token
+// contained in the given source range, such as the identifier label of a
+// block header or the identifier part of a block reference.
+func lastIdentifierIn(toks []positionedToken, rng hcl.Range) *hclwrite.Token {
+	var last *hclwrite.Token
+
+	for _, t := range toks {
+		if t.offset < rng.Start.Byte {
+			continue
+		}
+		if t.offset+len(t.Bytes) > rng.End.Byte {
+			break
+		}
+
+		if t.Type == hclsyntax.TokenIdent || t.Type == hclsyntax.TokenQuotedLit {
+			last = t.Token
+		}
+	}
+
+	return last
+}
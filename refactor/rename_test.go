@@ -0,0 +1,245 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package refactor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"til/config/file"
+	"til/core"
+	"til/fs"
+	. "til/refactor"
+	"til/tiltest"
+)
+
+func TestRename(t *testing.T) {
+	const (
+		bridgeFile = "test.brg.hcl"
+		testsFile  = "test.tiltest.hcl"
+	)
+
+	testCases := map[string]struct {
+		from, to string
+		// substitutions expected in the rewritten Bridge, as old/new pairs
+		expectSubst []string
+		// substitutions expected in the rewritten tests, as old/new pairs
+		expectTestsSubst []string
+		expectErr        bool
+	}{
+		"target referenced by multiple components": {
+			from: "target.slack_stats",
+			to:   "target.stats",
+			expectSubst: []string{
+				"to = target.slack_stats # comment", "to = target.stats # comment",
+				"[target.slack_stats, target.archive]", "[target.stats, target.archive]",
+				`target container "slack_stats" {`, `target container "stats" {`,
+			},
+			expectTestsSubst: []string{
+				"target = target.slack_stats", "target = target.stats",
+				"[target.slack_stats]", "[target.stats]",
+			},
+		},
+		"source referenced by tests": {
+			from: "source.heartbeat",
+			to:   "source.ping",
+			expectSubst: []string{
+				`source ping "heartbeat" {`, `source ping "ping" {`,
+			},
+			expectTestsSubst: []string{
+				"from = source.heartbeat", "from = source.ping",
+			},
+		},
+		"unquoted label": {
+			from: "target.archive",
+			to:   "target.storage",
+			expectSubst: []string{
+				"[target.slack_stats, target.archive]", "[target.slack_stats, target.storage]",
+				"reply_to = target.archive", "reply_to = target.storage",
+				"target container archive {", "target container storage {",
+			},
+			expectTestsSubst: []string{
+				"target = target.archive", "target = target.storage",
+			},
+		},
+		"dead-letter sink": {
+			from: "target.dls",
+			to:   "target.dead_letters",
+			expectSubst: []string{
+				"dead_letter_sink = target.dls", "dead_letter_sink = target.dead_letters",
+				`target container "dls" {`, `target container "dead_letters" {`,
+			},
+		},
+		"collision with existing identifier": {
+			from:      "target.slack_stats",
+			to:        "target.archive",
+			expectErr: true,
+		},
+		"unknown component": {
+			from:      "target.nope",
+			to:        "target.yes",
+			expectErr: true,
+		},
+		"category change": {
+			from:      "target.slack_stats",
+			to:        "channel.slack_stats",
+			expectErr: true,
+		},
+		"invalid identifier": {
+			from:      "target.slack_stats",
+			to:        "target.slack-stats!",
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			memFS := fs.NewMemFS()
+			if err := memFS.CreateFile(bridgeFile, []byte(testBridge)); err != nil {
+				t.Fatal("Failed to create Bridge file:", err)
+			}
+			if err := memFS.CreateFile(testsFile, []byte(testTests)); err != nil {
+				t.Fatal("Failed to create tests file:", err)
+			}
+
+			p := &file.Parser{
+				Parser: hclparse.NewParser(),
+				FS:     memFS,
+			}
+
+			brg, diags := p.LoadBridge(bridgeFile)
+			if diags.HasErrors() {
+				t.Fatal("Failed to load Bridge:", diags)
+			}
+			cctx, diags := core.NewContext(brg)
+			if diags.HasErrors() {
+				t.Fatal("Failed to initialize context:", diags)
+			}
+
+			tests, diags := tiltest.LoadFile(p, testsFile)
+			if diags.HasErrors() {
+				t.Fatal("Failed to load tests:", diags)
+			}
+
+			out, diags := Rename(cctx, p.Files(), tests, tc.from, tc.to)
+
+			if tc.expectErr {
+				if !diags.HasErrors() {
+					t.Fatal("Expected an error")
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatal("Unexpected diagnostics:", diags)
+			}
+
+			expect := strings.NewReplacer(tc.expectSubst...).Replace(testBridge)
+
+			if diff := cmp.Diff(expect, string(out[bridgeFile])); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+
+			gotTests, isRewritten := out[testsFile]
+			if len(tc.expectTestsSubst) == 0 {
+				if isRewritten {
+					t.Error("Tests file was rewritten but shouldn't have been")
+				}
+				return
+			}
+
+			expectTests := strings.NewReplacer(tc.expectTestsSubst...).Replace(testTests)
+
+			if diff := cmp.Diff(expectTests, string(gotTests)); diff != "" {
+				t.Error("Unexpected diff in tests: (-:expect, +:got)", diff)
+			}
+		})
+	}
+}
+
+const testBridge = `
+bridge "test" {
+  delivery {
+    dead_letter_sink = target.dls
+  }
+}
+
+source ping "heartbeat" {
+  data = "{}"
+  to   = router.dispatch
+}
+
+router content_based "dispatch" {
+  route {
+    attributes = { type = "stats" }
+    to = target.slack_stats # comment
+  }
+  route {
+    attributes = { type = "archive" }
+    to = channel.fanout
+  }
+}
+
+channel pubsub "fanout" {
+  subscribers = [target.slack_stats, target.archive]
+}
+
+target container "slack_stats" {
+  image = "stats"
+  reply_to = target.archive
+}
+
+target container archive {
+  image = "archive"
+}
+
+target container "dls" {
+  image = "dls"
+}
+`
+
+const testTests = `
+test "stats_are_posted" {
+  from = source.heartbeat
+
+  event {
+    type   = "stats"
+    source = "test"
+  }
+
+  expect {
+    target = target.slack_stats
+  }
+}
+
+test "archives_are_stored" {
+  from = source.heartbeat
+
+  event {
+    type   = "archive"
+    source = "test"
+  }
+
+  expect {
+    target = target.archive
+  }
+
+  not_reached = [target.slack_stats]
+}
+`
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"til/cli"
+	"til/config/file"
+	"til/core"
+	"til/refactor"
+	"til/tiltest"
+)
+
+type RenameCommand struct {
+	// flags
+	diagnosticsOptions
+}
+
+// Run implements cli.Command.
+func (c *RenameCommand) Run(ctx context.Context, args []string) error {
+	flagSet := cli.FlagSetFromContext(ctx)
+	setUsageFn(flagSet, usageRename)
+
+	c.diagnosticsOptions.addFlags(flagSet)
+
+	pos, flags := splitArgs(3, args)
+	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
+
+	if len(pos) != 3 {
+		return fmt.Errorf("unexpected number of positional arguments.\n\n%s", usageRename(flagSet.Name()))
+	}
+	filePath, from, to := pos[0], pos[1], pos[2]
+
+	ui := cli.UIFromContext(ctx)
+
+	// Disabled components are loaded too, since their references to the
+	// renamed component must be rewritten as well.
+	p := file.NewParser()
+	p.IgnoreEnabled = true
+	brg, diags := p.LoadBridge(filePath)
+
+	dw := c.diagnosticWriter(ui, p.Files())
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errLoadBridge
+	}
+
+	cctx, diags := core.NewContext(brg)
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errInitContext
+	}
+
+	// References contained in the routing tests located next to the
+	// Bridge description must be rewritten too, otherwise those tests
+	// would refer to a component which no longer exists.
+	testFiles, err := filepath.Glob(filepath.Join(filepath.Dir(filePath), "*"+tiltest.FileExt))
+	if err != nil {
+		return fmt.Errorf("listing test files: %w", err)
+	}
+
+	var tests []*tiltest.Test
+	for _, tf := range testFiles {
+		ts, testDiags := tiltest.LoadFile(p, tf)
+		diags = diags.Extend(testDiags)
+		tests = append(tests, ts...)
+	}
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errors.New("failed to load tests. See error diagnostics")
+	}
+
+	out, renameDiags := refactor.Rename(cctx, p.Files(), tests, from, to)
+	diags = diags.Extend(renameDiags)
+	if len(diags) > 0 {
+		_ = dw.WriteDiagnostics(diags)
+	}
+	if diags.HasErrors() {
+		return errors.New("failed to rename component. See error diagnostics")
+	}
+
+	filenames := make([]string, 0, len(out))
+	for fn := range out {
+		filenames = append(filenames, fn)
+	}
+	sort.Strings(filenames)
+
+	for _, fn := range filenames {
+		fi, err := os.Stat(fn)
+		if err != nil {
+			return fmt.Errorf("writing renamed file: %w", err)
+		}
+		if err := ioutil.WriteFile(fn, out[fn], fi.Mode().Perm()); err != nil {
+			return fmt.Errorf("writing renamed file: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"til/catalog"
+	"til/catalog/jsonschema"
+	"til/cli"
+	"til/config"
+)
+
+type SchemaCommand struct{}
+
+// Run implements cli.Command.
+func (c *SchemaCommand) Run(ctx context.Context, args []string) error {
+	flagSet := cli.FlagSetFromContext(ctx)
+	setUsageFn(flagSet, usageSchema)
+
+	pos, flags := splitArgs(countPositional(2, args), args)
+	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
+
+	if flagSet.NArg() > 0 {
+		return fmt.Errorf("unexpected number of positional arguments.\n\n%s", usageSchema(flagSet.Name()))
+	}
+
+	cats := catalog.Categories()
+
+	if len(pos) > 0 {
+		cat := config.AsComponentCategory(pos[0])
+		if cat == config.CategoryUnknown {
+			return fmt.Errorf("unknown component category %q. Valid categories are: %s",
+				pos[0], strings.Join(categoryNames(), ", "))
+		}
+		cats = []config.ComponentCategory{cat}
+	}
+
+	var s *jsonschema.Schema
+
+	if len(pos) == 2 {
+		cmpType, ok := catalog.Lookup(cats[0], pos[1])
+		if !ok {
+			return fmt.Errorf("unknown %s type %q. Run %q to list all supported types",
+				cats[0], pos[1], cmdExplain+" "+cats[0].String())
+		}
+		s = jsonschema.ForComponentType(cmpType)
+
+	} else {
+		var cmpTypes []*catalog.ComponentType
+		for _, cat := range cats {
+			for _, typ := range catalog.Types(cat) {
+				cmpType, _ := catalog.Lookup(cat, typ)
+				cmpTypes = append(cmpTypes, cmpType)
+			}
+		}
+		s = jsonschema.ForComponentTypes(cmpTypes)
+	}
+
+	enc := json.NewEncoder(cli.UIFromContext(ctx).StdWriter)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("writing JSON Schema: %w", err)
+	}
+
+	return nil
+}
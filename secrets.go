@@ -0,0 +1,134 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"til/cli"
+	"til/core"
+	"til/encoding"
+	"til/secrets"
+)
+
+type SecretsCommand struct {
+	// flags
+	template            bool
+	externalSecretStore string
+	yaml                bool
+	variableOptions
+	diagnosticsOptions
+}
+
+// Run implements cli.Command.
+func (c *SecretsCommand) Run(ctx context.Context, args []string) error {
+	flagSet := cli.FlagSetFromContext(ctx)
+	setUsageFn(flagSet, usageSecrets)
+
+	flagSet.BoolVar(&c.template, "template", false, "")
+	flagSet.StringVar(&c.externalSecretStore, "external-secret-store", "", "")
+	flagSet.BoolVar(&c.yaml, "yaml", false, "")
+	c.variableOptions.addFlags(flagSet)
+	c.diagnosticsOptions.addFlags(flagSet)
+
+	pos, flags := splitArgs(1, args)
+	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
+
+	if len(pos) != 1 {
+		return fmt.Errorf("unexpected number of positional arguments.\n\n%s", usageSecrets(flagSet.Name()))
+	}
+	filePath := pos[0]
+
+	if c.externalSecretStore != "" {
+		c.template = true
+	}
+	if c.yaml && !c.template {
+		return fmt.Errorf("the --yaml option applies to --template only.\n\n%s", usageSecrets(flagSet.Name()))
+	}
+
+	ui := cli.UIFromContext(ctx)
+
+	p := c.variableOptions.newParser()
+	brg, diags := p.LoadBridge(filePath)
+
+	dw := c.diagnosticWriter(ui, p.Files())
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errLoadBridge
+	}
+
+	cctx, diags := core.NewContext(brg)
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errInitContext
+	}
+
+	secrs, diags := secrets.Inventory(cctx)
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errGenerate
+	}
+
+	if !c.template {
+		writeSecrets(ui.StdWriter, secrs)
+		return nil
+	}
+
+	var manifests []interface{}
+	if c.externalSecretStore != "" {
+		manifests = secrets.ExternalSecretManifests(secrs, c.externalSecretStore)
+	} else {
+		manifests = secrets.Manifests(secrs)
+	}
+
+	brgID := brg.Identifier
+	if brgID == "" {
+		brgID = defaultBridgeIdentifier
+	}
+	s := encoding.NewSerializer(brgID)
+
+	if c.yaml {
+		return s.WriteManifestsYAML(ui.StdWriter, manifests)
+	}
+	return s.WriteManifestsJSON(ui.StdWriter, manifests)
+}
+
+// writeSecrets writes a human-readable list of the given Secrets to w.
+func writeSecrets(w io.Writer, secrs []*secrets.Secret) {
+	for i, s := range secrs {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		usedBy := make([]string, len(s.Components))
+		for j, cmp := range s.Components {
+			usedBy[j] = cmp.Category.String() + "." + cmp.Identifier
+		}
+
+		keys := strings.Join(s.Keys, ", ")
+		if keys == "" {
+			keys = "(whole Secret)"
+		}
+
+		fmt.Fprintf(w, "%s:\n", s.Name)
+		fmt.Fprintf(w, "    keys:    %s\n", keys)
+		fmt.Fprintf(w, "    used by: %s\n", strings.Join(usedBy, ", "))
+	}
+}
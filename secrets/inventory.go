@@ -0,0 +1,166 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config/addr"
+	"til/core"
+)
+
+// Secret is a Kubernetes Secret referenced by a Bridge.
+type Secret struct {
+	// Name of the Secret.
+	Name string
+	// Keys of the Secret which are read by components, sorted
+	// alphabetically. Empty if components consume the Secret as a whole.
+	Keys []string
+	// Components which reference the Secret, sorted by category and
+	// identifier.
+	Components []addr.MessagingComponent
+}
+
+// Inventory returns the Kubernetes Secrets referenced in the manifests
+// generated for the given Bridge, sorted by name.
+func Inventory(cctx *core.Context) ([]*Secret, hcl.Diagnostics) {
+	cmps, diags := cctx.GenerateComponents()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return FromComponents(cmps), diags
+}
+
+// FromComponents returns the Kubernetes Secrets referenced in the given
+// component manifests, sorted by name.
+func FromComponents(cmps []*core.ComponentManifests) []*Secret {
+	idx := make(map[string]*secretRefs)
+
+	for _, cmp := range cmps {
+		for _, m := range cmp.Manifests {
+			u, ok := m.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+
+			walkObject(u.Object, func(name, key string) {
+				refs, ok := idx[name]
+				if !ok {
+					refs = &secretRefs{
+						keys: make(map[string]struct{}),
+						cmps: make(map[addr.MessagingComponent]struct{}),
+					}
+					idx[name] = refs
+				}
+
+				if key != "" {
+					refs.keys[key] = struct{}{}
+				}
+				if _, seen := refs.cmps[cmp.Component]; !seen {
+					refs.cmps[cmp.Component] = struct{}{}
+					refs.list = append(refs.list, cmp.Component)
+				}
+			})
+		}
+	}
+
+	secrs := make([]*Secret, 0, len(idx))
+	for name, refs := range idx {
+		keys := make([]string, 0, len(refs.keys))
+		for k := range refs.keys {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		cmps := refs.list
+		sort.Slice(cmps, func(i, j int) bool {
+			if cmps[i].Category != cmps[j].Category {
+				return cmps[i].Category < cmps[j].Category
+			}
+			return cmps[i].Identifier < cmps[j].Identifier
+		})
+
+		secrs = append(secrs, &Secret{
+			Name:       name,
+			Keys:       keys,
+			Components: cmps,
+		})
+	}
+
+	sort.Slice(secrs, func(i, j int) bool {
+		return secrs[i].Name < secrs[j].Name
+	})
+
+	return secrs
+}
+
+// secretRefs accumulates references to a single Secret.
+type secretRefs struct {
+	keys map[string]struct{}
+	cmps map[addr.MessagingComponent]struct{}
+	list []addr.MessagingComponent
+}
+
+// walkObject walks the given object recursively and calls fn for each
+// reference to a Kubernetes Secret. The key is empty when the reference
+// targets the Secret as a whole.
+func walkObject(obj map[string]interface{}, fn func(name, key string)) {
+	for k, v := range obj {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if name, key, ok := secretRef(k, v); ok {
+				fn(name, key)
+				continue
+			}
+			walkObject(v, fn)
+
+		case []interface{}:
+			for _, e := range v {
+				if m, ok := e.(map[string]interface{}); ok {
+					walkObject(m, fn)
+				}
+			}
+		}
+	}
+}
+
+// secretRef returns the name and key of the Secret referenced by the given
+// field, if the field is a known type of Secret reference.
+func secretRef(field string, v map[string]interface{}) (name, key string, ok bool) {
+	switch field {
+	case "secretKeyRef", "valueFromSecret":
+		// corev1.SecretKeySelector
+		name, ok = v["name"].(string)
+		key, _ = v["key"].(string)
+		return name, key, ok
+
+	case "secret":
+		// reference to a whole Secret, e.g. {secret: {ref: {name: x}}}
+		ref, isMap := v["ref"].(map[string]interface{})
+		if !isMap {
+			return "", "", false
+		}
+		name, ok = ref["name"].(string)
+		return name, "", ok
+	}
+
+	return "", "", false
+}
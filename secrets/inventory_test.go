@@ -0,0 +1,107 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"til/config"
+	"til/config/addr"
+	"til/core"
+	. "til/secrets"
+)
+
+func TestFromComponents(t *testing.T) {
+	src := addr.MessagingComponent{Category: config.CategorySources, Type: "some_source", Identifier: "src"}
+	trg := addr.MessagingComponent{Category: config.CategoryTargets, Type: "some_target", Identifier: "trg"}
+	kafka := addr.MessagingComponent{Category: config.CategoryTargets, Type: "kafka", Identifier: "kafka"}
+
+	cmps := []*core.ComponentManifests{{
+		Component: trg,
+		Manifests: []interface{}{
+			&unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"env": []interface{}{
+						map[string]interface{}{
+							"name": "TOKEN",
+							"valueFrom": map[string]interface{}{
+								"secretKeyRef": map[string]interface{}{
+									"name": "shared",
+									"key":  "token",
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}, {
+		Component: src,
+		Manifests: []interface{}{
+			&unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"user": map[string]interface{}{
+						"valueFromSecret": map[string]interface{}{
+							"name": "shared",
+							"key":  "user",
+						},
+					},
+					"password": map[string]interface{}{
+						"valueFromSecret": map[string]interface{}{
+							"name": "shared",
+							"key":  "password",
+						},
+					},
+				},
+			}},
+		},
+	}, {
+		Component: kafka,
+		Manifests: []interface{}{
+			&unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"auth": map[string]interface{}{
+						"secret": map[string]interface{}{
+							"ref": map[string]interface{}{
+								"name": "kafka-auth",
+							},
+						},
+					},
+				},
+			}},
+		},
+	}}
+
+	expect := []*Secret{{
+		Name:       "kafka-auth",
+		Keys:       []string{},
+		Components: []addr.MessagingComponent{kafka},
+	}, {
+		Name:       "shared",
+		Keys:       []string{"password", "token", "user"},
+		Components: []addr.MessagingComponent{src, trg},
+	}}
+
+	secrs := FromComponents(cmps)
+
+	if diff := cmp.Diff(expect, secrs); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
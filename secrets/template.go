@@ -0,0 +1,107 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// API version of ExternalSecret objects.
+// https://external-secrets.io/latest/api/externalsecret/
+const apiExternalSecrets = "external-secrets.io/v1beta1"
+
+// Manifests returns placeholder Kubernetes Secret objects for the given
+// Secrets. Each object contains all the keys expected by the components that
+// reference the Secret, with empty values.
+func Manifests(secrs []*Secret) []interface{} {
+	manifests := make([]interface{}, 0, len(secrs))
+
+	for _, s := range secrs {
+		data := make(map[string]interface{}, len(s.Keys))
+		for _, k := range s.Keys {
+			data[k] = ""
+		}
+
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"type":       "Opaque",
+			"stringData": data,
+		}}
+		u.SetAPIVersion("v1")
+		u.SetKind("Secret")
+		u.SetName(s.Name)
+
+		manifests = append(manifests, u)
+	}
+
+	return manifests
+}
+
+// ExternalSecretManifests returns ExternalSecret objects which synchronize
+// the given Secrets from the SecretStore with the given name.
+//
+// Each key of a Secret is read from the property of the same name of a
+// remote secret named after the Secret. Secrets which are referenced without
+// any specific key are extracted as a whole from the remote secret.
+func ExternalSecretManifests(secrs []*Secret, store string) []interface{} {
+	manifests := make([]interface{}, 0, len(secrs))
+
+	for _, s := range secrs {
+		spec := map[string]interface{}{
+			"refreshInterval": "1h",
+			"secretStoreRef": map[string]interface{}{
+				"kind": "SecretStore",
+				"name": store,
+			},
+			"target": map[string]interface{}{
+				"name": s.Name,
+			},
+		}
+
+		if len(s.Keys) == 0 {
+			spec["dataFrom"] = []interface{}{
+				map[string]interface{}{
+					"extract": map[string]interface{}{
+						"key": s.Name,
+					},
+				},
+			}
+		} else {
+			data := make([]interface{}, 0, len(s.Keys))
+			for _, k := range s.Keys {
+				data = append(data, map[string]interface{}{
+					"secretKey": k,
+					"remoteRef": map[string]interface{}{
+						"key":      s.Name,
+						"property": k,
+					},
+				})
+			}
+			spec["data"] = data
+		}
+
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": spec,
+		}}
+		u.SetAPIVersion(apiExternalSecrets)
+		u.SetKind("ExternalSecret")
+		u.SetName(s.Name)
+
+		manifests = append(manifests, u)
+	}
+
+	return manifests
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	. "til/secrets"
+)
+
+func TestManifests(t *testing.T) {
+	secrs := []*Secret{{
+		Name: "creds",
+		Keys: []string{"password", "user"},
+	}}
+
+	expect := []interface{}{
+		&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name": "creds",
+			},
+			"type": "Opaque",
+			"stringData": map[string]interface{}{
+				"password": "",
+				"user":     "",
+			},
+		}},
+	}
+
+	if diff := cmp.Diff(expect, Manifests(secrs)); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+func TestExternalSecretManifests(t *testing.T) {
+	secrs := []*Secret{{
+		Name: "creds",
+		Keys: []string{"token"},
+	}, {
+		Name: "whole",
+		Keys: []string{},
+	}}
+
+	newExternalSecret := func(name string, data map[string]interface{}) *unstructured.Unstructured {
+		spec := map[string]interface{}{
+			"refreshInterval": "1h",
+			"secretStoreRef": map[string]interface{}{
+				"kind": "SecretStore",
+				"name": "my-store",
+			},
+			"target": map[string]interface{}{
+				"name": name,
+			},
+		}
+		for k, v := range data {
+			spec[k] = v
+		}
+
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "external-secrets.io/v1beta1",
+			"kind":       "ExternalSecret",
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+			"spec": spec,
+		}}
+	}
+
+	expect := []interface{}{
+		newExternalSecret("creds", map[string]interface{}{
+			"data": []interface{}{
+				map[string]interface{}{
+					"secretKey": "token",
+					"remoteRef": map[string]interface{}{
+						"key":      "creds",
+						"property": "token",
+					},
+				},
+			},
+		}),
+		newExternalSecret("whole", map[string]interface{}{
+			"dataFrom": []interface{}{
+				map[string]interface{}{
+					"extract": map[string]interface{}{
+						"key": "whole",
+					},
+				},
+			},
+		}),
+	}
+
+	if diff := cmp.Diff(expect, ExternalSecretManifests(secrs, "my-store")); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
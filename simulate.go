@@ -0,0 +1,106 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"til/cli"
+	"til/core"
+	"til/simulation"
+)
+
+type SimulateCommand struct {
+	// flags
+	event string
+	from  string
+	variableOptions
+	diagnosticsOptions
+}
+
+// Run implements cli.Command.
+func (c *SimulateCommand) Run(ctx context.Context, args []string) error {
+	flagSet := cli.FlagSetFromContext(ctx)
+	setUsageFn(flagSet, usageSimulate)
+
+	flagSet.StringVar(&c.event, "event", "", "")
+	flagSet.StringVar(&c.from, "from", "", "")
+	c.variableOptions.addFlags(flagSet)
+	c.diagnosticsOptions.addFlags(flagSet)
+
+	pos, flags := splitArgs(1, args)
+	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
+
+	if len(pos) != 1 {
+		return fmt.Errorf("unexpected number of positional arguments.\n\n%s", usageSimulate(flagSet.Name()))
+	}
+	filePath := pos[0]
+
+	if c.event == "" || c.from == "" {
+		return fmt.Errorf("the --event and --from options are required.\n\n%s", usageSimulate(flagSet.Name()))
+	}
+
+	ui := cli.UIFromContext(ctx)
+
+	var evData []byte
+	var err error
+	if c.event == "-" {
+		evData, err = ioutil.ReadAll(ui.StdReader)
+	} else {
+		evData, err = ioutil.ReadFile(c.event)
+	}
+	if err != nil {
+		return fmt.Errorf("reading event: %w", err)
+	}
+
+	ev, err := simulation.ParseEvent(evData)
+	if err != nil {
+		return err
+	}
+
+	p := c.variableOptions.newParser()
+	brg, diags := p.LoadBridge(filePath)
+
+	dw := c.diagnosticWriter(ui, p.Files())
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errLoadBridge
+	}
+
+	cctx, diags := core.NewContext(brg)
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errInitContext
+	}
+
+	sim, diags := simulation.New(cctx)
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errors.New("failed to build bridge graph. See error diagnostics")
+	}
+
+	res, diags := sim.Run(c.from, ev)
+	if diags.HasErrors() {
+		_ = dw.WriteDiagnostics(diags)
+		return errors.New("failed to simulate event flow. See error diagnostics")
+	}
+
+	return simulation.WriteTree(ui.StdWriter, res)
+}
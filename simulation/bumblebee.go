@@ -0,0 +1,319 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// bumblebeeOperation is a transformation operation of a "bumblebee"
+// transformer.
+type bumblebeeOperation struct {
+	operation string
+	paths     []bumblebeePath
+}
+
+type bumblebeePath struct {
+	key   string
+	value string
+}
+
+// decodeBumblebeeOperations decodes the operations of a "context" or "data"
+// block of a "bumblebee" transformer.
+func decodeBumblebeeOperations(v cty.Value) []bumblebeeOperation {
+	if v.IsNull() || !v.IsKnown() {
+		return nil
+	}
+
+	var ops []bumblebeeOperation
+
+	for _, opVal := range v.AsValueSlice() {
+		opValMap := opVal.AsValueMap()
+
+		op := bumblebeeOperation{
+			operation: opValMap["operation"].AsString(),
+		}
+
+		for _, pathVal := range opValMap["path"].AsValueSlice() {
+			var p bumblebeePath
+
+			pathValMap := pathVal.AsValueMap()
+			if key := pathValMap["key"]; !key.IsNull() {
+				p.key = key.AsString()
+			}
+			if value := pathValMap["value"]; !value.IsNull() {
+				p.value = value.AsString()
+			}
+
+			op.paths = append(op.paths, p)
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops
+}
+
+// applyBumblebee applies the transformations configured in a "bumblebee"
+// transformer to a copy of the given event.
+//
+// Like the actual transformation engine, all "store" operations are evaluated
+// first against the original event, so that the stored variables are available
+// to all other operations regardless of their order.
+func applyBumblebee(cfg cty.Value, ev *Event) (*Event, error) {
+	ctxOps := decodeBumblebeeOperations(cfg.GetAttr("context"))
+	dataOps := decodeBumblebeeOperations(cfg.GetAttr("data"))
+
+	ceCtx := make(map[string]interface{}, len(ev.Context))
+	for k, v := range ev.Context {
+		ceCtx[k] = v
+	}
+
+	vars := make(map[string]interface{})
+
+	if err := storeVariables(vars, ctxOps, ceCtx); err != nil {
+		return nil, err
+	}
+	if err := storeVariables(vars, dataOps, ev.Data); err != nil {
+		return nil, err
+	}
+
+	out := ev.Copy()
+
+	newCtx, err := applyOperations(ctxOps, copyValue(ceCtx), vars)
+	if err != nil {
+		return nil, fmt.Errorf("transforming context: %w", err)
+	}
+
+	ctxMap, ok := newCtx.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("transforming context: the event context was replaced with %s", describe(newCtx))
+	}
+	out.Context = make(map[string]string, len(ctxMap))
+	for k, v := range ctxMap {
+		out.Context[k] = stringify(v)
+	}
+
+	if out.Data, err = applyOperations(dataOps, out.Data, vars); err != nil {
+		return nil, fmt.Errorf("transforming data: %w", err)
+	}
+
+	return out, nil
+}
+
+// storeVariables evaluates all "store" operations against the given document.
+func storeVariables(vars map[string]interface{}, ops []bumblebeeOperation, doc interface{}) error {
+	for _, op := range ops {
+		if op.operation != "store" {
+			continue
+		}
+
+		for _, p := range op.paths {
+			segs, err := parsePath(p.value)
+			if err != nil {
+				return err
+			}
+			if v, ok := lookupPath(doc, segs); ok {
+				vars[p.key] = copyValue(v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyOperations applies all operations except "store" to the given document,
+// and returns the updated document.
+func applyOperations(ops []bumblebeeOperation, doc interface{}, vars map[string]interface{}) (interface{}, error) {
+	for _, op := range ops {
+		for _, p := range op.paths {
+			var err error
+
+			switch op.operation {
+			case "store":
+				continue
+			case "add":
+				doc, err = opAdd(doc, p, vars)
+			case "delete":
+				doc, err = opDelete(doc, p)
+			case "shift":
+				doc, err = opShift(doc, p)
+			case "parse":
+				doc, err = opParse(doc, p)
+			default:
+				err = fmt.Errorf("unsupported operation %q", op.operation)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// opAdd sets the value at the path in the key, after substituting stored
+// variables in the value.
+func opAdd(doc interface{}, p bumblebeePath, vars map[string]interface{}) (interface{}, error) {
+	segs, err := parsePath(p.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return setPath(doc, segs, substituteVariables(p.value, vars)), nil
+}
+
+// opDelete deletes the value at the path in the key. If a value is set, only
+// values equal to it are deleted, anywhere in the document if the key is
+// empty. If both the key and the value are empty, the whole document is
+// deleted.
+func opDelete(doc interface{}, p bumblebeePath) (interface{}, error) {
+	switch {
+	case p.key == "" && p.value == "":
+		return nil, nil
+
+	case p.key == "":
+		return deleteValues(doc, p.value), nil
+	}
+
+	segs, err := parsePath(p.key)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.value != "" {
+		if v, ok := lookupPath(doc, segs); !ok || stringify(v) != p.value {
+			return doc, nil
+		}
+	}
+
+	return deletePath(doc, segs), nil
+}
+
+// deleteValues recursively deletes all object members which value is equal to
+// the given value.
+func deleteValues(doc interface{}, value string) interface{} {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		for k, v := range doc {
+			switch v.(type) {
+			case map[string]interface{}, []interface{}:
+				doc[k] = deleteValues(v, value)
+			default:
+				if stringify(v) == value {
+					delete(doc, k)
+				}
+			}
+		}
+	case []interface{}:
+		for i, v := range doc {
+			doc[i] = deleteValues(v, value)
+		}
+	}
+
+	return doc
+}
+
+// opShift moves the value at the path before the colon in the key to the path
+// after the colon. If a value is set, only a value equal to it is moved.
+func opShift(doc interface{}, p bumblebeePath) (interface{}, error) {
+	paths := strings.SplitN(p.key, ":", 2)
+	if len(paths) != 2 {
+		return nil, fmt.Errorf(`invalid shift key %q, expected "<from>:<to>"`, p.key)
+	}
+
+	from, err := parsePath(paths[0])
+	if err != nil {
+		return nil, err
+	}
+	to, err := parsePath(paths[1])
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := lookupPath(doc, from)
+	if !ok || (p.value != "" && stringify(v) != p.value) {
+		return doc, nil
+	}
+
+	doc = deletePath(doc, from)
+
+	return setPath(doc, to, v), nil
+}
+
+// opParse parses the string at the path in the key using the format in the
+// value.
+func opParse(doc interface{}, p bumblebeePath) (interface{}, error) {
+	if !strings.EqualFold(p.value, "json") {
+		return nil, fmt.Errorf("unsupported parse format %q", p.value)
+	}
+
+	segs, err := parsePath(p.key)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := lookupPath(doc, segs)
+	if !ok {
+		return doc, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return doc, nil
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(s), &parsed); err != nil {
+		return nil, fmt.Errorf("parsing %q as JSON: %w", p.key, err)
+	}
+
+	return setPath(doc, segs, parsed), nil
+}
+
+// substituteVariables replaces references to stored variables in the given
+// value. If the value consists of a single reference, the stored value is
+// returned as is, without being converted to a string.
+func substituteVariables(value string, vars map[string]interface{}) interface{} {
+	if v, ok := vars[value]; ok {
+		return v
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	// substitute longer names first, in case a name is a prefix of another
+	sort.Slice(names, func(i, j int) bool {
+		return len(names[i]) > len(names[j])
+	})
+
+	for _, name := range names {
+		val := stringify(vars[name])
+		if bare := strings.TrimPrefix(name, "$"); bare != name {
+			value = strings.ReplaceAll(value, "${"+bare+"}", val)
+		}
+		value = strings.ReplaceAll(value, name, val)
+	}
+
+	return value
+}
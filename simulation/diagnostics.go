@@ -0,0 +1,34 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// unknownComponentDiagnostic returns a hcl.Diagnostic which indicates that the
+// component selected as the origin of a simulation does not exist.
+func unknownComponentDiagnostic(address string) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unknown component",
+		Detail: fmt.Sprintf("The Bridge doesn't contain any component with the address %q. "+
+			"Addresses have the format <category>.<identifier>, e.g. \"source.my_source\".", address),
+	}
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulation simulates the flow of CloudEvents through the components
+// of a Bridge, without deploying it.
+//
+// Starting from a given component, an event is propagated along the edges of
+// the Bridge's graph. Components which filter, route, split or transform
+// events are evaluated offline based on their configuration, and the payload
+// of the event is tracked at every step of its journey.
+//
+// Components which behaviour depends on code or services that can not be
+// evaluated offline (e.g. functions, replies from targets) are represented as
+// opaque stops.
+package simulation
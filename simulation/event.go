@@ -0,0 +1,138 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Names of CloudEvents context attributes with a special meaning during a
+// simulation.
+const (
+	attrSpecVersion     = "specversion"
+	attrID              = "id"
+	attrType            = "type"
+	attrSource          = "source"
+	attrDataContentType = "datacontenttype"
+)
+
+// Event is a CloudEvent.
+type Event struct {
+	// Context attributes, including extensions.
+	Context map[string]string
+	// Payload decoded from JSON, or nil if the event doesn't carry any data.
+	Data interface{}
+}
+
+// ParseEvent parses a CloudEvent in the JSON event format (structured mode).
+func ParseEvent(b []byte) (*Event, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("decoding CloudEvent: %w", err)
+	}
+
+	ev := &Event{
+		Context: make(map[string]string, len(raw)),
+	}
+
+	for k, v := range raw {
+		switch k {
+		case "data":
+			ev.Data = v
+		case "data_base64":
+			return nil, errors.New("binary event data (data_base64) is not supported")
+		default:
+			ev.Context[k] = stringify(v)
+		}
+	}
+
+	if _, ok := ev.Context[attrSpecVersion]; !ok {
+		ev.Context[attrSpecVersion] = "1.0"
+	}
+
+	for _, attr := range []string{attrID, attrType, attrSource} {
+		if ev.Context[attr] == "" {
+			return nil, fmt.Errorf("missing required CloudEvent attribute %q", attr)
+		}
+	}
+
+	return ev, nil
+}
+
+// MarshalJSON implements json.Marshaler.
+// The event is serialized in the JSON event format (structured mode).
+func (e *Event) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]interface{}, len(e.Context)+1)
+	for k, v := range e.Context {
+		obj[k] = v
+	}
+	if e.Data != nil {
+		obj["data"] = e.Data
+	}
+
+	return json.Marshal(obj)
+}
+
+// Copy returns a deep copy of the Event.
+func (e *Event) Copy() *Event {
+	cpy := &Event{
+		Context: make(map[string]string, len(e.Context)),
+		Data:    copyValue(e.Data),
+	}
+	for k, v := range e.Context {
+		cpy.Context[k] = v
+	}
+
+	return cpy
+}
+
+// copyValue returns a deep copy of a value decoded from JSON.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		cpy := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			cpy[k] = copyValue(e)
+		}
+		return cpy
+	case []interface{}:
+		cpy := make([]interface{}, len(v))
+		for i, e := range v {
+			cpy[i] = copyValue(e)
+		}
+		return cpy
+	default:
+		return v
+	}
+}
+
+// stringify returns the string representation of a value decoded from JSON.
+func stringify(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,629 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// evalCondition evaluates a filtering expression, as accepted by the
+// "condition" attribute of routers, against the data of the given event.
+//
+// Expressions reference values from the event's data using a "$" prefix
+// followed by a path, optionally asserting the type of the value. For example:
+//
+//   $user.name.(string) == "alice" && $user.age.(int64) >= 18
+//
+// Supported operators are ||, &&, !, ==, !=, <, <=, >, >=, =~, !~, +, -, *, /
+// and %.
+func evalCondition(expr string, ev *Event) (bool, error) {
+	toks, err := tokenize(expr)
+	if err != nil {
+		return false, err
+	}
+
+	p := &exprParser{toks: toks}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if !p.done() {
+		return false, fmt.Errorf("unexpected %q", p.peek().text)
+	}
+
+	v, err := node(ev.Data)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression evaluates to %s, expected a boolean", describe(v))
+	}
+
+	return b, nil
+}
+
+// exprNode evaluates a node of a parsed expression against some event data.
+type exprNode func(data interface{}) (interface{}, error)
+
+type tokenKind int
+
+const (
+	tokOperator tokenKind = iota
+	tokNumber
+	tokString
+	tokVariable
+	tokIdent
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// value of number and string literals
+	val interface{}
+	// type assertion of variables (e.g. "int64"), if any
+	typ string
+}
+
+// operators is the list of operators supported in expressions, ordered so that
+// longer operators are matched first.
+var operators = []string{
+	"||", "&&", "==", "!=", "<=", ">=", "=~", "!~",
+	"<", ">", "!", "+", "-", "*", "/", "%", "(", ")",
+}
+
+// tokenize splits an expression into tokens.
+func tokenize(expr string) ([]token, error) {
+	var toks []token
+
+	for i := 0; i < len(expr); {
+		c := expr[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(expr) && expr[end] != c {
+				if expr[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string literal at offset %d", i)
+			}
+
+			lit := expr[i : end+1]
+			if c == '\'' {
+				lit = `"` + strings.ReplaceAll(expr[i+1:end], `"`, `\"`) + `"`
+			}
+			s, err := strconv.Unquote(lit)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string literal %s", expr[i:end+1])
+			}
+
+			toks = append(toks, token{kind: tokString, text: expr[i : end+1], val: s})
+			i = end + 1
+
+		case c >= '0' && c <= '9':
+			end := i
+			for end < len(expr) && (expr[end] >= '0' && expr[end] <= '9' || expr[end] == '.') {
+				end++
+			}
+
+			n, err := strconv.ParseFloat(expr[i:end], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", expr[i:end])
+			}
+
+			toks = append(toks, token{kind: tokNumber, text: expr[i:end], val: n})
+			i = end
+
+		case c == '$':
+			end := i + 1
+			for end < len(expr) && isPathChar(rune(expr[end])) {
+				end++
+			}
+
+			tok := token{kind: tokVariable, text: expr[i:end]}
+
+			// type assertion, e.g. "$foo.(int64)"
+			if strings.HasSuffix(tok.text, ".") && end < len(expr) && expr[end] == '(' {
+				closing := strings.IndexByte(expr[end:], ')')
+				if closing == -1 {
+					return nil, fmt.Errorf("unterminated type assertion at offset %d", end)
+				}
+				tok.typ = expr[end+1 : end+closing]
+				tok.text = strings.TrimSuffix(tok.text, ".")
+				end += closing + 1
+			}
+
+			toks = append(toks, tok)
+			i = end
+
+		case unicode.IsLetter(rune(c)):
+			end := i
+			for end < len(expr) && (unicode.IsLetter(rune(expr[end])) || unicode.IsDigit(rune(expr[end]))) {
+				end++
+			}
+			toks = append(toks, token{kind: tokIdent, text: expr[i:end]})
+			i = end
+
+		default:
+			var op string
+			for _, o := range operators {
+				if strings.HasPrefix(expr[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+
+			toks = append(toks, token{kind: tokOperator, text: op})
+			i += len(op)
+		}
+	}
+
+	return toks, nil
+}
+
+// isPathChar returns whether the given character can be part of the path of a
+// variable.
+func isPathChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) ||
+		c == '_' || c == '-' || c == '.' || c == '[' || c == ']'
+}
+
+// exprParser is a recursive descent parser for filtering expressions.
+type exprParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *exprParser) done() bool {
+	return p.pos >= len(p.toks)
+}
+
+func (p *exprParser) peek() token {
+	if p.done() {
+		return token{}
+	}
+	return p.toks[p.pos]
+}
+
+// acceptOp consumes the next token if it is one of the given operators.
+func (p *exprParser) acceptOp(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	lhs, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.acceptOp("||"); !ok {
+			return lhs, nil
+		}
+		rhs, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		lhs = logicalNode(lhs, rhs, true)
+	}
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	lhs, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, ok := p.acceptOp("&&"); !ok {
+			return lhs, nil
+		}
+		rhs, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		lhs = logicalNode(lhs, rhs, false)
+	}
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	lhs, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	op, ok := p.acceptOp("==", "!=", "<=", ">=", "<", ">", "=~", "!~")
+	if !ok {
+		return lhs, nil
+	}
+
+	rhs, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	return binaryNode(op, lhs, rhs, compare), nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	lhs, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.acceptOp("+", "-")
+		if !ok {
+			return lhs, nil
+		}
+		rhs, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		lhs = binaryNode(op, lhs, rhs, arithmetic)
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	lhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.acceptOp("*", "/", "%")
+		if !ok {
+			return lhs, nil
+		}
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		lhs = binaryNode(op, lhs, rhs, arithmetic)
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	op, ok := p.acceptOp("!", "-")
+	if !ok {
+		return p.parsePrimary()
+	}
+
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(data interface{}) (interface{}, error) {
+		v, err := operand(data)
+		if err != nil {
+			return nil, err
+		}
+
+		switch op {
+		case "!":
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("operator ! can not be applied to %s", describe(v))
+			}
+			return !b, nil
+		default:
+			n, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("operator - can not be applied to %s", describe(v))
+			}
+			return -n, nil
+		}
+	}, nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	if p.done() {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	tok := p.toks[p.pos]
+	p.pos++
+
+	switch tok.kind {
+	case tokNumber, tokString:
+		return func(interface{}) (interface{}, error) { return tok.val, nil }, nil
+
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return func(interface{}) (interface{}, error) { return true, nil }, nil
+		case "false":
+			return func(interface{}) (interface{}, error) { return false, nil }, nil
+		}
+		return nil, fmt.Errorf("unknown identifier %q", tok.text)
+
+	case tokVariable:
+		return variableNode(tok)
+
+	default:
+		if tok.text != "(" {
+			return nil, fmt.Errorf("unexpected %q", tok.text)
+		}
+
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.acceptOp(")"); !ok {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return node, nil
+	}
+}
+
+// variableNode returns an exprNode which resolves a variable from event data.
+func variableNode(tok token) (exprNode, error) {
+	segs, err := parsePath(tok.text)
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok.typ {
+	case "", "string", "bool", "int64", "float64":
+	default:
+		return nil, fmt.Errorf("unsupported type assertion %q for %s", tok.typ, tok.text)
+	}
+
+	return func(data interface{}) (interface{}, error) {
+		v, ok := lookupPath(data, segs)
+		if !ok {
+			return nil, fmt.Errorf("%s does not exist in the event data", tok.text)
+		}
+
+		return assertType(v, tok.typ, tok.text)
+	}, nil
+}
+
+// assertType converts a value decoded from JSON to the asserted type.
+func assertType(v interface{}, typ, name string) (interface{}, error) {
+	switch typ {
+	case "":
+		return v, nil
+
+	case "string":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+
+	case "bool":
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+
+	case "int64", "float64":
+		var n float64
+		switch v := v.(type) {
+		case float64:
+			n = v
+		case string:
+			var err error
+			if n, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("value of %s is not a number", name)
+			}
+		default:
+			return nil, fmt.Errorf("value of %s is %s, expected %s", name, describe(v), typ)
+		}
+		if typ == "int64" {
+			n = math.Trunc(n)
+		}
+		return n, nil
+	}
+
+	return nil, fmt.Errorf("value of %s is %s, expected %s", name, describe(v), typ)
+}
+
+// logicalNode returns an exprNode which evaluates a short-circuiting logical
+// operation.
+func logicalNode(lhs, rhs exprNode, or bool) exprNode {
+	op := "&&"
+	if or {
+		op = "||"
+	}
+
+	operand := func(node exprNode, data interface{}) (bool, error) {
+		v, err := node(data)
+		if err != nil {
+			return false, err
+		}
+		b, ok := v.(bool)
+		if !ok {
+			return false, fmt.Errorf("operator %s can not be applied to %s", op, describe(v))
+		}
+		return b, nil
+	}
+
+	return func(data interface{}) (interface{}, error) {
+		l, err := operand(lhs, data)
+		if err != nil {
+			return nil, err
+		}
+		if l == or {
+			return l, nil
+		}
+		return operand(rhs, data)
+	}
+}
+
+// binaryNode returns an exprNode which evaluates a binary operation.
+func binaryNode(op string, lhs, rhs exprNode, eval func(op string, l, r interface{}) (interface{}, error)) exprNode {
+	return func(data interface{}) (interface{}, error) {
+		l, err := lhs(data)
+		if err != nil {
+			return nil, err
+		}
+		r, err := rhs(data)
+		if err != nil {
+			return nil, err
+		}
+		return eval(op, l, r)
+	}
+}
+
+// compare evaluates a comparison operation.
+func compare(op string, l, r interface{}) (interface{}, error) {
+	switch op {
+	case "==":
+		return reflect.DeepEqual(l, r), nil
+	case "!=":
+		return !reflect.DeepEqual(l, r), nil
+	case "=~", "!~":
+		s, ok := l.(string)
+		if !ok {
+			return nil, fmt.Errorf("operator %s can not be applied to %s", op, describe(l))
+		}
+		pattern, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("operator %s expects a regular expression, got %s", op, describe(r))
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+		return re.MatchString(s) == (op == "=~"), nil
+	}
+
+	var cmp int
+
+	switch l := l.(type) {
+	case float64:
+		r, ok := r.(float64)
+		if !ok {
+			return nil, fmt.Errorf("can not compare a number with %s", describe(r))
+		}
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	case string:
+		r, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("can not compare a string with %s", describe(r))
+		}
+		cmp = strings.Compare(l, r)
+	default:
+		return nil, fmt.Errorf("operator %s can not be applied to %s", op, describe(l))
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// arithmetic evaluates an arithmetic operation.
+func arithmetic(op string, l, r interface{}) (interface{}, error) {
+	if op == "+" {
+		if ls, ok := l.(string); ok {
+			if rs, ok := r.(string); ok {
+				return ls + rs, nil
+			}
+		}
+	}
+
+	ln, lok := l.(float64)
+	rn, rok := r.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("operator %s can not be applied to %s and %s", op, describe(l), describe(r))
+	}
+
+	switch op {
+	case "+":
+		return ln + rn, nil
+	case "-":
+		return ln - rn, nil
+	case "*":
+		return ln * rn, nil
+	case "/":
+		if rn == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return ln / rn, nil
+	default:
+		if rn == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(ln, rn), nil
+	}
+}
+
+// describe returns a short description of the type of a value, for use in
+// error messages.
+func describe(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
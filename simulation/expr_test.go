@@ -0,0 +1,97 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import "testing"
+
+func TestEvalCondition(t *testing.T) {
+	ev := &Event{
+		Data: map[string]interface{}{
+			"name":  "alice",
+			"age":   float64(42),
+			"admin": "true",
+			"tags":  []interface{}{"a", "b"},
+		},
+	}
+
+	testCases := map[string]struct {
+		expr      string
+		expect    bool
+		expectErr bool
+	}{
+		"string equality": {
+			expr:   `$name.(string) == "alice"`,
+			expect: true,
+		},
+		"numeric comparison": {
+			expr:   `$age.(int64) >= 18 && $age.(int64) < 40`,
+			expect: false,
+		},
+		"arithmetic": {
+			expr:   `($age.(float64) + 8) / 2 == 25`,
+			expect: true,
+		},
+		"negation and disjunction": {
+			expr:   `!($name.(string) == "bob") || $missing.(string) == "x"`,
+			expect: true,
+		},
+		"boolean conversion": {
+			expr:   `$admin.(bool)`,
+			expect: true,
+		},
+		"regular expression": {
+			expr:   `$name.(string) =~ '^al' && $tags[1].(string) !~ "a"`,
+			expect: true,
+		},
+		"missing value": {
+			expr:      `$missing.(string) == "x"`,
+			expectErr: true,
+		},
+		"wrong type assertion": {
+			expr:      `$name.(int64) > 1`,
+			expectErr: true,
+		},
+		"non-boolean result": {
+			expr:      `$age.(int64) + 1`,
+			expectErr: true,
+		},
+		"syntax error": {
+			expr:      `($age.(int64) > 1`,
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := evalCondition(tc.expr, ev)
+
+			if tc.expectErr {
+				if err == nil {
+					t.Error("Expected an error, got result", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal("Unexpected error:", err)
+			}
+			if got != tc.expect {
+				t.Errorf("Expected %t, got %t", tc.expect, got)
+			}
+		})
+	}
+}
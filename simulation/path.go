@@ -0,0 +1,170 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a segment of a path inside a JSON document. It is either the
+// key of an object member, or the index of an array element.
+type pathSegment struct {
+	key   string
+	index int
+	isIdx bool
+}
+
+// parsePath parses a path expressed in dot notation, such as "a.b[0].c".
+// A leading "$" or "." is accepted and ignored.
+func parsePath(path string) ([]pathSegment, error) {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+
+	if path == "" {
+		return nil, nil
+	}
+
+	var segs []pathSegment
+
+	for _, part := range strings.Split(path, ".") {
+		key := part
+		var idxs []string
+
+		if i := strings.IndexByte(part, '['); i != -1 {
+			key = part[:i]
+
+			for rest := part[i:]; rest != ""; {
+				end := strings.IndexByte(rest, ']')
+				if rest[0] != '[' || end == -1 {
+					return nil, fmt.Errorf("invalid path %q: malformed array index", path)
+				}
+				idxs = append(idxs, rest[1:end])
+				rest = rest[end+1:]
+			}
+		}
+
+		if key == "" && len(idxs) == 0 {
+			return nil, fmt.Errorf("invalid path %q: empty segment", path)
+		}
+
+		if key != "" {
+			segs = append(segs, pathSegment{key: key})
+		}
+
+		for _, idx := range idxs {
+			i, err := strconv.Atoi(idx)
+			if err != nil || i < 0 {
+				return nil, fmt.Errorf("invalid path %q: array index %q is not a positive integer", path, idx)
+			}
+			segs = append(segs, pathSegment{index: i, isIdx: true})
+		}
+	}
+
+	return segs, nil
+}
+
+// lookupPath returns the value located at the given path inside v.
+func lookupPath(v interface{}, segs []pathSegment) (interface{}, bool) {
+	for _, seg := range segs {
+		switch cur := v.(type) {
+		case map[string]interface{}:
+			if seg.isIdx {
+				return nil, false
+			}
+			var ok bool
+			if v, ok = cur[seg.key]; !ok {
+				return nil, false
+			}
+
+		case []interface{}:
+			if !seg.isIdx || seg.index >= len(cur) {
+				return nil, false
+			}
+			v = cur[seg.index]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// setPath sets the value located at the given path inside root, creating
+// intermediate objects and arrays as needed, and returns the updated root.
+func setPath(root interface{}, segs []pathSegment, val interface{}) interface{} {
+	if len(segs) == 0 {
+		return val
+	}
+
+	seg := segs[0]
+
+	if seg.isIdx {
+		arr, _ := root.([]interface{})
+		for len(arr) <= seg.index {
+			arr = append(arr, nil)
+		}
+		arr[seg.index] = setPath(arr[seg.index], segs[1:], val)
+		return arr
+	}
+
+	obj, ok := root.(map[string]interface{})
+	if !ok {
+		obj = make(map[string]interface{})
+	}
+	obj[seg.key] = setPath(obj[seg.key], segs[1:], val)
+
+	return obj
+}
+
+// deletePath deletes the value located at the given path inside root, and
+// returns the updated root.
+func deletePath(root interface{}, segs []pathSegment) interface{} {
+	if len(segs) == 0 {
+		return nil
+	}
+
+	seg := segs[0]
+	last := len(segs) == 1
+
+	switch cur := root.(type) {
+	case map[string]interface{}:
+		if seg.isIdx {
+			break
+		}
+		if last {
+			delete(cur, seg.key)
+			break
+		}
+		if v, ok := cur[seg.key]; ok {
+			cur[seg.key] = deletePath(v, segs[1:])
+		}
+
+	case []interface{}:
+		if !seg.isIdx || seg.index >= len(cur) {
+			break
+		}
+		if last {
+			return append(cur[:seg.index], cur[seg.index+1:]...)
+		}
+		cur[seg.index] = deletePath(cur[seg.index], segs[1:])
+	}
+
+	return root
+}
@@ -0,0 +1,503 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"til/config"
+	"til/config/addr"
+	"til/core"
+	"til/lang/k8s"
+)
+
+// maxHops is the maximum number of components an event can traverse during a
+// simulation. It prevents Bridges which contain cycles from being simulated
+// infinitely.
+const maxHops = 64
+
+// placeholderAPIVersion is the API version of the placeholder event addresses
+// assigned to components during a simulation.
+const placeholderAPIVersion = "simulation.til/v1"
+
+// Outcome is the outcome of the processing of an event by a component.
+type Outcome int
+
+// Possible outcomes.
+const (
+	// The event was forwarded to other components.
+	OutcomeForwarded Outcome = iota
+	// The event reached its final destination.
+	OutcomeDelivered
+	// The event was discarded, e.g. by a filter.
+	OutcomeDropped
+	// The processing of the event can not be simulated.
+	OutcomeOpaque
+	// The simulation was interrupted to prevent an infinite loop.
+	OutcomeTruncated
+)
+
+// String implements fmt.Stringer.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeForwarded:
+		return "forwarded"
+	case OutcomeDelivered:
+		return "delivered"
+	case OutcomeDropped:
+		return "dropped"
+	case OutcomeOpaque:
+		return "opaque"
+	case OutcomeTruncated:
+		return "truncated"
+	default:
+		return "simulation.Outcome(" + strconv.Itoa(int(o)) + ")"
+	}
+}
+
+// Step is a step of the journey of an event through a Bridge.
+type Step struct {
+	// Component which received the event.
+	Component addr.MessagingComponent
+	// Event received by the component, or emitted by the component if it
+	// is the origin of the simulation.
+	Event *Event
+	// Outcome of the processing of the event by the component.
+	Outcome Outcome
+	// Human-readable details about the processing of the event.
+	Notes []string
+	// Steps of the events sent by the component.
+	Next []*Step
+}
+
+// Simulator simulates the flow of events through the components of a Bridge.
+type Simulator struct {
+	// messaging components indexed by address (e.g. "router.my_router")
+	components map[string]core.MessagingComponentVertex
+
+	eval *core.Evaluator
+}
+
+// New returns a Simulator for the Bridge of the given Context.
+func New(cctx *core.Context) (*Simulator, hcl.Diagnostics) {
+	g, diags := cctx.Graph()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	s := &Simulator{
+		components: make(map[string]core.MessagingComponentVertex),
+		eval:       core.NewEvaluator(filepath.Dir(cctx.Bridge.Path), cctx.FS, cctx.Bridge.Delivery),
+	}
+
+	// Every referenceable component is assigned a placeholder address
+	// which identifies it, so that the decoded event destinations of
+	// other components can be mapped back to it.
+	for _, v := range g.Vertices() {
+		cmp, ok := v.(core.MessagingComponentVertex)
+		if !ok {
+			continue
+		}
+
+		cmpAddr := cmp.ComponentAddr()
+		key := componentKey(cmpAddr)
+
+		s.components[key] = cmp
+
+		if _, ok := v.(core.ReferenceableVertex); ok {
+			s.eval.InsertVariable(cmpAddr.Category.String(), cmpAddr.Identifier,
+				k8s.NewDestination(placeholderAPIVersion, cmpAddr.Category.String(), cmpAddr.Identifier))
+		}
+	}
+
+	return s, diags
+}
+
+// Run simulates the journey of the given event, starting from the component
+// with the given address (e.g. "source.my_source").
+func (s *Simulator) Run(from string, ev *Event) (*Step, hcl.Diagnostics) {
+	cmp, ok := s.components[from]
+	if !ok {
+		return nil, hcl.Diagnostics{unknownComponentDiagnostic(from)}
+	}
+
+	var diags hcl.Diagnostics
+
+	st := s.simulate(cmp, ev, 0, &diags)
+
+	return st, diags
+}
+
+// simulate simulates the processing of an event by the given component, and
+// recursively by all the components it sends events to.
+func (s *Simulator) simulate(cmp core.MessagingComponentVertex, ev *Event, hops int,
+	diags *hcl.Diagnostics) *Step {
+
+	cmpAddr := cmp.ComponentAddr()
+
+	st := &Step{
+		Component: cmpAddr,
+		Event:     ev,
+	}
+
+	if hops >= maxHops {
+		st.Outcome = OutcomeTruncated
+		st.note("maximum number of hops (%d) reached, the Bridge likely contains a cycle", maxHops)
+		return st
+	}
+
+	cfg := cty.NullVal(cty.DynamicPseudoType)
+	if dec, ok := cmp.(core.DecodableConfigVertex); ok {
+		var cfgDiags hcl.Diagnostics
+		cfg, _, cfgDiags = dec.DecodedConfig(s.eval)
+		*diags = diags.Extend(cfgDiags)
+		if cfgDiags.HasErrors() {
+			st.Outcome = OutcomeOpaque
+			st.note("the configuration of the component could not be decoded")
+			return st
+		}
+	}
+
+	dst := cty.NullVal(k8s.DestinationCty)
+	if sdr, ok := cmp.(core.EventSenderVertex); ok {
+		var dstDiags hcl.Diagnostics
+		dst, _, dstDiags = sdr.EventDestination(s.eval)
+		*diags = diags.Extend(dstDiags)
+	}
+
+	var out []outgoingEvent
+
+	switch cmpAddr.Category {
+	case config.CategorySources:
+		out = []outgoingEvent{{dst: dst, ev: ev}}
+
+	case config.CategoryChannels:
+		out = s.simulateChannel(cmpAddr.Type, cfg, ev)
+
+	case config.CategoryRouters:
+		out = s.simulateRouter(st, cfg)
+
+	case config.CategoryTransformers:
+		out = s.simulateTransformer(st, cfg, dst)
+
+	case config.CategoryTargets:
+		if dst.IsNull() {
+			st.Outcome = OutcomeDelivered
+			break
+		}
+		st.Outcome = OutcomeOpaque
+		st.note("the content of replies is not simulated, they would be sent to %s", s.destinationName(dst))
+	}
+
+	if st.Outcome != OutcomeForwarded {
+		return st
+	}
+
+	if len(out) == 0 {
+		st.Outcome = OutcomeDropped
+		return st
+	}
+
+	for _, o := range out {
+		next, ok := s.resolve(o.dst)
+		if !ok {
+			st.note("event sent to external destination %s", s.destinationName(o.dst))
+			continue
+		}
+		st.Next = append(st.Next, s.simulate(next, o.ev, hops+1, diags))
+	}
+
+	return st
+}
+
+// outgoingEvent is an event sent by a component to a destination.
+type outgoingEvent struct {
+	dst cty.Value
+	ev  *Event
+}
+
+// simulateChannel simulates the processing of an event by a channel.
+func (s *Simulator) simulateChannel(typ string, cfg cty.Value, ev *Event) []outgoingEvent {
+	switch typ {
+	case "point_to_point":
+		return []outgoingEvent{{dst: cfg.GetAttr("to"), ev: ev}}
+
+	case "pubsub":
+		var out []outgoingEvent
+		for it := cfg.ElementIterator(); it.Next(); {
+			_, dst := it.Element()
+			out = append(out, outgoingEvent{dst: dst, ev: ev.Copy()})
+		}
+		return s.sortByDestination(out)
+	}
+
+	return nil
+}
+
+// simulateRouter simulates the processing of an event by a router.
+func (s *Simulator) simulateRouter(st *Step, cfg cty.Value) []outgoingEvent {
+	ev := st.Event
+
+	switch typ := st.Component.Type; typ {
+	case "content_based":
+		var routes []cty.Value
+		for it := cfg.ElementIterator(); it.Next(); {
+			_, route := it.Element()
+			routes = append(routes, route)
+		}
+		sort.SliceStable(routes, func(i, j int) bool {
+			return s.destinationName(routes[i].GetAttr("to")) < s.destinationName(routes[j].GetAttr("to"))
+		})
+
+		var out []outgoingEvent
+
+		for _, route := range routes {
+			dst := route.GetAttr("to")
+			dstName := s.destinationName(dst)
+
+			if reason, ok := matchAttributes(route.GetAttr("attributes"), ev); !ok {
+				st.note("route to %s not taken: %s", dstName, reason)
+				continue
+			}
+
+			if cond := route.GetAttr("condition"); !cond.IsNull() {
+				if reason, ok := matchCondition(cond.AsString(), ev); !ok {
+					st.note("route to %s not taken: %s", dstName, reason)
+					continue
+				}
+			}
+
+			st.note("route to %s taken", dstName)
+			out = append(out, outgoingEvent{dst: dst, ev: ev.Copy()})
+		}
+
+		if len(out) == 0 {
+			st.note("no route matched the event")
+		}
+		return out
+
+	case "data_expression_filter":
+		if reason, ok := matchCondition(cfg.GetAttr("condition").AsString(), ev); !ok {
+			st.note("event filtered out: %s", reason)
+			return nil
+		}
+		return []outgoingEvent{{dst: cfg.GetAttr("to"), ev: ev}}
+
+	case "splitter":
+		events, err := split(cfg, ev)
+		if err != nil {
+			st.note("event could not be split: %s", err)
+			return nil
+		}
+
+		st.note("event split into %d event(s)", len(events))
+
+		out := make([]outgoingEvent, 0, len(events))
+		for _, splitEv := range events {
+			out = append(out, outgoingEvent{dst: cfg.GetAttr("to"), ev: splitEv})
+		}
+		return out
+
+	default:
+		st.Outcome = OutcomeOpaque
+		st.note("routers of type %q can not be simulated", typ)
+		return nil
+	}
+}
+
+// simulateTransformer simulates the processing of an event by a transformer.
+func (s *Simulator) simulateTransformer(st *Step, cfg, dst cty.Value) []outgoingEvent {
+	switch typ := st.Component.Type; typ {
+	case "bumblebee":
+		ev, err := applyBumblebee(cfg, st.Event)
+		if err != nil {
+			st.note("event could not be transformed: %s", err)
+			return nil
+		}
+		return []outgoingEvent{{dst: dst, ev: ev}}
+
+	default:
+		st.Outcome = OutcomeOpaque
+		if typ == "function" {
+			st.note("the code of functions is not evaluated")
+		} else {
+			st.note("transformers of type %q can not be simulated", typ)
+		}
+		if !dst.IsNull() {
+			st.note("transformed events would be sent to %s", s.destinationName(dst))
+		}
+		return nil
+	}
+}
+
+// matchAttributes returns whether the context attributes of the given event
+// match all the given attributes exactly. If not, the reason is returned.
+func matchAttributes(attrs cty.Value, ev *Event) (string, bool) {
+	if attrs.IsNull() {
+		return "", true
+	}
+
+	for it := attrs.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		name, expect := k.AsString(), v.AsString()
+
+		got, ok := ev.Context[name]
+		if !ok {
+			return fmt.Sprintf("attribute %q is not set, expected %q", name, expect), false
+		}
+		if got != expect {
+			return fmt.Sprintf("attribute %q is %q, expected %q", name, got, expect), false
+		}
+	}
+
+	return "", true
+}
+
+// matchCondition returns whether the data of the given event matches the
+// given filtering expression. If not, the reason is returned.
+func matchCondition(expr string, ev *Event) (string, bool) {
+	match, err := evalCondition(expr, ev)
+	if err != nil {
+		return fmt.Sprintf("condition %q could not be evaluated: %s", expr, err), false
+	}
+	if !match {
+		return fmt.Sprintf("condition %q is false", expr), false
+	}
+
+	return "", true
+}
+
+// split splits the data of an event into multiple events, according to the
+// configuration of a "splitter" router.
+func split(cfg cty.Value, ev *Event) ([]*Event, error) {
+	path := cfg.GetAttr("path").AsString()
+
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := lookupPath(ev.Data, segs)
+	if !ok {
+		return nil, fmt.Errorf("path %q does not exist in the event data", path)
+	}
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("path %q refers to %s, expected an array", path, describe(v))
+	}
+
+	ceCtx := cfg.GetAttr("ce_context")
+
+	events := make([]*Event, 0, len(items))
+	for i, item := range items {
+		splitEv := &Event{
+			Context: map[string]string{
+				attrSpecVersion:     "1.0",
+				attrID:              ev.Context[attrID] + "-" + strconv.Itoa(i),
+				attrType:            ceCtx.GetAttr("type").AsString(),
+				attrSource:          ceCtx.GetAttr("source").AsString(),
+				attrDataContentType: "application/json",
+			},
+			Data: copyValue(item),
+		}
+
+		if exts := ceCtx.GetAttr("extensions"); !exts.IsNull() {
+			for it := exts.ElementIterator(); it.Next(); {
+				k, v := it.Element()
+				splitEv.Context[k.AsString()] = v.AsString()
+			}
+		}
+
+		events = append(events, splitEv)
+	}
+
+	return events, nil
+}
+
+// resolve returns the component which has the given event destination.
+func (s *Simulator) resolve(dst cty.Value) (core.MessagingComponentVertex, bool) {
+	key, ok := placeholderKey(dst)
+	if !ok {
+		return nil, false
+	}
+
+	cmp, ok := s.components[key]
+	return cmp, ok
+}
+
+// destinationName returns a human-readable name for the given event
+// destination.
+func (s *Simulator) destinationName(dst cty.Value) string {
+	if key, ok := placeholderKey(dst); ok {
+		return key
+	}
+
+	if dst.IsNull() || !dst.IsKnown() {
+		return "<unknown>"
+	}
+
+	if uri := dst.GetAttr("uri"); !uri.IsNull() && uri.IsKnown() {
+		return uri.AsString()
+	}
+
+	ref := dst.GetAttr("ref")
+	if ref.IsNull() || !ref.IsWhollyKnown() {
+		return "<unknown>"
+	}
+
+	return ref.GetAttr("kind").AsString() + " " + strconv.Quote(ref.GetAttr("name").AsString())
+}
+
+// sortByDestination sorts outgoing events by name of destination, to make the
+// output of simulations deterministic.
+func (s *Simulator) sortByDestination(out []outgoingEvent) []outgoingEvent {
+	sort.SliceStable(out, func(i, j int) bool {
+		return s.destinationName(out[i].dst) < s.destinationName(out[j].dst)
+	})
+	return out
+}
+
+// placeholderKey returns the address of the component identified by the given
+// placeholder event destination.
+func placeholderKey(dst cty.Value) (string, bool) {
+	if dst.IsNull() || !dst.IsWhollyKnown() || !k8s.IsDestination(dst) {
+		return "", false
+	}
+
+	ref := dst.GetAttr("ref")
+	if ref.IsNull() || ref.GetAttr("apiVersion").AsString() != placeholderAPIVersion {
+		return "", false
+	}
+
+	return ref.GetAttr("kind").AsString() + "." + ref.GetAttr("name").AsString(), true
+}
+
+// componentKey returns the address of the given component.
+func componentKey(cmp addr.MessagingComponent) string {
+	return cmp.Category.String() + "." + cmp.Identifier
+}
+
+// note appends a formatted note to the Step.
+func (st *Step) note(format string, a ...interface{}) {
+	st.Notes = append(st.Notes, fmt.Sprintf(format, a...))
+}
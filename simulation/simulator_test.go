@@ -0,0 +1,244 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"til/config/file"
+	"til/core"
+	"til/fs"
+
+	. "til/simulation"
+)
+
+const testBridge = `
+source webhook "hook" {
+  event_type = "batch"
+
+  to = router.split
+}
+
+router splitter "split" {
+  path = "items"
+
+  ce_context {
+    type   = "item"
+    source = "splitter"
+  }
+
+  to = router.dispatch
+}
+
+router content_based "dispatch" {
+  route {
+    condition = "$qty.(int64) > 2"
+    to        = transformer.enrich
+  }
+
+  route {
+    attributes = {
+      type = "other"
+    }
+    to = target.display
+  }
+}
+
+transformer bumblebee "enrich" {
+  context {
+    operation "add" {
+      path {
+        key   = "type"
+        value = "item.large"
+      }
+    }
+  }
+
+  data {
+    operation "store" {
+      path {
+        key   = "$name"
+        value = "name"
+      }
+    }
+    operation "add" {
+      path {
+        key   = "label"
+        value = "large $name"
+      }
+    }
+    operation "delete" {
+      path {
+        key = "qty"
+      }
+    }
+  }
+
+  to = transformer.format
+}
+
+transformer function "format" {
+  runtime = "python"
+  code    = "def main(event, context): return event"
+
+  ce_context {
+    type = "item.formatted"
+  }
+
+  to = target.display
+}
+
+target event_display "display" {}
+`
+
+func TestSimulator(t *testing.T) {
+	const event = `{
+  "specversion": "1.0",
+  "id": "0",
+  "type": "batch",
+  "source": "test",
+  "data": {
+    "items": [
+      { "name": "a", "qty": 5 },
+      { "name": "b", "qty": 1 }
+    ]
+  }
+}`
+
+	const expectTree = `source.hook (webhook): forwarded
+  event: {"data":{"items":[{"name":"a","qty":5},{"name":"b","qty":1}]},"id":"0","source":"test","specversion":"1.0","type":"batch"}
+  -> router.split (splitter): forwarded
+       event: {"data":{"items":[{"name":"a","qty":5},{"name":"b","qty":1}]},"id":"0","source":"test","specversion":"1.0","type":"batch"}
+       event split into 2 event(s)
+       -> router.dispatch (content_based): forwarded
+            event: {"data":{"name":"a","qty":5},"datacontenttype":"application/json","id":"0-0","source":"splitter","specversion":"1.0","type":"item"}
+            route to target.display not taken: attribute "type" is "item", expected "other"
+            route to transformer.enrich taken
+            -> transformer.enrich (bumblebee): forwarded
+                 event: {"data":{"name":"a","qty":5},"datacontenttype":"application/json","id":"0-0","source":"splitter","specversion":"1.0","type":"item"}
+                 -> transformer.format (function): opaque
+                      event: {"data":{"label":"large a","name":"a"},"datacontenttype":"application/json","id":"0-0","source":"splitter","specversion":"1.0","type":"item.large"}
+                      the code of functions is not evaluated
+                      transformed events would be sent to target.display
+       -> router.dispatch (content_based): dropped
+            event: {"data":{"name":"b","qty":1},"datacontenttype":"application/json","id":"0-1","source":"splitter","specversion":"1.0","type":"item"}
+            route to target.display not taken: attribute "type" is "item", expected "other"
+            route to transformer.enrich not taken: condition "$qty.(int64) > 2" is false
+            no route matched the event
+`
+
+	sim := newSimulator(t)
+
+	ev, err := ParseEvent([]byte(event))
+	if err != nil {
+		t.Fatal("Failed to parse event:", err)
+	}
+
+	res, diags := sim.Run("source.hook", ev)
+	if diags.HasErrors() {
+		t.Fatal("Simulation returned error diagnostics:", diags)
+	}
+
+	var out bytes.Buffer
+	if err := WriteTree(&out, res); err != nil {
+		t.Fatal("Failed to write simulation tree:", err)
+	}
+
+	if diff := cmp.Diff(expectTree, out.String()); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+
+	t.Run("unknown origin", func(t *testing.T) {
+		_, diags := sim.Run("source.unknown", ev)
+		if !diags.HasErrors() {
+			t.Error("Expected error diagnostics")
+		}
+	})
+}
+
+func TestParseEvent(t *testing.T) {
+	testCases := map[string]struct {
+		event     string
+		expectErr bool
+	}{
+		"valid event": {
+			event: `{"specversion":"1.0","id":"0","type":"t","source":"s","data":"hello"}`,
+		},
+		"missing attribute": {
+			event:     `{"specversion":"1.0","id":"0","type":"t"}`,
+			expectErr: true,
+		},
+		"binary data": {
+			event:     `{"specversion":"1.0","id":"0","type":"t","source":"s","data_base64":"aGVsbG8="}`,
+			expectErr: true,
+		},
+		"invalid JSON": {
+			event:     `{`,
+			expectErr: true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			_, err := ParseEvent([]byte(tc.event))
+			if tc.expectErr && err == nil {
+				t.Error("Expected an error")
+			}
+			if !tc.expectErr && err != nil {
+				t.Error("Unexpected error:", err)
+			}
+		})
+	}
+}
+
+// newSimulator returns a Simulator for the test Bridge.
+func newSimulator(t *testing.T) *Simulator {
+	t.Helper()
+
+	const bridgeFile = "test.brg.hcl"
+
+	memFS := fs.NewMemFS()
+	if err := memFS.CreateFile(bridgeFile, []byte(testBridge)); err != nil {
+		t.Fatal("Failed to create Bridge file:", err)
+	}
+
+	p := &file.Parser{
+		Parser: hclparse.NewParser(),
+		FS:     memFS,
+	}
+
+	brg, diags := p.LoadBridge(bridgeFile)
+	if diags.HasErrors() {
+		t.Fatal("Failed to load Bridge:", diags)
+	}
+
+	cctx, diags := core.NewContext(brg)
+	if diags.HasErrors() {
+		t.Fatal("Failed to initialize context:", diags)
+	}
+
+	sim, diags := New(cctx)
+	if diags.HasErrors() {
+		t.Fatal("Failed to initialize simulator:", diags)
+	}
+
+	return sim
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulation
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// WriteTree writes a human-readable representation of the tree of Steps which
+// starts at the given Step.
+//
+// Example:
+//
+//   source.my_source (webhook): forwarded
+//     event: {"data":{"msg":"Hello"},"id":"1","source":"example","specversion":"1.0","type":"greeting"}
+//     -> router.my_router (content_based): forwarded
+//          event: {...}
+//          route to target.my_target taken
+//          -> target.my_target (event_display): delivered
+//               event: {...}
+func WriteTree(w io.Writer, st *Step) error {
+	bw := bufio.NewWriter(w)
+
+	if err := writeStep(bw, st, "", ""); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// writeStep writes a Step and its children with the given indentation.
+func writeStep(w *bufio.Writer, st *Step, indent, prefix string) error {
+	cmp := st.Component
+
+	_, _ = w.WriteString(indent + prefix + componentKey(cmp) + " (" + cmp.Type + "): " + st.Outcome.String() + "\n")
+
+	detailIndent := indent + strings.Repeat(" ", len(prefix)) + "  "
+
+	ev, err := json.Marshal(st.Event)
+	if err != nil {
+		return err
+	}
+	_, _ = w.WriteString(detailIndent + "event: " + string(ev) + "\n")
+
+	for _, n := range st.Notes {
+		_, _ = w.WriteString(detailIndent + n + "\n")
+	}
+
+	for _, next := range st.Next {
+		if err := writeStep(w, next, detailIndent, "-> "); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"til/cli"
+	"til/core"
+	"til/simulation"
+	"til/tiltest"
+)
+
+type TestCommand struct {
+	// flags
+	variableOptions
+	diagnosticsOptions
+}
+
+// Run implements cli.Command.
+func (c *TestCommand) Run(ctx context.Context, args []string) error {
+	flagSet := cli.FlagSetFromContext(ctx)
+	setUsageFn(flagSet, usageTest)
+
+	c.variableOptions.addFlags(flagSet)
+	c.diagnosticsOptions.addFlags(flagSet)
+
+	pos, flags := splitArgs(countPositional(len(args), args), args)
+	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
+
+	if len(pos) == 0 || flagSet.NArg() > 0 {
+		return fmt.Errorf("unexpected number of positional arguments.\n\n%s", usageTest(flagSet.Name()))
+	}
+	filePath, testFiles := pos[0], pos[1:]
+
+	if len(testFiles) == 0 {
+		var err error
+		if testFiles, err = filepath.Glob(filepath.Join(filepath.Dir(filePath), "*"+tiltest.FileExt)); err != nil {
+			return fmt.Errorf("listing test files: %w", err)
+		}
+		if len(testFiles) == 0 {
+			return fmt.Errorf("no test file (*%s) found next to %s", tiltest.FileExt, filePath)
+		}
+	}
+
+	ui := cli.UIFromContext(ctx)
+
+	p := c.variableOptions.newParser()
+
+	writeDiags := func(diags hcl.Diagnostics) {
+		dw := c.diagnosticWriter(ui, p.Files())
+		_ = dw.WriteDiagnostics(diags)
+	}
+
+	brg, diags := p.LoadBridge(filePath)
+	if diags.HasErrors() {
+		writeDiags(diags)
+		return errLoadBridge
+	}
+
+	cctx, diags := core.NewContext(brg)
+	if diags.HasErrors() {
+		writeDiags(diags)
+		return errInitContext
+	}
+
+	sim, diags := simulation.New(cctx)
+	if diags.HasErrors() {
+		writeDiags(diags)
+		return errors.New("failed to build bridge graph. See error diagnostics")
+	}
+
+	var tests []*tiltest.Test
+	for _, f := range testFiles {
+		t, loadDiags := tiltest.LoadFile(p, f)
+		diags = diags.Extend(loadDiags)
+		tests = append(tests, t...)
+	}
+	if diags.HasErrors() {
+		writeDiags(diags)
+		return errors.New("failed to load tests. See error diagnostics")
+	}
+
+	var failDiags hcl.Diagnostics
+	var failed int
+
+	for _, res := range tiltest.Run(sim, tests) {
+		status := "PASS"
+		if !res.Passed() {
+			status = "FAIL"
+			failed++
+			failDiags = failDiags.Extend(res.Diagnostics)
+		}
+		fmt.Fprintf(ui.StdWriter, "%s  %s\n", status, res.Test.Name)
+	}
+
+	fmt.Fprintf(ui.StdWriter, "\n%d passed, %d failed\n", len(tests)-failed, failed)
+
+	if failed > 0 {
+		writeDiags(failDiags)
+		return fmt.Errorf("%d test(s) failed. See error diagnostics", failed)
+	}
+
+	return nil
+}
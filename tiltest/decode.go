@@ -0,0 +1,345 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiltest
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"til/config"
+	"til/config/file"
+	"til/lang"
+	"til/simulation"
+)
+
+// FileExt is the extension of files containing tests.
+const FileExt = ".tiltest.hcl"
+
+// Names of blocks and attributes in test files.
+const (
+	blkTest   = "test"
+	blkEvent  = "event"
+	blkExpect = "expect"
+
+	attrFrom       = "from"
+	attrNotReached = "not_reached"
+	attrTarget     = "target"
+	attrAttributes = "attributes"
+	attrData       = "data"
+)
+
+// fileSchema is the schema of the body of test files.
+var fileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: blkTest, LabelNames: []string{"name"}},
+	},
+}
+
+// testSchema is the schema of the body of "test" blocks.
+var testSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: attrFrom, Required: true},
+		{Name: attrNotReached},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: blkEvent},
+		{Type: blkExpect},
+	},
+}
+
+// expectSchema is the schema of the body of "expect" blocks.
+var expectSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: attrTarget, Required: true},
+		{Name: attrAttributes},
+		{Name: attrData},
+	},
+}
+
+// Test is a routing test.
+type Test struct {
+	Name string
+
+	// Component which emits the event.
+	From *ComponentRef
+
+	// Event emitted by the tested component.
+	Event *simulation.Event
+
+	// Expectations about the targets which receive the event.
+	Expect []*Expectation
+	// Targets which must not receive the event.
+	NotReached []*ComponentRef
+
+	SourceRange hcl.Range
+}
+
+// Expectation is an assertion about an event received by a target.
+type Expectation struct {
+	Target *ComponentRef
+
+	// Context attributes the received event must have, if set.
+	Attributes      map[string]string
+	AttributesRange hcl.Range
+
+	// Data the received event must contain, if set. Objects match if they
+	// contain at least the expected members.
+	Data      interface{}
+	DataRange hcl.Range
+}
+
+// ComponentRef is a reference to a Bridge component.
+type ComponentRef struct {
+	// Address of the component (e.g. "target.my_target").
+	Addr        string
+	SourceRange hcl.Range
+}
+
+// LoadFile parses the test file at the given path and decodes the tests it
+// contains.
+func LoadFile(p *file.Parser, filePath string) ([]*Test, hcl.Diagnostics) {
+	hclFile, diags := p.ParseHCLFile(filePath)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	content, contentDiags := hclFile.Body.Content(fileSchema)
+	diags = diags.Extend(contentDiags)
+
+	evalCtx := &hcl.EvalContext{
+		Functions: lang.Functions(filepath.Dir(filePath), p.FS),
+	}
+
+	var tests []*Test
+
+	for _, blk := range content.Blocks {
+		t, decodeDiags := decodeTestBlock(blk, evalCtx)
+		diags = diags.Extend(decodeDiags)
+		if t != nil {
+			tests = append(tests, t)
+		}
+	}
+
+	return tests, diags
+}
+
+// decodeTestBlock decodes a "test" block.
+func decodeTestBlock(blk *hcl.Block, evalCtx *hcl.EvalContext) (*Test, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := blk.Body.Content(testSchema)
+	diags = diags.Extend(contentDiags)
+	if contentDiags.HasErrors() {
+		return nil, diags
+	}
+
+	t := &Test{
+		Name:        blk.Labels[0],
+		SourceRange: blk.DefRange,
+	}
+
+	from, fromDiags := decodeComponentRef(content.Attributes[attrFrom].Expr, config.CategoryUnknown)
+	diags = diags.Extend(fromDiags)
+	t.From = from
+
+	if attr, ok := content.Attributes[attrNotReached]; ok {
+		exprs, listDiags := hcl.ExprList(attr.Expr)
+		diags = diags.Extend(listDiags)
+
+		for _, expr := range exprs {
+			ref, refDiags := decodeComponentRef(expr, config.CategoryTargets)
+			diags = diags.Extend(refDiags)
+			if ref != nil {
+				t.NotReached = append(t.NotReached, ref)
+			}
+		}
+	}
+
+	for _, blk := range content.Blocks {
+		switch blk.Type {
+		case blkEvent:
+			if t.Event != nil {
+				diags = diags.Append(duplicateBlockDiagnostic(blk.Type, blk.DefRange))
+				continue
+			}
+
+			ev, evDiags := decodeEventBlock(blk, evalCtx)
+			diags = diags.Extend(evDiags)
+			t.Event = ev
+
+		case blkExpect:
+			exp, expDiags := decodeExpectBlock(blk, evalCtx)
+			diags = diags.Extend(expDiags)
+			if exp != nil {
+				t.Expect = append(t.Expect, exp)
+			}
+		}
+	}
+
+	if t.Event == nil && !diags.HasErrors() {
+		diags = diags.Append(missingEventDiagnostic(blk.DefRange))
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return t, diags
+}
+
+// decodeEventBlock decodes an "event" block into a CloudEvent.
+// All attributes except "data" are context attributes.
+func decodeEventBlock(blk *hcl.Block, evalCtx *hcl.EvalContext) (*simulation.Event, hcl.Diagnostics) {
+	attrs, diags := blk.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ce := map[string]interface{}{
+		"specversion": "1.0",
+		"id":          "til-test",
+	}
+
+	for name, attr := range attrs {
+		v, valDiags := decodeValue(attr, evalCtx)
+		diags = diags.Extend(valDiags)
+		if valDiags.HasErrors() {
+			continue
+		}
+		ce[name] = v
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	b, err := json.Marshal(ce)
+	if err == nil {
+		var ev *simulation.Event
+		if ev, err = simulation.ParseEvent(b); err == nil {
+			return ev, diags
+		}
+	}
+
+	return nil, diags.Append(invalidEventDiagnostic(err, blk.DefRange))
+}
+
+// decodeExpectBlock decodes an "expect" block.
+func decodeExpectBlock(blk *hcl.Block, evalCtx *hcl.EvalContext) (*Expectation, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := blk.Body.Content(expectSchema)
+	diags = diags.Extend(contentDiags)
+	if contentDiags.HasErrors() {
+		return nil, diags
+	}
+
+	exp := &Expectation{}
+
+	trg, trgDiags := decodeComponentRef(content.Attributes[attrTarget].Expr, config.CategoryTargets)
+	diags = diags.Extend(trgDiags)
+	exp.Target = trg
+
+	if attr, ok := content.Attributes[attrAttributes]; ok {
+		v, valDiags := decodeValue(attr, evalCtx)
+		diags = diags.Extend(valDiags)
+
+		if m, ok := v.(map[string]interface{}); ok {
+			exp.Attributes = make(map[string]string, len(m))
+			for k, v := range m {
+				s, ok := v.(string)
+				if !ok {
+					diags = diags.Append(wrongTypeDiagnostic("a map of strings", attr.Expr.Range()))
+					break
+				}
+				exp.Attributes[k] = s
+			}
+		} else if !valDiags.HasErrors() {
+			diags = diags.Append(wrongTypeDiagnostic("a map of strings", attr.Expr.Range()))
+		}
+
+		exp.AttributesRange = attr.Range
+	}
+
+	if attr, ok := content.Attributes[attrData]; ok {
+		v, valDiags := decodeValue(attr, evalCtx)
+		diags = diags.Extend(valDiags)
+
+		exp.Data = v
+		exp.DataRange = attr.Range
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return exp, diags
+}
+
+// decodeComponentRef decodes an expression which references a component (e.g.
+// "source.my_source"). If cat is not CategoryUnknown, the referenced component
+// must belong to that category.
+func decodeComponentRef(expr hcl.Expression, cat config.ComponentCategory) (*ComponentRef, hcl.Diagnostics) {
+	trav, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ts := trav.SimpleSplit()
+	refCat := config.AsComponentCategory(ts.RootName())
+
+	if len(ts.Rel) != 1 || refCat == config.CategoryUnknown {
+		return nil, diags.Append(badComponentRefDiagnostic(expr.Range()))
+	}
+	if cat != config.CategoryUnknown && refCat != cat {
+		return nil, diags.Append(wrongRefCategoryDiagnostic(cat, expr.Range()))
+	}
+
+	attr, ok := ts.Rel[0].(hcl.TraverseAttr)
+	if !ok {
+		return nil, diags.Append(badComponentRefDiagnostic(expr.Range()))
+	}
+
+	return &ComponentRef{
+		Addr:        refCat.String() + "." + attr.Name,
+		SourceRange: expr.Range(),
+	}, diags
+}
+
+// decodeValue evaluates the expression of an attribute and converts the
+// result to a value as it would be decoded from JSON.
+func decodeValue(attr *hcl.Attribute, evalCtx *hcl.EvalContext) (interface{}, hcl.Diagnostics) {
+	val, diags := attr.Expr.Value(evalCtx)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	b, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		return nil, diags.Append(wrongTypeDiagnostic("a JSON-compatible value", attr.Expr.Range()))
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, diags.Append(wrongTypeDiagnostic("a JSON-compatible value", attr.Expr.Range()))
+	}
+
+	return v, diags
+}
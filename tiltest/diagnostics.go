@@ -0,0 +1,153 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiltest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"til/config"
+	"til/simulation"
+)
+
+// duplicateBlockDiagnostic returns a hcl.Diagnostic which indicates that a
+// block which can only appear once in a test was declared multiple times.
+func duplicateBlockDiagnostic(blkType string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Duplicate block",
+		Detail:   fmt.Sprintf("A test can contain only one %q block.", blkType),
+		Subject:  subj.Ptr(),
+	}
+}
+
+// missingEventDiagnostic returns a hcl.Diagnostic which indicates that a test
+// doesn't describe any event.
+func missingEventDiagnostic(subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Missing event",
+		Detail:   fmt.Sprintf("A test must contain an %q block which describes the CloudEvent to send.", blkEvent),
+		Subject:  subj.Ptr(),
+	}
+}
+
+// invalidEventDiagnostic returns a hcl.Diagnostic which indicates that the
+// event described in a test is not a valid CloudEvent.
+func invalidEventDiagnostic(err error, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid event",
+		Detail:   fmt.Sprintf("The described event is not a valid CloudEvent: %s.", err),
+		Subject:  subj.Ptr(),
+	}
+}
+
+// wrongTypeDiagnostic returns a hcl.Diagnostic which indicates that the value
+// of an attribute is of an unexpected type.
+func wrongTypeDiagnostic(expectType string, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Wrong type",
+		Detail:   fmt.Sprintf("The value must be %s.", expectType),
+		Subject:  subj.Ptr(),
+	}
+}
+
+// badComponentRefDiagnostic returns a hcl.Diagnostic which indicates that an
+// expression is not a valid reference to a Bridge component.
+func badComponentRefDiagnostic(subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid reference",
+		Detail: "A reference to a Bridge component must have the format " +
+			"<category>.<identifier>, e.g. \"source.my_source\".",
+		Subject: subj.Ptr(),
+	}
+}
+
+// wrongRefCategoryDiagnostic returns a hcl.Diagnostic which indicates that an
+// expression references a component of an unexpected category.
+func wrongRefCategoryDiagnostic(expectCat config.ComponentCategory, subj hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid reference",
+		Detail:   fmt.Sprintf("The expression must reference a component of type %q.", expectCat),
+		Subject:  subj.Ptr(),
+	}
+}
+
+// targetNotReachedDiagnostic returns a hcl.Diagnostic which indicates that the
+// tested event did not reach an expected target.
+func targetNotReachedDiagnostic(trg *ComponentRef, stops []*simulation.Step) *hcl.Diagnostic {
+	detail := fmt.Sprintf("The event did not reach %s.", trg.Addr)
+	if len(stops) > 0 {
+		detail += " It stopped at:" + describeStops(stops)
+	}
+
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Target not reached",
+		Detail:   detail,
+		Subject:  trg.SourceRange.Ptr(),
+	}
+}
+
+// targetReachedDiagnostic returns a hcl.Diagnostic which indicates that the
+// tested event reached a target which it was not expected to reach.
+func targetReachedDiagnostic(trg *ComponentRef) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unexpected target reached",
+		Detail:   fmt.Sprintf("The event reached %s, which it was expected not to reach.", trg.Addr),
+		Subject:  trg.SourceRange.Ptr(),
+	}
+}
+
+// attributeMismatchDiagnostic returns a hcl.Diagnostic which indicates that
+// the event received by a target doesn't have the expected context attribute.
+func attributeMismatchDiagnostic(exp *Expectation, name, expect, got string, isSet bool) *hcl.Diagnostic {
+	detail := fmt.Sprintf("The attribute %q of the event received by %s is not set, expected %q.",
+		name, exp.Target.Addr, expect)
+	if isSet {
+		detail = fmt.Sprintf("The attribute %q of the event received by %s is %q, expected %q.",
+			name, exp.Target.Addr, got, expect)
+	}
+
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unexpected event attribute",
+		Detail:   detail,
+		Subject:  exp.AttributesRange.Ptr(),
+	}
+}
+
+// dataMismatchDiagnostic returns a hcl.Diagnostic which indicates that the
+// event received by a target doesn't contain the expected data.
+func dataMismatchDiagnostic(exp *Expectation, got interface{}) *hcl.Diagnostic {
+	gotJSON, _ := json.Marshal(got)
+
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unexpected event data",
+		Detail: fmt.Sprintf("The data of the event received by %s doesn't match the expected data. "+
+			"The received data was: %s", exp.Target.Addr, gotJSON),
+		Subject: exp.DataRange.Ptr(),
+	}
+}
@@ -0,0 +1,24 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tiltest runs declarative routing tests against Bridge descriptions.
+//
+// Tests are written in HCL files with the extension ".tiltest.hcl", which
+// contain "test" blocks. Each test describes a CloudEvent, the component which
+// emits it, and assertions about the targets which are expected to receive it.
+// Tests run offline, by simulating the flow of the event through the Bridge
+// (see package simulation).
+package tiltest
@@ -0,0 +1,201 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiltest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"til/config"
+	"til/simulation"
+)
+
+// Result is the result of a Test.
+type Result struct {
+	Test *Test
+	// Failed assertions, and errors which prevented the Test from running.
+	Diagnostics hcl.Diagnostics
+}
+
+// Passed returns whether the Test passed.
+func (r *Result) Passed() bool {
+	return !r.Diagnostics.HasErrors()
+}
+
+// Run runs the given Tests using a Simulator.
+func Run(sim *simulation.Simulator, tests []*Test) []*Result {
+	results := make([]*Result, 0, len(tests))
+
+	for _, t := range tests {
+		results = append(results, &Result{
+			Test:        t,
+			Diagnostics: run(sim, t),
+		})
+	}
+
+	return results
+}
+
+// run runs a single Test.
+func run(sim *simulation.Simulator, t *Test) hcl.Diagnostics {
+	res, diags := sim.Run(t.From.Addr, t.Event.Copy())
+	if diags.HasErrors() {
+		for _, d := range diags {
+			if d.Subject == nil {
+				d.Subject = t.From.SourceRange.Ptr()
+			}
+		}
+		return diags
+	}
+
+	received := make(map[string][]*simulation.Event)
+	var stops []*simulation.Step
+	collect(res, received, &stops)
+
+	for _, exp := range t.Expect {
+		events, reached := received[exp.Target.Addr]
+		if !reached {
+			diags = diags.Append(targetNotReachedDiagnostic(exp.Target, stops))
+			continue
+		}
+
+		diags = diags.Extend(checkEvents(exp, events))
+	}
+
+	for _, trg := range t.NotReached {
+		if _, reached := received[trg.Addr]; reached {
+			diags = diags.Append(targetReachedDiagnostic(trg))
+		}
+	}
+
+	return diags
+}
+
+// collect walks the tree of simulation Steps which starts at the given Step,
+// and records the events received by targets as well as the Steps at which
+// events stopped without reaching any target.
+func collect(st *simulation.Step, received map[string][]*simulation.Event, stops *[]*simulation.Step) {
+	if st.Component.Category == config.CategoryTargets {
+		addr := st.Component.Category.String() + "." + st.Component.Identifier
+		received[addr] = append(received[addr], st.Event)
+		return
+	}
+
+	if len(st.Next) == 0 {
+		*stops = append(*stops, st)
+	}
+
+	for _, next := range st.Next {
+		collect(next, received, stops)
+	}
+}
+
+// checkEvents verifies that at least one of the events received by a target
+// satisfies the given Expectation.
+func checkEvents(exp *Expectation, events []*simulation.Event) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for _, ev := range events {
+		var evDiags hcl.Diagnostics
+
+		for _, name := range sortedKeys(exp.Attributes) {
+			expect := exp.Attributes[name]
+			if got, ok := ev.Context[name]; !ok || got != expect {
+				evDiags = evDiags.Append(attributeMismatchDiagnostic(exp, name, expect, got, ok))
+			}
+		}
+
+		if exp.Data != nil && !matchData(exp.Data, ev.Data) {
+			evDiags = evDiags.Append(dataMismatchDiagnostic(exp, ev.Data))
+		}
+
+		if !evDiags.HasErrors() {
+			return nil
+		}
+
+		// only report the mismatches of the first received event
+		if diags == nil {
+			diags = evDiags
+		}
+	}
+
+	return diags
+}
+
+// matchData returns whether the given data matches the expected data.
+// Objects match if they contain at least the expected members.
+func matchData(expect, got interface{}) bool {
+	switch expect := expect.(type) {
+	case map[string]interface{}:
+		gotObj, ok := got.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, v := range expect {
+			gotV, ok := gotObj[k]
+			if !ok || !matchData(v, gotV) {
+				return false
+			}
+		}
+		return true
+
+	case []interface{}:
+		gotArr, ok := got.([]interface{})
+		if !ok || len(gotArr) != len(expect) {
+			return false
+		}
+		for i := range expect {
+			if !matchData(expect[i], gotArr[i]) {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return reflect.DeepEqual(expect, got)
+	}
+}
+
+// describeStops returns a human-readable description of the Steps at which
+// events stopped.
+func describeStops(stops []*simulation.Step) string {
+	var sb strings.Builder
+
+	for _, st := range stops {
+		fmt.Fprintf(&sb, "\n  - %s.%s (%s)", st.Component.Category, st.Component.Identifier, st.Outcome)
+		if n := len(st.Notes); n > 0 {
+			sb.WriteString(": " + st.Notes[n-1])
+		}
+	}
+
+	return sb.String()
+}
+
+// sortedKeys returns the keys of the given map in lexical order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
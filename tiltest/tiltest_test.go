@@ -0,0 +1,255 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiltest_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"til/config/file"
+	"til/core"
+	"til/fs"
+	"til/simulation"
+
+	. "til/tiltest"
+)
+
+const testBridge = `
+source webhook "hook" {
+  event_type = "greeting"
+
+  to = router.dispatch
+}
+
+router content_based "dispatch" {
+  route {
+    attributes = {
+      type = "greeting"
+    }
+    to = target.greetings
+  }
+
+  route {
+    condition = "$urgent.(bool)"
+    to        = target.alerts
+  }
+}
+
+target event_display "greetings" {}
+target event_display "alerts" {}
+`
+
+const testTests = `
+test "greeting" {
+  from = source.hook
+
+  event {
+    type   = "greeting"
+    source = "test"
+    data   = { msg = "hello", urgent = false }
+  }
+
+  expect {
+    target     = target.greetings
+    attributes = { type = "greeting" }
+    data       = { msg = "hello" }
+  }
+
+  not_reached = [target.alerts]
+}
+
+test "urgent_greeting" {
+  from = source.hook
+
+  event {
+    type   = "greeting"
+    source = "test"
+    data   = { msg = "hello", urgent = true }
+  }
+
+  expect {
+    target = target.alerts
+    data   = { msg = "bye" }
+  }
+
+  not_reached = [target.greetings]
+}
+
+test "other" {
+  from = source.hook
+
+  event {
+    type   = "other"
+    source = "test"
+    data   = { urgent = false }
+  }
+
+  expect {
+    target = target.greetings
+  }
+}
+`
+
+func TestRun(t *testing.T) {
+	const bridgeFile = "test.brg.hcl"
+	const testFile = "test" + FileExt
+
+	memFS := fs.NewMemFS()
+	if err := memFS.CreateFile(bridgeFile, []byte(testBridge)); err != nil {
+		t.Fatal("Failed to create Bridge file:", err)
+	}
+	if err := memFS.CreateFile(testFile, []byte(testTests)); err != nil {
+		t.Fatal("Failed to create test file:", err)
+	}
+
+	p := &file.Parser{
+		Parser: hclparse.NewParser(),
+		FS:     memFS,
+	}
+
+	brg, diags := p.LoadBridge(bridgeFile)
+	if diags.HasErrors() {
+		t.Fatal("Failed to load Bridge:", diags)
+	}
+	cctx, diags := core.NewContext(brg)
+	if diags.HasErrors() {
+		t.Fatal("Failed to initialize context:", diags)
+	}
+	sim, diags := simulation.New(cctx)
+	if diags.HasErrors() {
+		t.Fatal("Failed to initialize simulator:", diags)
+	}
+
+	tests, diags := LoadFile(p, testFile)
+	if diags.HasErrors() {
+		t.Fatal("Failed to load tests:", diags)
+	}
+
+	type result struct {
+		Name     string
+		Passed   bool
+		Failures []string
+		Lines    []int
+	}
+
+	expectResults := []result{
+		{Name: "greeting", Passed: true},
+		{
+			Name:     "urgent_greeting",
+			Failures: []string{"Unexpected event data", "Unexpected target reached"},
+			Lines:    []int{31, 34},
+		},
+		{
+			Name:     "other",
+			Failures: []string{"Target not reached"},
+			Lines:    []int{47},
+		},
+	}
+
+	var results []result
+	for _, res := range Run(sim, tests) {
+		r := result{
+			Name:   res.Test.Name,
+			Passed: res.Passed(),
+		}
+		for _, d := range res.Diagnostics {
+			r.Failures = append(r.Failures, d.Summary)
+			r.Lines = append(r.Lines, d.Subject.Start.Line)
+		}
+		results = append(results, r)
+	}
+
+	if diff := cmp.Diff(expectResults, results); diff != "" {
+		t.Error("Unexpected diff: (-:expect, +:got)", diff)
+	}
+}
+
+func TestLoadFileInvalid(t *testing.T) {
+	testCases := map[string]struct {
+		tests         string
+		expectSummary string
+	}{
+		"missing event": {
+			tests: `
+test "t" {
+  from = source.hook
+}`,
+			expectSummary: "Missing event",
+		},
+		"invalid event": {
+			tests: `
+test "t" {
+  from = source.hook
+  event {
+    type = "greeting"
+  }
+}`,
+			expectSummary: "Invalid event",
+		},
+		"expected target of wrong category": {
+			tests: `
+test "t" {
+  from = source.hook
+  event {
+    type   = "greeting"
+    source = "test"
+  }
+  expect {
+    target = router.dispatch
+  }
+}`,
+			expectSummary: "Invalid reference",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			const testFile = "test" + FileExt
+
+			memFS := fs.NewMemFS()
+			if err := memFS.CreateFile(testFile, []byte(tc.tests)); err != nil {
+				t.Fatal("Failed to create test file:", err)
+			}
+
+			p := &file.Parser{
+				Parser: hclparse.NewParser(),
+				FS:     memFS,
+			}
+
+			_, diags := LoadFile(p, testFile)
+			if !diags.HasErrors() {
+				t.Fatal("Expected error diagnostics")
+			}
+			if got := firstError(diags).Summary; got != tc.expectSummary {
+				t.Errorf("Expected diagnostic %q, got %q", tc.expectSummary, got)
+			}
+		})
+	}
+}
+
+// firstError returns the first error diagnostic in diags.
+func firstError(diags hcl.Diagnostics) *hcl.Diagnostic {
+	for _, d := range diags {
+		if d.Severity == hcl.DiagError {
+			return d
+		}
+	}
+	return nil
+}
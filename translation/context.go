@@ -0,0 +1,58 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package translation
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"til/config/globals"
+)
+
+// LabelBridgeIdentifier is the label which associates Kubernetes objects with
+// the Bridge they were generated for.
+const LabelBridgeIdentifier = "bridges.triggermesh.io/id"
+
+// Context contains information about a component being translated and about
+// the Bridge it belongs to.
+type Context struct {
+	// Identifier of the component. Must be used wherever deterministic
+	// object names need to be generated, because the state of previously
+	// generated manifests is not persisted.
+	Identifier string
+	// Decoded configuration of the component.
+	Config cty.Value
+	// Destination of events sent by the component. Null if the component
+	// doesn't send events.
+	EventDestination cty.Value
+	// Global settings of the Bridge.
+	Globals globals.Accessor
+
+	// Identifier of the Bridge. May be empty.
+	BridgeIdentifier string
+	// Kubernetes namespace the Bridge is deployed to. Empty if the
+	// namespace is only determined at deployment time, in which case
+	// generated manifests must not set a namespace either.
+	Namespace string
+	// Labels which are set on all the objects generated for the Bridge
+	// once they are translated. Translators don't need to set them, but
+	// may use them, e.g. in label selectors.
+	Labels map[string]string
+
+	// Source range of the block that declares the component.
+	SourceRange hcl.Range
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package translation
 
 import (
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"til/config/globals"
 )
@@ -47,20 +49,47 @@ type Translatable interface {
 	Manifests(id string, config, eventDst cty.Value, settings globals.Accessor) []interface{}
 }
 
+// TranslatableV2 is implemented by component types that can be translated into
+// Kubernetes manifests, with access to the context of the translation.
+//
+// Unlike Translatable, it allows component types to report semantic problems
+// with their configuration as diagnostics. It supersedes Translatable, which
+// remains supported while in-tree component types are being migrated. When a
+// component type implements both interfaces, TranslatableV2 takes precedence.
+type TranslatableV2 interface {
+	// Kubernetes manifests satisfying the configuration found in the
+	// given translation context.
+	//
+	// Diagnostics should have their subject set to a range within the
+	// component's block (Context.SourceRange). Returned manifests are
+	// discarded if the diagnostics contain errors.
+	ManifestsV2(*Context) ([]interface{}, hcl.Diagnostics)
+}
+
 // Addressable is implemented by component types that can receive events from
 // other components.
 //
 // Currently, only KReference destinations are supported, meaning that all
-// types that implement Addressable are also Translatable, since they must be
-// represented by at least one Kubernetes object. This may change in the future.
+// types that implement Addressable are also Translatable or TranslatableV2,
+// since they must be represented by at least one Kubernetes object. This may
+// change in the future.
 //
 // The provided id must be used to generate a deterministic value for the name
 // field of this KReference, and that name must match the one of the
-// corresponding object generated by the Translatable interface.
+// corresponding object generated by the Translatable or TranslatableV2
+// interface.
 type Addressable interface {
-	Translatable
-
 	// Address of the component expressed as a Knative "duck" destination
 	// in the cty type system.
 	Address(id string, config, eventDst cty.Value) cty.Value
 }
+
+// Introspectable is implemented by component types that can describe the
+// Kubernetes objects they translate to, independently of any configuration.
+type Introspectable interface {
+	// Kinds of all Kubernetes objects that may be generated by the
+	// Translatable or TranslatableV2 interface, including the ones that are
+	// only generated under certain conditions (e.g. when replies are
+	// routed).
+	Kinds() []schema.GroupVersionKind
+}
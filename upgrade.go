@@ -0,0 +1,122 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"til/cli"
+	"til/upgrade"
+)
+
+type UpgradeCommand struct {
+	// flags
+	dryRun bool
+	diagnosticsOptions
+}
+
+// Run implements cli.Command.
+func (c *UpgradeCommand) Run(ctx context.Context, args []string) error {
+	flagSet := cli.FlagSetFromContext(ctx)
+	setUsageFn(flagSet, usageUpgrade)
+
+	flagSet.BoolVar(&c.dryRun, "dry-run", false, "")
+	c.diagnosticsOptions.addFlags(flagSet)
+
+	pos, flags := splitArgs(1, args)
+	_ = flagSet.Parse(flags) // ignore err; the FlagSet uses ExitOnError
+
+	if len(pos) != 1 {
+		return fmt.Errorf("unexpected number of positional arguments.\n\n%s", usageUpgrade(flagSet.Name()))
+	}
+	filePath := pos[0]
+
+	ui := cli.UIFromContext(ctx)
+
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("reading Bridge description: %w", err)
+	}
+
+	src, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("reading Bridge description: %w", err)
+	}
+
+	out, changes, diags := upgrade.Rewrite(src, filePath, upgrade.Builtin)
+
+	files := map[string]*hcl.File{filePath: {Bytes: src}}
+	dw := c.diagnosticWriter(ui, files)
+	if len(diags) > 0 {
+		_ = dw.WriteDiagnostics(diags)
+	}
+	if diags.HasErrors() {
+		return errors.New("failed to parse the Bridge description. See error diagnostics")
+	}
+
+	writeChanges(ui.ErrWriter, changes)
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if c.dryRun {
+		_, err := ui.StdWriter.Write(upgrade.UnifiedDiff(filePath, src, out))
+		return err
+	}
+
+	if err := ioutil.WriteFile(filePath, out, fi.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing upgraded Bridge description: %w", err)
+	}
+
+	return nil
+}
+
+// writeChanges writes a human-readable list of the given changes to w,
+// followed by the actions which remain to be taken manually.
+func writeChanges(w io.Writer, changes []*upgrade.Change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No deprecated construct found.")
+		return
+	}
+
+	for _, ch := range changes {
+		fmt.Fprintf(w, "%s: %s\n", ch.Block, ch.Description)
+	}
+
+	var actions []*upgrade.Change
+	for _, ch := range changes {
+		if ch.Action != "" {
+			actions = append(actions, ch)
+		}
+	}
+	if len(actions) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nRequired actions:")
+	for _, ch := range actions {
+		fmt.Fprintf(w, "  - %s: %s\n", ch.Block, ch.Action)
+	}
+}
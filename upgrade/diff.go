@@ -0,0 +1,190 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Number of unchanged lines surrounding each hunk of a unified diff.
+const diffContextLines = 3
+
+// diffOp is a line-level edit operation.
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// UnifiedDiff returns the differences between the original content a and the
+// modified content b of the given file, in the unified diff format. It returns
+// nil if both contents are identical.
+func UnifiedDiff(filename string, a, b []byte) []byte {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var out bytes.Buffer
+
+	// positions of the current operation in a and b, 0-based
+	var posA, posB int
+	// start positions of each operation, used to compute hunk headers
+	startsA := make([]int, len(ops)+1)
+	startsB := make([]int, len(ops)+1)
+	for i, op := range ops {
+		startsA[i], startsB[i] = posA, posB
+		if op.kind != '+' {
+			posA++
+		}
+		if op.kind != '-' {
+			posB++
+		}
+	}
+	startsA[len(ops)], startsB[len(ops)] = posA, posB
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// extend the hunk until the next change is too far away to share
+		// context lines
+		first := max(0, i-diffContextLines)
+		last := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind == ' ' {
+				continue
+			}
+			if j-last-1 > 2*diffContextLines {
+				break
+			}
+			last = j
+		}
+		end := min(len(ops), last+diffContextLines+1)
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", filename, filename)
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n",
+			hunkRange(startsA[first], startsA[end]-startsA[first]),
+			hunkRange(startsB[first], startsB[end]-startsB[first]),
+		)
+
+		for _, op := range ops[first:end] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+
+	if out.Len() == 0 {
+		return nil
+	}
+	return out.Bytes()
+}
+
+// hunkRange formats the range of lines of a hunk which starts at the given
+// 0-based position.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		// an empty range refers to the line preceding the hunk
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// diffLines computes the shortest edit script which transforms the lines a
+// into the lines b, based on their longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, max(len(a), len(b)))
+
+	var i, j int
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{kind: '-', line: a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{kind: '-', line: a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{kind: '+', line: b[j]})
+	}
+
+	return ops
+}
+
+// splitLines splits the given content into lines, each of them retaining its
+// trailing line feed.
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+
+	lines := strings.SplitAfter(string(b), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	. "til/upgrade"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	testCases := map[string]struct {
+		a, b   string
+		expect string
+	}{
+		"identical contents": {
+			a:      "a\nb\n",
+			b:      "a\nb\n",
+			expect: "",
+		},
+		"single change with context": {
+			a: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b: "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			expect: "--- f\n+++ f\n" +
+				"@@ -2,7 +2,7 @@\n" +
+				" 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		"distant changes in separate hunks": {
+			a: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b: "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			expect: "--- f\n+++ f\n" +
+				"@@ -1,4 +1,4 @@\n" +
+				"-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n" +
+				" 7\n 8\n 9\n-10\n+ten\n",
+		},
+		"insertion at beginning": {
+			a: "a\n",
+			b: "new\na\n",
+			expect: "--- f\n+++ f\n" +
+				"@@ -1 +1,2 @@\n" +
+				"+new\n a\n",
+		},
+		"missing trailing newline": {
+			a: "a",
+			b: "b",
+			expect: "--- f\n+++ f\n" +
+				"@@ -1 +1 @@\n" +
+				"-a\n\\ No newline at end of file\n" +
+				"+b\n\\ No newline at end of file\n",
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			diff := UnifiedDiff("f", []byte(tc.a), []byte(tc.b))
+
+			if d := cmp.Diff(tc.expect, string(diff)); d != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgrade rewrites deprecated constructs of Bridge descriptions.
+//
+// Each Migration targets a single deprecated construct, such as a component
+// type or attribute which was renamed, or a plaintext credential which must be
+// replaced with a reference to a Kubernetes Secret. Migrations operate on the
+// hclwrite representation of a file, so that the formatting and comments of
+// unaffected parts of the file are preserved.
+//
+// Some migrations can not be completed solely by rewriting the Bridge
+// description. Those report the action which remains to be taken manually.
+package upgrade
@@ -0,0 +1,246 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"til/config"
+	"til/internal/sdk/k8s"
+)
+
+// Migration rewrites a deprecated construct of a Bridge description.
+type Migration interface {
+	// Migrate rewrites the given top-level block in place if it contains
+	// the deprecated construct, and returns the changes it made. The
+	// second argument is the same block as parsed from the original
+	// source, which carries the source locations of its contents.
+	Migrate(*hclwrite.Block, *hclsyntax.Block) []*Change
+}
+
+// Change describes a modification made to a Bridge description.
+type Change struct {
+	// Address of the modified block (e.g. "source.my_source").
+	Block string
+	// Description of the modification.
+	Description string
+	// Action which remains to be taken manually to complete the migration,
+	// if any.
+	Action string
+}
+
+// Builtin contains the migrations of all deprecated constructs known to the
+// interpreter, in the order they should be applied.
+var Builtin = []Migration{
+	&SecretRefAttribute{
+		Category:     config.CategorySources,
+		Type:         "webhook",
+		Attribute:    "basic_auth_password",
+		SecretSuffix: "basic-auth",
+		Key:          "password",
+	},
+	&SecretRefAttribute{
+		Category:     config.CategorySources,
+		Type:         "zendesk",
+		Attribute:    "webhook_password",
+		SecretSuffix: "webhook",
+		Key:          "password",
+	},
+}
+
+// RenamedType is a Migration which renames a component type.
+type RenamedType struct {
+	Category config.ComponentCategory
+	From     string
+	To       string
+}
+
+var _ Migration = (*RenamedType)(nil)
+
+// Migrate implements Migration.
+func (m *RenamedType) Migrate(blk *hclwrite.Block, _ *hclsyntax.Block) []*Change {
+	id, ok := componentIdentifier(blk, m.Category, m.From)
+	if !ok {
+		return nil
+	}
+
+	// Labels are rewritten in place instead of via SetLabels, which would
+	// also change the form (quoted/unquoted) of all labels.
+	toks := blk.BuildTokens(nil)
+	for i := range toks {
+		if toks[i].Type != hclsyntax.TokenIdent {
+			continue
+		}
+
+		// toks[i] is the block type, the type label follows
+		lbl := toks[i+1]
+		if lbl.Type == hclsyntax.TokenOQuote {
+			lbl = toks[i+2]
+		}
+		lbl.Bytes = []byte(m.To)
+		break
+	}
+
+	return []*Change{{
+		Block:       m.Category.String() + "." + id,
+		Description: fmt.Sprintf("Renamed component type %q to %q.", m.From, m.To),
+	}}
+}
+
+// RenamedAttribute is a Migration which renames an attribute of a component
+// type.
+type RenamedAttribute struct {
+	Category config.ComponentCategory
+	Type     string
+	From     string
+	To       string
+}
+
+var _ Migration = (*RenamedAttribute)(nil)
+
+// Migrate implements Migration.
+func (m *RenamedAttribute) Migrate(blk *hclwrite.Block, _ *hclsyntax.Block) []*Change {
+	id, ok := componentIdentifier(blk, m.Category, m.Type)
+	if !ok {
+		return nil
+	}
+
+	attr := blk.Body().GetAttribute(m.From)
+	if attr == nil {
+		return nil
+	}
+
+	addr := m.Category.String() + "." + id
+
+	if blk.Body().GetAttribute(m.To) != nil {
+		return []*Change{{
+			Block:       addr,
+			Description: fmt.Sprintf("Left the deprecated attribute %q untouched.", m.From),
+			Action: fmt.Sprintf("Remove the deprecated attribute %q, which is superseded by %q.",
+				m.From, m.To),
+		}}
+	}
+
+	// The tokens of an attribute are shared with the syntax tree, so the
+	// name can be rewritten in place while preserving comments.
+	for _, tok := range attr.BuildTokens(nil) {
+		if tok.Type == hclsyntax.TokenIdent {
+			tok.Bytes = []byte(m.To)
+			break
+		}
+	}
+
+	return []*Change{{
+		Block:       addr,
+		Description: fmt.Sprintf("Renamed attribute %q to %q.", m.From, m.To),
+	}}
+}
+
+// SecretRefAttribute is a Migration which replaces the plaintext value of a
+// component's attribute with a reference to a key of a Kubernetes Secret.
+//
+// The name of the Secret is derived from the component's identifier and the
+// given suffix. The Secret itself must be created manually.
+type SecretRefAttribute struct {
+	Category     config.ComponentCategory
+	Type         string
+	Attribute    string
+	SecretSuffix string
+	Key          string
+}
+
+var _ Migration = (*SecretRefAttribute)(nil)
+
+// Migrate implements Migration.
+func (m *SecretRefAttribute) Migrate(blk *hclwrite.Block, src *hclsyntax.Block) []*Change {
+	id, ok := componentIdentifier(blk, m.Category, m.Type)
+	if !ok {
+		return nil
+	}
+
+	attr := blk.Body().GetAttribute(m.Attribute)
+	if attr == nil || !isStringLiteral(attr.Expr()) {
+		return nil
+	}
+
+	secretName := k8s.RFC1123Name(id) + "-" + m.SecretSuffix
+
+	// The plaintext value is discarded, so the user is pointed at its
+	// location in the original file to be able to recover it.
+	loc := src.Body.Attributes[m.Attribute].Expr.Range()
+
+	blk.Body().SetAttributeRaw(m.Attribute, funcCallTokens("secret_ref", secretName, m.Key))
+
+	return []*Change{{
+		Block:       m.Category.String() + "." + id,
+		Description: fmt.Sprintf("Replaced the plaintext value of %q with a secret reference.", m.Attribute),
+		Action: fmt.Sprintf("Create the Kubernetes Secret %q with the key %q set to the plaintext value "+
+			"of %q which was removed from %s:%d.", secretName, m.Key, m.Attribute, loc.Filename, loc.Start.Line),
+	}}
+}
+
+// componentIdentifier returns the identifier of the given block if it
+// describes a component of the given category and type.
+func componentIdentifier(blk *hclwrite.Block, cat config.ComponentCategory, typ string) (string, bool) {
+	if blk.Type() != cat.String() {
+		return "", false
+	}
+
+	lbls := blk.Labels()
+	if len(lbls) != 2 || lbls[0] != typ {
+		return "", false
+	}
+
+	return lbls[1], true
+}
+
+// isStringLiteral returns whether the given expression is a literal string,
+// without template interpolations or directives.
+func isStringLiteral(expr *hclwrite.Expression) bool {
+	e, diags := hclsyntax.ParseExpression(expr.BuildTokens(nil).Bytes(), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return false
+	}
+
+	tmpl, ok := e.(*hclsyntax.TemplateExpr)
+	return ok && tmpl.IsStringLiteral()
+}
+
+// funcCallTokens returns the tokens of a call to the function fn with the
+// given string arguments.
+func funcCallTokens(fn string, args ...string) hclwrite.Tokens {
+	toks := hclwrite.Tokens{
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(fn), SpacesBefore: 1},
+		{Type: hclsyntax.TokenOParen, Bytes: []byte("(")},
+	}
+
+	for i, a := range args {
+		argToks := hclwrite.TokensForValue(cty.StringVal(a))
+		if i > 0 {
+			toks = append(toks, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+			argToks[0].SpacesBefore = 1
+		}
+		toks = append(toks, argToks...)
+	}
+
+	return append(toks, &hclwrite.Token{Type: hclsyntax.TokenCParen, Bytes: []byte(")")})
+}
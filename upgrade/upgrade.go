@@ -0,0 +1,64 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// Rewrite applies the given migrations to the top-level blocks of the Bridge
+// description contained in src, and returns the rewritten description along
+// with the changes that were made.
+func Rewrite(src []byte, filename string, migs []Migration) ([]byte, []*Change, hcl.Diagnostics) {
+	f, diags := hclwrite.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	// Both parsers accept the same syntax, and list top-level blocks in
+	// their order of appearance in the source.
+	synf, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, diags
+	}
+	synBlocks := synf.Body.(*hclsyntax.Body).Blocks
+
+	var changes []*Change
+
+	for i, blk := range f.Body().Blocks() {
+		for _, m := range migs {
+			changes = append(changes, m.Migrate(blk, synBlocks[i])...)
+		}
+	}
+
+	if len(changes) == 0 {
+		return src, nil, diags
+	}
+
+	// File.Bytes would reformat the entire file, whereas writing the raw
+	// tokens leaves the parts of the file which weren't migrated untouched.
+	var out bytes.Buffer
+	if _, err := f.BuildTokens(nil).WriteTo(&out); err != nil {
+		return nil, nil, diags.Append(writeDiagnostic(filename, err))
+	}
+
+	return out.Bytes(), changes, diags
+}
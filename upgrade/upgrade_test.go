@@ -0,0 +1,154 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"til/config"
+	. "til/upgrade"
+)
+
+func TestRewrite(t *testing.T) {
+	migs := []Migration{
+		&RenamedType{
+			Category: config.CategoryTargets,
+			From:     "old_type",
+			To:       "new_type",
+		},
+		&RenamedAttribute{
+			Category: config.CategoryTargets,
+			Type:     "new_type",
+			From:     "old_attr",
+			To:       "new_attr",
+		},
+		&SecretRefAttribute{
+			Category:     config.CategorySources,
+			Type:         "some_type",
+			Attribute:    "password",
+			SecretSuffix: "creds",
+			Key:          "pwd",
+		},
+	}
+
+	testCases := map[string]struct {
+		in            string
+		expectOut     string
+		expectChanges []*Change
+	}{
+		"renamed type and attribute": {
+			in: "target old_type my_target {\n" +
+				"  # some comment\n" +
+				"  old_attr = 42 # another comment\n" +
+				"}\n",
+			expectOut: "target new_type my_target {\n" +
+				"  # some comment\n" +
+				"  new_attr = 42 # another comment\n" +
+				"}\n",
+			expectChanges: []*Change{{
+				Block:       "target.my_target",
+				Description: `Renamed component type "old_type" to "new_type".`,
+			}, {
+				Block:       "target.my_target",
+				Description: `Renamed attribute "old_attr" to "new_attr".`,
+			}},
+		},
+		"renamed attribute superseded by new attribute": {
+			in: "target \"new_type\" \"my_target\" {\n" +
+				"  old_attr = 1\n" +
+				"  new_attr = 2\n" +
+				"}\n",
+			expectOut: "target \"new_type\" \"my_target\" {\n" +
+				"  old_attr = 1\n" +
+				"  new_attr = 2\n" +
+				"}\n",
+			expectChanges: []*Change{{
+				Block:       "target.my_target",
+				Description: `Left the deprecated attribute "old_attr" untouched.`,
+				Action:      `Remove the deprecated attribute "old_attr", which is superseded by "new_attr".`,
+			}},
+		},
+		"plaintext password": {
+			in: "source some_type \"my_source\" {\n" +
+				"  username   = \"me\"\n" +
+				"  password   = \"s3cr3t\"\n" +
+				"}\n",
+			expectOut: "source some_type \"my_source\" {\n" +
+				"  username   = \"me\"\n" +
+				"  password   = secret_ref(\"my-source-creds\", \"pwd\")\n" +
+				"}\n",
+			expectChanges: []*Change{{
+				Block:       "source.my_source",
+				Description: `Replaced the plaintext value of "password" with a secret reference.`,
+				Action: `Create the Kubernetes Secret "my-source-creds" with the key "pwd" ` +
+					`set to the plaintext value of "password" which was removed from test.brg.hcl:3.`,
+			}},
+		},
+		"password which is already a secret reference": {
+			in: "source some_type \"my_source\" {\n" +
+				"  password = secret_ref(\"my-secret\", \"password\")\n" +
+				"}\n",
+			expectOut: "source some_type \"my_source\" {\n" +
+				"  password = secret_ref(\"my-secret\", \"password\")\n" +
+				"}\n",
+			expectChanges: nil,
+		},
+		"templated password": {
+			in: "source some_type \"my_source\" {\n" +
+				"  password = \"${var.password}\"\n" +
+				"}\n",
+			expectOut: "source some_type \"my_source\" {\n" +
+				"  password = \"${var.password}\"\n" +
+				"}\n",
+			expectChanges: nil,
+		},
+		"unrelated component type": {
+			in: "source other_type \"my_source\" {\n" +
+				"  password = \"s3cr3t\"\n" +
+				"}\n",
+			expectOut: "source other_type \"my_source\" {\n" +
+				"  password = \"s3cr3t\"\n" +
+				"}\n",
+			expectChanges: nil,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			out, changes, diags := Rewrite([]byte(tc.in), "test.brg.hcl", migs)
+			if diags.HasErrors() {
+				t.Fatal("Unexpected diagnostics:", diags)
+			}
+
+			if diff := cmp.Diff(tc.expectOut, string(out)); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+			if diff := cmp.Diff(tc.expectChanges, changes); diff != "" {
+				t.Error("Unexpected diff: (-:expect, +:got)", diff)
+			}
+		})
+	}
+}
+
+func TestRewriteInvalidSyntax(t *testing.T) {
+	_, _, diags := Rewrite([]byte("source {"), "test.brg.hcl", Builtin)
+	if !diags.HasErrors() {
+		t.Error("Expected syntax errors")
+	}
+}
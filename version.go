@@ -0,0 +1,45 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"til/cli"
+	"til/version"
+)
+
+type VersionCommand struct{}
+
+// Run implements cli.Command.
+func (c *VersionCommand) Run(ctx context.Context, args []string) error {
+	flagSet := cli.FlagSetFromContext(ctx)
+	setUsageFn(flagSet, usageVersion)
+
+	_ = flagSet.Parse(args) // ignore err; the FlagSet uses ExitOnError
+
+	if flagSet.NArg() > 0 {
+		return fmt.Errorf("unexpected number of positional arguments.\n\n%s", usageVersion(flagSet.Name()))
+	}
+
+	fmt.Fprintf(cli.UIFromContext(ctx).StdWriter, "til version %s %s/%s\n",
+		version.Version, runtime.GOOS, runtime.GOARCH)
+
+	return nil
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported constraint operators.
+const (
+	opEqual          = "="
+	opNotEqual       = "!="
+	opGreater        = ">"
+	opGreaterOrEqual = ">="
+	opLess           = "<"
+	opLessOrEqual    = "<="
+	opPessimistic    = "~>"
+)
+
+// operators lists the supported operators, longest first so that operators
+// which are prefixes of others are matched last.
+var operators = []string{
+	opNotEqual, opGreaterOrEqual, opLessOrEqual, opPessimistic,
+	opEqual, opGreater, opLess,
+}
+
+// Constraints is a set of version constraints which must all be satisfied,
+// e.g. ">= 1.2, < 2.0".
+type Constraints []*constraint
+
+// constraint is a single version constraint, e.g. ">= 1.2".
+type constraint struct {
+	op  string
+	ver Number
+}
+
+// ParseConstraints parses a comma-separated list of version constraints.
+// Each constraint is composed of an optional operator, which defaults to "=",
+// followed by a version number.
+//
+// The pessimistic operator "~>" allows only the rightmost specified segment
+// to increase, e.g. "~> 1.2" is equivalent to ">= 1.2, < 2.0" and "~> 1.2.3"
+// is equivalent to ">= 1.2.3, < 1.3.0".
+func ParseConstraints(s string) (Constraints, error) {
+	var cs Constraints
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty constraint")
+		}
+
+		op := opEqual
+		for _, o := range operators {
+			if strings.HasPrefix(part, o) {
+				op = o
+				part = part[len(o):]
+				break
+			}
+		}
+
+		ver, err := ParseNumber(part)
+		if err != nil {
+			return nil, err
+		}
+		if op == opPessimistic && ver.specified < 2 {
+			return nil, fmt.Errorf("the %q operator requires at least a major and minor version", opPessimistic)
+		}
+
+		cs = append(cs, &constraint{op: op, ver: ver})
+	}
+
+	return cs, nil
+}
+
+// Check returns whether the given version number satisfies all constraints.
+func (cs Constraints) Check(n Number) bool {
+	for _, c := range cs {
+		if !c.check(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements fmt.Stringer.
+func (cs Constraints) String() string {
+	strs := make([]string, len(cs))
+	for i, c := range cs {
+		strs[i] = c.op + " " + c.ver.String()
+	}
+	return strings.Join(strs, ", ")
+}
+
+// check returns whether the given version number satisfies the constraint.
+func (c *constraint) check(n Number) bool {
+	cmp := n.Compare(c.ver)
+
+	switch c.op {
+	case opEqual:
+		return cmp == 0
+	case opNotEqual:
+		return cmp != 0
+	case opGreater:
+		return cmp > 0
+	case opGreaterOrEqual:
+		return cmp >= 0
+	case opLess:
+		return cmp < 0
+	case opLessOrEqual:
+		return cmp <= 0
+	case opPessimistic:
+		return cmp >= 0 && n.Compare(c.upperBound()) < 0
+	}
+
+	return false
+}
+
+// upperBound returns the exclusive upper bound of a pessimistic constraint.
+func (c *constraint) upperBound() Number {
+	var ub Number
+
+	// the segment before the rightmost specified one is incremented
+	i := c.ver.specified - 2
+	copy(ub.Segments[:i], c.ver.Segments[:i])
+	ub.Segments[i] = c.ver.Segments[i] + 1
+
+	return ub
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version_test
+
+import (
+	"testing"
+
+	. "til/version"
+)
+
+func TestConstraints(t *testing.T) {
+	testCases := map[string]struct {
+		constraints string
+		version     string
+		expect      bool
+	}{
+		"implicit equality": {
+			constraints: "1.2.3",
+			version:     "1.2.3",
+			expect:      true,
+		},
+		"inequality": {
+			constraints: "!= 1.2.3",
+			version:     "1.2.3",
+			expect:      false,
+		},
+		"range satisfied": {
+			constraints: ">= 1.2, < 2.0",
+			version:     "1.9.9",
+			expect:      true,
+		},
+		"range not satisfied": {
+			constraints: ">= 1.2, < 2.0",
+			version:     "2.0.0",
+			expect:      false,
+		},
+		"pessimistic minor": {
+			constraints: "~> 1.2",
+			version:     "1.5.0",
+			expect:      true,
+		},
+		"pessimistic minor upper bound": {
+			constraints: "~> 1.2",
+			version:     "2.0.0",
+			expect:      false,
+		},
+		"pessimistic patch": {
+			constraints: "~> 1.2.3",
+			version:     "1.2.9",
+			expect:      true,
+		},
+		"pessimistic patch upper bound": {
+			constraints: "~> 1.2.3",
+			version:     "1.3.0",
+			expect:      false,
+		},
+		"prerelease ignored": {
+			constraints: ">= 0.1.0",
+			version:     "0.1.0-dev",
+			expect:      true,
+		},
+		"v prefix": {
+			constraints: "> v0.9",
+			version:     "v1.0.0",
+			expect:      true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			cs, err := ParseConstraints(tc.constraints)
+			if err != nil {
+				t.Fatal("Failed to parse constraints:", err)
+			}
+			v, err := ParseNumber(tc.version)
+			if err != nil {
+				t.Fatal("Failed to parse version:", err)
+			}
+
+			if got := cs.Check(v); got != tc.expect {
+				t.Errorf("Expected %q to satisfy %q: %t, got %t", tc.version, tc.constraints, tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestParseConstraintsErrors(t *testing.T) {
+	testCases := map[string]string{
+		"empty":                     "",
+		"trailing comma":            ">= 1.0,",
+		"invalid segment":           ">= 1.x",
+		"too many segments":         "1.2.3.4",
+		"pessimistic without minor": "~> 1",
+		"unknown operator":          "=> 1.0",
+	}
+
+	for n, constraints := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if _, err := ParseConstraints(constraints); err == nil {
+				t.Errorf("Expected constraints %q to be invalid", constraints)
+			}
+		})
+	}
+}
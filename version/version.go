@@ -0,0 +1,109 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of the TriggerMesh Integration Language interpreter.
+// It is meant to be overridden at build time using the linker flag
+//
+//	-X til/version.Version=<version>
+var Version = "0.1.0-dev"
+
+// Number is a parsed version number, e.g. "1.2.3-rc.1".
+type Number struct {
+	// Major, minor and patch numbers. Omitted segments are zero.
+	Segments [3]int
+	// Pre-release suffix, without the leading "-".
+	Prerelease string
+
+	// number of segments that were explicitly specified
+	specified int
+}
+
+// Current returns the parsed version of the interpreter.
+//
+// An error is returned if Version isn't a valid version number, which can only
+// result from a build-time misconfiguration.
+func Current() (Number, error) {
+	n, err := ParseNumber(Version)
+	if err != nil {
+		return Number{}, fmt.Errorf("invalid interpreter version %q: %w", Version, err)
+	}
+	return n, nil
+}
+
+// ParseNumber parses a version number of the form MAJOR[.MINOR[.PATCH]][-PRE].
+// An optional "v" prefix is accepted.
+func ParseNumber(s string) (Number, error) {
+	var n Number
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	if i := strings.IndexByte(s, '-'); i != -1 {
+		s, n.Prerelease = s[:i], s[i+1:]
+		if n.Prerelease == "" {
+			return Number{}, fmt.Errorf("empty pre-release suffix")
+		}
+	}
+
+	segs := strings.Split(s, ".")
+	if len(segs) > len(n.Segments) {
+		return Number{}, fmt.Errorf("%q has more than %d segments", s, len(n.Segments))
+	}
+
+	for i, seg := range segs {
+		v, err := strconv.Atoi(seg)
+		if err != nil || v < 0 {
+			return Number{}, fmt.Errorf("%q is not a valid version segment", seg)
+		}
+		n.Segments[i] = v
+	}
+	n.specified = len(segs)
+
+	return n, nil
+}
+
+// Compare returns -1, 0 or 1 depending on whether n is lower than, equal to or
+// greater than o.
+//
+// Pre-release suffixes are ignored, so that development builds satisfy the
+// constraints that apply to the release they precede.
+func (n Number) Compare(o Number) int {
+	for i := range n.Segments {
+		switch {
+		case n.Segments[i] < o.Segments[i]:
+			return -1
+		case n.Segments[i] > o.Segments[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// String implements fmt.Stringer.
+func (n Number) String() string {
+	s := fmt.Sprintf("%d.%d.%d", n.Segments[0], n.Segments[1], n.Segments[2])
+	if n.Prerelease != "" {
+		s += "-" + n.Prerelease
+	}
+	return s
+}
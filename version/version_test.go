@@ -0,0 +1,41 @@
+/*
+Copyright 2021 TriggerMesh Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version_test
+
+import (
+	"testing"
+
+	. "til/version"
+)
+
+// Ensures that the default version of the interpreter, as well as the one set
+// at build time, is a valid version number.
+func TestCurrent(t *testing.T) {
+	if _, err := Current(); err != nil {
+		t.Error("Invalid interpreter version:", err)
+	}
+}
+
+func TestCurrentInvalid(t *testing.T) {
+	defer func(v string) { Version = v }(Version)
+
+	Version = "not-a-version"
+
+	if _, err := Current(); err == nil {
+		t.Error("Expected an error for an invalid interpreter version")
+	}
+}